@@ -2,6 +2,8 @@ package jsoniter
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"io"
 )
 
@@ -26,6 +28,21 @@ func Get(data []byte, path ...interface{}) Any {
 	return ConfigDefault.Get(data, path...)
 }
 
+// GetPointer quick method to get value referenced by RFC 6901 JSON Pointer, such as /items/0/name
+func GetPointer(data []byte, pointer string) Any {
+	return ConfigDefault.GetPointer(data, pointer)
+}
+
+// ParseDocument quick method to parse data once, for many lookups of its values
+func ParseDocument(data []byte) *Document {
+	return ConfigDefault.ParseDocument(data)
+}
+
+// MergePatchInto applies RFC 7396 JSON Merge Patch to the value v points to, in place
+func MergePatchInto(v interface{}, patch []byte) error {
+	return ConfigDefault.MergePatchInto(v, patch)
+}
+
 // Marshal adapts to json/encoding Marshal API
 //
 // Marshal returns the JSON encoding of v, adapts to json/encoding Marshal API
@@ -34,7 +51,7 @@ func Marshal(v interface{}) ([]byte, error) {
 	return ConfigDefault.Marshal(v)
 }
 
-// MarshalIndent same as json.MarshalIndent. Prefix is not supported.
+// MarshalIndent same as json.MarshalIndent.
 func MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
 	return ConfigDefault.MarshalIndent(v, prefix, indent)
 }
@@ -55,26 +72,51 @@ func NewDecoder(reader io.Reader) *Decoder {
 }
 
 // Decoder reads and decodes JSON values from an input stream.
-// Decoder provides identical APIs with json/stream Decoder (Token() and UseNumber() are in progress)
+// Decoder provides identical APIs with json/stream Decoder
 type Decoder struct {
-	iter *Iterator
+	iter       *Iterator
+	tokenState int
+	tokenStack []int
 }
 
 // Decode decode JSON into interface{}
 func (adapter *Decoder) Decode(obj interface{}) error {
+	if !adapter.tokenPrepareForDecode() {
+		return adapter.iter.Error
+	}
 	if adapter.iter.head == adapter.iter.tail && adapter.iter.reader != nil {
 		if !adapter.iter.loadMore() {
+			if isAbortError(adapter.iter.Error) {
+				return adapter.iter.Error
+			}
 			return io.EOF
 		}
 	}
 	adapter.iter.ReadVal(obj)
 	err := adapter.iter.Error
 	if err == io.EOF {
+		adapter.tokenValueEnd()
 		return nil
 	}
+	if err == nil {
+		adapter.tokenValueEnd()
+	}
 	return adapter.iter.Error
 }
 
+// DecodeContext is Decode stopping with the error of ctx once it is done.
+// ctx is checked before each read from the underlying reader, a read blocked in the reader is not interrupted.
+func (adapter *Decoder) DecodeContext(ctx context.Context, obj interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	adapter.iter.ctx = ctx
+	defer func() {
+		adapter.iter.ctx = nil
+	}()
+	return adapter.Decode(obj)
+}
+
 // More is there more?
 func (adapter *Decoder) More() bool {
 	iter := adapter.iter
@@ -89,6 +131,166 @@ func (adapter *Decoder) More() bool {
 	return c != ']' && c != '}'
 }
 
+// decoding states, same as the ones used by json.Decoder to validate Token() calls
+const (
+	tokenTopValue = iota
+	tokenArrayStart
+	tokenArrayValue
+	tokenArrayComma
+	tokenObjectStart
+	tokenObjectKey
+	tokenObjectColon
+	tokenObjectValue
+	tokenObjectComma
+)
+
+// Token returns the next JSON token in the input stream.
+// At the end of the input stream, Token returns nil, io.EOF.
+//
+// Token guarantees that the delimiters [ ] { } it returns are properly nested and matched.
+// Commas and colons are elided. Numbers are returned as float64,
+// or as json.Number if UseNumber has been called.
+// Token can be mixed with Decode and More on the same Decoder.
+func (adapter *Decoder) Token() (json.Token, error) {
+	iter := adapter.iter
+	for {
+		if iter.Error != nil && iter.Error != io.EOF {
+			return nil, iter.Error
+		}
+		c := iter.nextToken()
+		switch c {
+		case 0:
+			if iter.Error == nil || iter.Error == io.EOF {
+				return nil, io.EOF
+			}
+			return nil, iter.Error
+		case '[':
+			if !adapter.tokenValueAllowed() {
+				return adapter.tokenError(c)
+			}
+			adapter.tokenStack = append(adapter.tokenStack, adapter.tokenState)
+			adapter.tokenState = tokenArrayStart
+			return json.Delim('['), nil
+		case ']':
+			if adapter.tokenState != tokenArrayStart && adapter.tokenState != tokenArrayComma {
+				return adapter.tokenError(c)
+			}
+			adapter.tokenState = adapter.tokenStack[len(adapter.tokenStack)-1]
+			adapter.tokenStack = adapter.tokenStack[:len(adapter.tokenStack)-1]
+			adapter.tokenValueEnd()
+			return json.Delim(']'), nil
+		case '{':
+			if !adapter.tokenValueAllowed() {
+				return adapter.tokenError(c)
+			}
+			adapter.tokenStack = append(adapter.tokenStack, adapter.tokenState)
+			adapter.tokenState = tokenObjectStart
+			return json.Delim('{'), nil
+		case '}':
+			if adapter.tokenState != tokenObjectStart && adapter.tokenState != tokenObjectComma {
+				return adapter.tokenError(c)
+			}
+			adapter.tokenState = adapter.tokenStack[len(adapter.tokenStack)-1]
+			adapter.tokenStack = adapter.tokenStack[:len(adapter.tokenStack)-1]
+			adapter.tokenValueEnd()
+			return json.Delim('}'), nil
+		case ':':
+			if adapter.tokenState != tokenObjectColon {
+				return adapter.tokenError(c)
+			}
+			adapter.tokenState = tokenObjectValue
+		case ',':
+			if adapter.tokenState == tokenArrayComma {
+				adapter.tokenState = tokenArrayValue
+				continue
+			}
+			if adapter.tokenState == tokenObjectComma {
+				adapter.tokenState = tokenObjectKey
+				continue
+			}
+			return adapter.tokenError(c)
+		case '"':
+			if adapter.tokenState == tokenObjectStart || adapter.tokenState == tokenObjectKey {
+				iter.unreadByte()
+				key := iter.ReadString()
+				if iter.Error != nil && iter.Error != io.EOF {
+					return nil, iter.Error
+				}
+				adapter.tokenState = tokenObjectColon
+				return key, nil
+			}
+			fallthrough
+		default:
+			if !adapter.tokenValueAllowed() {
+				return adapter.tokenError(c)
+			}
+			iter.unreadByte()
+			val := iter.Read()
+			if iter.Error != nil && iter.Error != io.EOF {
+				return nil, iter.Error
+			}
+			adapter.tokenValueEnd()
+			return val, nil
+		}
+	}
+}
+
+func (adapter *Decoder) tokenPrepareForDecode() bool {
+	iter := adapter.iter
+	switch adapter.tokenState {
+	case tokenArrayComma:
+		c := iter.nextToken()
+		if c != ',' {
+			iter.reportUnexpected("Decode", ",", c)
+			return false
+		}
+		adapter.tokenState = tokenArrayValue
+	case tokenObjectColon:
+		c := iter.nextToken()
+		if c != ':' {
+			iter.reportUnexpected("Decode", ":", c)
+			return false
+		}
+		adapter.tokenState = tokenObjectValue
+	}
+	return true
+}
+
+func (adapter *Decoder) tokenValueAllowed() bool {
+	switch adapter.tokenState {
+	case tokenTopValue, tokenArrayStart, tokenArrayValue, tokenObjectValue:
+		return true
+	}
+	return false
+}
+
+func (adapter *Decoder) tokenValueEnd() {
+	switch adapter.tokenState {
+	case tokenArrayStart, tokenArrayValue:
+		adapter.tokenState = tokenArrayComma
+	case tokenObjectValue:
+		adapter.tokenState = tokenObjectComma
+	}
+}
+
+func (adapter *Decoder) tokenError(c byte) (json.Token, error) {
+	var context string
+	switch adapter.tokenState {
+	case tokenTopValue, tokenArrayStart, tokenArrayValue, tokenObjectValue:
+		context = "looking for beginning of value"
+	case tokenArrayComma:
+		context = "after array element"
+	case tokenObjectKey:
+		context = "looking for beginning of object key string"
+	case tokenObjectColon:
+		context = "after object key"
+	case tokenObjectComma:
+		context = "after object key:value pair"
+	}
+	adapter.iter.ReportError("Token", "invalid character "+string([]byte{c})+" "+context)
+	return nil, adapter.iter.Error
+}
+
 // Buffered remaining buffer
 func (adapter *Decoder) Buffered() io.Reader {
 	remaining := adapter.iter.buf[adapter.iter.head:adapter.iter.tail]
@@ -124,19 +326,32 @@ type Encoder struct {
 
 // Encode encode interface{} as JSON to io.Writer
 func (adapter *Encoder) Encode(val interface{}) error {
+	start := adapter.stream.Buffered()
 	adapter.stream.WriteVal(val)
+	if adapter.stream.cfg.canonical {
+		adapter.stream.canonicalize(start)
+	}
 	adapter.stream.WriteRaw("\n")
 	adapter.stream.Flush()
 	return adapter.stream.Error
 }
 
-// SetIndent set the indention. Prefix is not supported
+// SetIndent set the indention, same as json.Encoder.SetIndent.
+// Calling SetIndent("", "") disables indention.
 func (adapter *Encoder) SetIndent(prefix, indent string) {
 	config := adapter.stream.cfg.configBeforeFrozen
-	config.IndentionStep = len(indent)
+	config.IndentionStep = 0
+	config.IndentPrefix = prefix
+	config.Indent = indent
 	adapter.stream.cfg = config.frozeWithCacheReuse(adapter.stream.cfg.extraExtensions)
 }
 
+// SetFlushThreshold writes the value being encoded to io.Writer whenever threshold bytes are buffered,
+// instead of once it is complete, see Stream.SetFlushThreshold.
+func (adapter *Encoder) SetFlushThreshold(threshold int) {
+	adapter.stream.SetFlushThreshold(threshold)
+}
+
 // SetEscapeHTML escape html by default, set to false to disable
 func (adapter *Encoder) SetEscapeHTML(escapeHTML bool) {
 	config := adapter.stream.cfg.configBeforeFrozen
@@ -12,6 +12,8 @@ import (
 
 // Any generic object representation.
 // The lazy json implementation holds []byte and parse lazily.
+// Set, Delete and Append leave the receiver untouched and return the edited copy,
+// which shares the bytes of unchanged values with the receiver.
 type Any interface {
 	LastError() error
 	ValueType() ValueType
@@ -28,6 +30,10 @@ type Any interface {
 	ToString() string
 	ToVal(val interface{})
 	Get(path ...interface{}) Any
+	GetPointer(pointer string) Any
+	Set(value interface{}, path ...interface{}) Any
+	Delete(path ...interface{}) Any
+	Append(value interface{}, path ...interface{}) Any
 	Size() int
 	Keys() []string
 	GetInterface() interface{}
@@ -152,6 +158,11 @@ func (iter *Iterator) ReadAny() Any {
 
 func (iter *Iterator) readAny() Any {
 	c := iter.nextToken()
+	if iter.cfg.allowJSON5 {
+		if any := iter.readJSON5Any(c); any != nil {
+			return any
+		}
+	}
 	switch c {
 	case '"':
 		iter.unreadByte()
@@ -201,8 +212,8 @@ func (iter *Iterator) readArrayAny() Any {
 
 func locateObjectField(iter *Iterator, target string) []byte {
 	var found []byte
-	iter.ReadObjectCB(func(iter *Iterator, field string) bool {
-		if field == target {
+	iter.readObjectFieldsCB(func(iter *Iterator, field []byte) bool {
+		if string(field) == target {
 			found = iter.SkipAndReturnBytes()
 			return false
 		}
@@ -227,21 +238,29 @@ func locateArrayElement(iter *Iterator, target int) []byte {
 	return found
 }
 
+// locatePathKey resets the iterator to the value of the object field or array element key, which is string or int
+func locatePathKey(iter *Iterator, key interface{}) bool {
+	var valueBytes []byte
+	switch key := key.(type) {
+	case string:
+		valueBytes = locateObjectField(iter, key)
+	case int:
+		valueBytes = locateArrayElement(iter, key)
+	}
+	if valueBytes == nil {
+		return false
+	}
+	iter.ResetBytes(valueBytes)
+	return true
+}
+
 func locatePath(iter *Iterator, path []interface{}) Any {
 	for i, pathKeyObj := range path {
 		switch pathKey := pathKeyObj.(type) {
-		case string:
-			valueBytes := locateObjectField(iter, pathKey)
-			if valueBytes == nil {
-				return newInvalidAny(path[i:])
-			}
-			iter.ResetBytes(valueBytes)
-		case int:
-			valueBytes := locateArrayElement(iter, pathKey)
-			if valueBytes == nil {
+		case string, int:
+			if !locatePathKey(iter, pathKey) {
 				return newInvalidAny(path[i:])
 			}
-			iter.ResetBytes(valueBytes)
 		case int32:
 			if '*' == pathKey {
 				return iter.readAny().Get(path[i:]...)
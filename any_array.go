@@ -130,6 +130,22 @@ func (any *arrayLazyAny) Get(path ...interface{}) Any {
 	}
 }
 
+func (any *arrayLazyAny) GetPointer(pointer string) Any {
+	return any.cfg.GetPointer(any.buf, pointer)
+}
+
+func (any *arrayLazyAny) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *arrayLazyAny) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *arrayLazyAny) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
 func (any *arrayLazyAny) Size() int {
 	size := 0
 	iter := any.cfg.BorrowIterator(any.buf)
@@ -143,7 +159,7 @@ func (any *arrayLazyAny) Size() int {
 }
 
 func (any *arrayLazyAny) WriteTo(stream *Stream) {
-	stream.Write(any.buf)
+	stream.buf = append(stream.buf, any.buf...)
 }
 
 func (any *arrayLazyAny) GetInterface() interface{} {
@@ -265,12 +281,28 @@ func (any *arrayAny) Get(path ...interface{}) Any {
 	}
 }
 
+func (any *arrayAny) GetPointer(pointer string) Any {
+	return getPointer(any, pointer)
+}
+
+func (any *arrayAny) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *arrayAny) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *arrayAny) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
 func (any *arrayAny) Size() int {
 	return any.val.Len()
 }
 
 func (any *arrayAny) WriteTo(stream *Stream) {
-	stream.WriteVal(any.val)
+	stream.WriteVal(any.val.Interface())
 }
 
 func (any *arrayAny) GetInterface() interface{} {
@@ -64,6 +64,22 @@ func (any *trueAny) ValueType() ValueType {
 	return BoolValue
 }
 
+func (any *trueAny) GetPointer(pointer string) Any {
+	return getPointer(any, pointer)
+}
+
+func (any *trueAny) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *trueAny) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *trueAny) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
 func (any *trueAny) MustBeValid() Any {
 	return any
 }
@@ -132,6 +148,22 @@ func (any *falseAny) ValueType() ValueType {
 	return BoolValue
 }
 
+func (any *falseAny) GetPointer(pointer string) Any {
+	return getPointer(any, pointer)
+}
+
+func (any *falseAny) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *falseAny) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *falseAny) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
 func (any *falseAny) MustBeValid() Any {
 	return any
 }
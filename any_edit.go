@@ -0,0 +1,284 @@
+package jsoniter
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// editedAny is the result of Set, Delete and Append.
+// Only the containers on the edited path are expanded, other values keep their original bytes.
+type editedAny struct {
+	baseAny
+	cfg  *frozenConfig
+	root *patchNode
+	once sync.Once
+	buf  []byte
+	lazy Any
+}
+
+func (any *editedAny) materialize() Any {
+	any.once.Do(func() {
+		any.buf = any.root.bytes(any.cfg)
+		iter := any.cfg.BorrowIterator(any.buf)
+		defer any.cfg.ReturnIterator(iter)
+		any.lazy = iter.readAny()
+	})
+	return any.lazy
+}
+
+func (any *editedAny) ValueType() ValueType {
+	if any.root.raw == nil {
+		return any.root.kind
+	}
+	return valueTypes[any.root.raw[0]]
+}
+
+func (any *editedAny) MustBeValid() Any {
+	return any
+}
+
+func (any *editedAny) LastError() error {
+	return nil
+}
+
+func (any *editedAny) ToBool() bool {
+	return any.materialize().ToBool()
+}
+
+func (any *editedAny) ToInt() int {
+	return any.materialize().ToInt()
+}
+
+func (any *editedAny) ToInt32() int32 {
+	return any.materialize().ToInt32()
+}
+
+func (any *editedAny) ToInt64() int64 {
+	return any.materialize().ToInt64()
+}
+
+func (any *editedAny) ToUint() uint {
+	return any.materialize().ToUint()
+}
+
+func (any *editedAny) ToUint32() uint32 {
+	return any.materialize().ToUint32()
+}
+
+func (any *editedAny) ToUint64() uint64 {
+	return any.materialize().ToUint64()
+}
+
+func (any *editedAny) ToFloat32() float32 {
+	return any.materialize().ToFloat32()
+}
+
+func (any *editedAny) ToFloat64() float64 {
+	return any.materialize().ToFloat64()
+}
+
+func (any *editedAny) ToString() string {
+	if any.ValueType() == StringValue {
+		return any.materialize().ToString()
+	}
+	any.materialize()
+	return *(*string)(unsafe.Pointer(&any.buf))
+}
+
+func (any *editedAny) ToVal(val interface{}) {
+	any.materialize()
+	iter := any.cfg.BorrowIterator(any.buf)
+	defer any.cfg.ReturnIterator(iter)
+	iter.ReadVal(val)
+}
+
+func (any *editedAny) Get(path ...interface{}) Any {
+	return any.materialize().Get(path...)
+}
+
+func (any *editedAny) GetPointer(pointer string) Any {
+	return any.materialize().GetPointer(pointer)
+}
+
+func (any *editedAny) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *editedAny) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *editedAny) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
+func (any *editedAny) Size() int {
+	return any.materialize().Size()
+}
+
+func (any *editedAny) Keys() []string {
+	return any.materialize().Keys()
+}
+
+func (any *editedAny) WriteTo(stream *Stream) {
+	any.root.writeTo(stream)
+}
+
+func (any *editedAny) GetInterface() interface{} {
+	return any.materialize().GetInterface()
+}
+
+// patchNodeOf returns the value as patch node, sharing the bytes of lazy values
+func patchNodeOf(any Any) (*frozenConfig, *patchNode, error) {
+	switch any := any.(type) {
+	case *editedAny:
+		return any.cfg, any.root, nil
+	case *objectLazyAny:
+		return any.cfg, &patchNode{raw: any.buf}, nil
+	case *arrayLazyAny:
+		return any.cfg, &patchNode{raw: any.buf}, nil
+	case *numberLazyAny:
+		return any.cfg, &patchNode{raw: any.buf}, nil
+	case *tapeAny:
+		return any.doc.cfg, &patchNode{raw: any.doc.raw(any.index)}, nil
+	case *invalidAny:
+		return nil, nil, any.LastError()
+	}
+	cfg := ConfigDefault.(*frozenConfig)
+	data, err := cfg.Marshal(any)
+	return cfg, &patchNode{raw: data}, err
+}
+
+func (cfg *frozenConfig) patchNodeOfValue(value interface{}) (*patchNode, error) {
+	if any, isAny := value.(Any); isAny {
+		_, node, err := patchNodeOf(any)
+		return node, err
+	}
+	data, err := cfg.Marshal(value)
+	return &patchNode{raw: data}, err
+}
+
+func (node *patchNode) clone() *patchNode {
+	clone := &patchNode{
+		raw:      node.raw,
+		kind:     node.kind,
+		fields:   append([]string(nil), node.fields...),
+		members:  append([]*patchNode(nil), node.members...),
+		elements: append([]*patchNode(nil), node.elements...),
+	}
+	if node.index != nil {
+		clone.index = make(map[string]int, len(node.index))
+		for field, i := range node.index {
+			clone.index[field] = i
+		}
+	}
+	return clone
+}
+
+// editAny copies the containers from the root to the value at path, and applies edit on the copied value
+func editAny(any Any, path []interface{}, edit func(cfg *frozenConfig, node *patchNode) error) Any {
+	cfg, root, err := patchNodeOf(any)
+	if err != nil {
+		return &invalidAny{baseAny{}, err}
+	}
+	root = root.clone()
+	node := root
+	for i, key := range path {
+		var slot **patchNode
+		switch key := key.(type) {
+		case string:
+			if node.expand(cfg) == ObjectValue {
+				if index := node.fieldIndex(key); index != -1 {
+					slot = &node.members[index]
+				}
+			}
+		case int:
+			if node.expand(cfg) == ArrayValue && key >= 0 && key < len(node.elements) {
+				slot = &node.elements[key]
+			}
+		}
+		if slot == nil {
+			return newInvalidAny(path[:i+1])
+		}
+		*slot = (*slot).clone()
+		node = *slot
+	}
+	if err := edit(cfg, node); err != nil {
+		return &invalidAny{baseAny{}, err}
+	}
+	return &editedAny{cfg: cfg, root: root}
+}
+
+// setAny sets the object member or replaces the array element at path
+func setAny(any Any, value interface{}, path []interface{}) Any {
+	if len(path) == 0 {
+		return Wrap(value)
+	}
+	last := path[len(path)-1]
+	return editAny(any, path[:len(path)-1], func(cfg *frozenConfig, node *patchNode) error {
+		valueNode, err := cfg.patchNodeOfValue(value)
+		if err != nil {
+			return err
+		}
+		switch key := last.(type) {
+		case string:
+			if node.expand(cfg) != ObjectValue {
+				return fmt.Errorf("set %v on non object value", path)
+			}
+			node.setMember(key, valueNode)
+			return nil
+		case int:
+			if node.expand(cfg) != ArrayValue {
+				return fmt.Errorf("set %v on non array value", path)
+			}
+			if key < 0 || key >= len(node.elements) {
+				return fmt.Errorf("set %v out of array bounds", path)
+			}
+			node.elements[key] = valueNode
+			return nil
+		}
+		return fmt.Errorf("set %v with invalid path element", path)
+	})
+}
+
+// deleteAny removes the object member or the array element at path
+func deleteAny(any Any, path []interface{}) Any {
+	if len(path) == 0 {
+		return &invalidAny{baseAny{}, errors.New("can not delete the whole value")}
+	}
+	last := path[len(path)-1]
+	return editAny(any, path[:len(path)-1], func(cfg *frozenConfig, node *patchNode) error {
+		switch key := last.(type) {
+		case string:
+			if node.expand(cfg) == ObjectValue {
+				if index := node.fieldIndex(key); index != -1 {
+					node.removeMember(index)
+					return nil
+				}
+			}
+		case int:
+			if node.expand(cfg) == ArrayValue && key >= 0 && key < len(node.elements) {
+				node.elements = append(node.elements[:key], node.elements[key+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("%v not found", path)
+	})
+}
+
+// appendAny appends value to the array at path
+func appendAny(any Any, value interface{}, path []interface{}) Any {
+	return editAny(any, path, func(cfg *frozenConfig, node *patchNode) error {
+		if node.expand(cfg) != ArrayValue {
+			return fmt.Errorf("append to non array value at %v", path)
+		}
+		valueNode, err := cfg.patchNodeOfValue(value)
+		if err != nil {
+			return err
+		}
+		node.elements = append(node.elements, valueNode)
+		return nil
+	})
+}
@@ -17,6 +17,22 @@ func (any *floatAny) ValueType() ValueType {
 	return NumberValue
 }
 
+func (any *floatAny) GetPointer(pointer string) Any {
+	return getPointer(any, pointer)
+}
+
+func (any *floatAny) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *floatAny) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *floatAny) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
 func (any *floatAny) MustBeValid() Any {
 	return any
 }
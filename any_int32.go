@@ -17,6 +17,22 @@ func (any *int32Any) ValueType() ValueType {
 	return NumberValue
 }
 
+func (any *int32Any) GetPointer(pointer string) Any {
+	return getPointer(any, pointer)
+}
+
+func (any *int32Any) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *int32Any) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *int32Any) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
 func (any *int32Any) MustBeValid() Any {
 	return any
 }
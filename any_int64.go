@@ -17,6 +17,22 @@ func (any *int64Any) ValueType() ValueType {
 	return NumberValue
 }
 
+func (any *int64Any) GetPointer(pointer string) Any {
+	return getPointer(any, pointer)
+}
+
+func (any *int64Any) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *int64Any) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *int64Any) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
 func (any *int64Any) MustBeValid() Any {
 	return any
 }
@@ -73,6 +73,22 @@ func (any *invalidAny) Get(path ...interface{}) Any {
 	return &invalidAny{baseAny{}, fmt.Errorf("%v, get %v from invalid", any.err, path)}
 }
 
+func (any *invalidAny) GetPointer(pointer string) Any {
+	return getPointer(any, pointer)
+}
+
+func (any *invalidAny) Set(value interface{}, path ...interface{}) Any {
+	return any
+}
+
+func (any *invalidAny) Delete(path ...interface{}) Any {
+	return any
+}
+
+func (any *invalidAny) Append(value interface{}, path ...interface{}) Any {
+	return any
+}
+
 func (any *invalidAny) Parse() *Iterator {
 	return nil
 }
@@ -12,6 +12,22 @@ func (any *nilAny) ValueType() ValueType {
 	return NilValue
 }
 
+func (any *nilAny) GetPointer(pointer string) Any {
+	return getPointer(any, pointer)
+}
+
+func (any *nilAny) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *nilAny) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *nilAny) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
 func (any *nilAny) MustBeValid() Any {
 	return any
 }
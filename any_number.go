@@ -16,6 +16,22 @@ func (any *numberLazyAny) ValueType() ValueType {
 	return NumberValue
 }
 
+func (any *numberLazyAny) GetPointer(pointer string) Any {
+	return getPointer(any, pointer)
+}
+
+func (any *numberLazyAny) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *numberLazyAny) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *numberLazyAny) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
 func (any *numberLazyAny) MustBeValid() Any {
 	return any
 }
@@ -113,7 +129,7 @@ func (any *numberLazyAny) ToString() string {
 }
 
 func (any *numberLazyAny) WriteTo(stream *Stream) {
-	stream.Write(any.buf)
+	stream.buf = append(stream.buf, any.buf...)
 }
 
 func (any *numberLazyAny) GetInterface() interface{} {
@@ -104,6 +104,22 @@ func (any *objectLazyAny) Get(path ...interface{}) Any {
 	}
 }
 
+func (any *objectLazyAny) GetPointer(pointer string) Any {
+	return any.cfg.GetPointer(any.buf, pointer)
+}
+
+func (any *objectLazyAny) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *objectLazyAny) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *objectLazyAny) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
 func (any *objectLazyAny) Keys() []string {
 	keys := []string{}
 	iter := any.cfg.BorrowIterator(any.buf)
@@ -129,7 +145,7 @@ func (any *objectLazyAny) Size() int {
 }
 
 func (any *objectLazyAny) WriteTo(stream *Stream) {
-	stream.Write(any.buf)
+	stream.buf = append(stream.buf, any.buf...)
 }
 
 func (any *objectLazyAny) GetInterface() interface{} {
@@ -237,6 +253,22 @@ func (any *objectAny) Get(path ...interface{}) Any {
 	}
 }
 
+func (any *objectAny) GetPointer(pointer string) Any {
+	return getPointer(any, pointer)
+}
+
+func (any *objectAny) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *objectAny) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *objectAny) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
 func (any *objectAny) Keys() []string {
 	keys := make([]string, 0, any.val.NumField())
 	for i := 0; i < any.val.NumField(); i++ {
@@ -250,7 +282,7 @@ func (any *objectAny) Size() int {
 }
 
 func (any *objectAny) WriteTo(stream *Stream) {
-	stream.WriteVal(any.val)
+	stream.WriteVal(any.val.Interface())
 }
 
 func (any *objectAny) GetInterface() interface{} {
@@ -349,10 +381,29 @@ func (any *mapAny) Get(path ...interface{}) Any {
 		if !value.IsValid() {
 			return newInvalidAny(path)
 		}
-		return Wrap(value.Interface())
+		if len(path) == 1 {
+			return Wrap(value.Interface())
+		}
+		return Wrap(value.Interface()).Get(path[1:]...)
 	}
 }
 
+func (any *mapAny) GetPointer(pointer string) Any {
+	return getPointer(any, pointer)
+}
+
+func (any *mapAny) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *mapAny) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *mapAny) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
 func (any *mapAny) Keys() []string {
 	keys := make([]string, 0, any.val.Len())
 	for _, key := range any.val.MapKeys() {
@@ -366,7 +417,7 @@ func (any *mapAny) Size() int {
 }
 
 func (any *mapAny) WriteTo(stream *Stream) {
-	stream.WriteVal(any.val)
+	stream.WriteVal(any.val.Interface())
 }
 
 func (any *mapAny) GetInterface() interface{} {
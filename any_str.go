@@ -17,6 +17,22 @@ func (any *stringAny) Get(path ...interface{}) Any {
 	return &invalidAny{baseAny{}, fmt.Errorf("GetIndex %v from simple value", path)}
 }
 
+func (any *stringAny) GetPointer(pointer string) Any {
+	return getPointer(any, pointer)
+}
+
+func (any *stringAny) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *stringAny) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *stringAny) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
 func (any *stringAny) Parse() *Iterator {
 	return nil
 }
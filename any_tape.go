@@ -0,0 +1,193 @@
+package jsoniter
+
+import (
+	"unsafe"
+)
+
+// tapeAny is a value of Document, containers are navigated with the tape,
+// scalars are converted like the lazy values read from bytes
+type tapeAny struct {
+	baseAny
+	doc   *Document
+	index uint32
+}
+
+func (any *tapeAny) entry() *tapeEntry {
+	return &any.doc.tape[any.index]
+}
+
+func (any *tapeAny) lazy() Any {
+	iter := any.doc.cfg.BorrowIterator(any.doc.raw(any.index))
+	defer any.doc.cfg.ReturnIterator(iter)
+	return iter.readAny()
+}
+
+func (any *tapeAny) ValueType() ValueType {
+	return any.entry().kind
+}
+
+func (any *tapeAny) MustBeValid() Any {
+	return any
+}
+
+func (any *tapeAny) LastError() error {
+	return nil
+}
+
+func (any *tapeAny) ToBool() bool {
+	switch any.entry().kind {
+	case ObjectValue:
+		return true
+	case ArrayValue:
+		return any.entry().count != 0
+	}
+	return any.lazy().ToBool()
+}
+
+func (any *tapeAny) ToInt() int {
+	return any.lazy().ToInt()
+}
+
+func (any *tapeAny) ToInt32() int32 {
+	return any.lazy().ToInt32()
+}
+
+func (any *tapeAny) ToInt64() int64 {
+	return any.lazy().ToInt64()
+}
+
+func (any *tapeAny) ToUint() uint {
+	return any.lazy().ToUint()
+}
+
+func (any *tapeAny) ToUint32() uint32 {
+	return any.lazy().ToUint32()
+}
+
+func (any *tapeAny) ToUint64() uint64 {
+	return any.lazy().ToUint64()
+}
+
+func (any *tapeAny) ToFloat32() float32 {
+	return any.lazy().ToFloat32()
+}
+
+func (any *tapeAny) ToFloat64() float64 {
+	return any.lazy().ToFloat64()
+}
+
+func (any *tapeAny) ToString() string {
+	switch any.entry().kind {
+	case StringValue:
+		return any.doc.key(any.index)
+	case ObjectValue, ArrayValue:
+		raw := any.doc.raw(any.index)
+		return *(*string)(unsafe.Pointer(&raw))
+	}
+	return any.lazy().ToString()
+}
+
+func (any *tapeAny) ToVal(val interface{}) {
+	iter := any.doc.cfg.BorrowIterator(any.doc.raw(any.index))
+	defer any.doc.cfg.ReturnIterator(iter)
+	iter.ReadVal(val)
+}
+
+func (any *tapeAny) child(index uint32) *tapeAny {
+	return &tapeAny{doc: any.doc, index: index}
+}
+
+func (any *tapeAny) Get(path ...interface{}) Any {
+	if len(path) == 0 {
+		return any
+	}
+	entry := any.entry()
+	switch firstPath := path[0].(type) {
+	case string:
+		if entry.kind != ObjectValue {
+			break
+		}
+		if member, found := any.doc.member(any.index, firstPath); found {
+			return any.child(member).Get(path[1:]...)
+		}
+	case int:
+		if entry.kind != ArrayValue || firstPath < 0 || firstPath >= int(entry.count) {
+			break
+		}
+		return any.child(any.doc.children[int(entry.first)+firstPath]).Get(path[1:]...)
+	case int32:
+		if '*' != firstPath {
+			break
+		}
+		switch entry.kind {
+		case ObjectValue:
+			mappedAll := map[string]Any{}
+			members := any.doc.children[entry.first : entry.first+2*entry.count]
+			for i := 0; i < len(members); i += 2 {
+				mapped := any.child(members[i+1]).Get(path[1:]...)
+				if mapped.ValueType() != InvalidValue {
+					mappedAll[any.doc.key(members[i])] = mapped
+				}
+			}
+			return wrapMap(mappedAll)
+		case ArrayValue:
+			arr := make([]Any, 0)
+			for _, element := range any.doc.children[entry.first : entry.first+entry.count] {
+				found := any.child(element).Get(path[1:]...)
+				if found.ValueType() != InvalidValue {
+					arr = append(arr, found)
+				}
+			}
+			return wrapArray(arr)
+		}
+	}
+	return newInvalidAny(path)
+}
+
+func (any *tapeAny) GetPointer(pointer string) Any {
+	return getPointer(any, pointer)
+}
+
+func (any *tapeAny) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *tapeAny) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *tapeAny) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
+func (any *tapeAny) Size() int {
+	entry := any.entry()
+	switch entry.kind {
+	case ObjectValue, ArrayValue:
+		return int(entry.count)
+	}
+	return 0
+}
+
+func (any *tapeAny) Keys() []string {
+	entry := any.entry()
+	keys := []string{}
+	if entry.kind != ObjectValue {
+		return keys
+	}
+	members := any.doc.children[entry.first : entry.first+2*entry.count]
+	for i := 0; i < len(members); i += 2 {
+		keys = append(keys, any.doc.key(members[i]))
+	}
+	return keys
+}
+
+func (any *tapeAny) WriteTo(stream *Stream) {
+	stream.buf = append(stream.buf, any.doc.raw(any.index)...)
+}
+
+func (any *tapeAny) GetInterface() interface{} {
+	iter := any.doc.cfg.BorrowIterator(any.doc.raw(any.index))
+	defer any.doc.cfg.ReturnIterator(iter)
+	return iter.Read()
+}
@@ -0,0 +1,176 @@
+package any_tests
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+const documentInput = `{
+	"name": "doc",
+	"esc\"aped": "a\nb",
+	"items": [
+		{"id": 1, "price": 1.5, "tags": ["x", "y"]},
+		{"id": 2, "price": -3e2, "tags": []},
+		{"id": 3, "ok": true, "none": null}
+	],
+	"nested": {"a": {"b": {"c": [10, 20, 30]}}},
+	"empty": {}
+}`
+
+func Test_document_same_as_lazy_any(t *testing.T) {
+	should := require.New(t)
+	doc := jsoniter.ParseDocument([]byte(documentInput))
+	should.NoError(doc.LastError())
+	lazy := jsoniter.Get([]byte(documentInput))
+	paths := [][]interface{}{
+		{},
+		{"name"},
+		{`esc"aped`},
+		{"items"},
+		{"items", 0},
+		{"items", 0, "price"},
+		{"items", 1, "price"},
+		{"items", 0, "tags", 1},
+		{"items", 2, "ok"},
+		{"items", 2, "none"},
+		{"items", '*', "id"},
+		{"items", '*', "tags", 0},
+		{"nested", "a", "b", "c", 2},
+		{"nested", '*'},
+		{"empty"},
+		{"items", 3},
+		{"items", -1},
+		{"missing"},
+		{"name", "x"},
+		{"items", "x"},
+	}
+	for _, path := range paths {
+		expected := lazy.Get(path...)
+		actual := doc.Get(path...)
+		should.Equal(expected.ValueType(), actual.ValueType(), "%v", path)
+		if expected.ValueType() == jsoniter.InvalidValue {
+			continue
+		}
+		should.Equal(expected.ToString(), actual.ToString(), "%v", path)
+		should.Equal(expected.ToInt(), actual.ToInt(), "%v", path)
+		should.Equal(expected.ToFloat64(), actual.ToFloat64(), "%v", path)
+		should.Equal(expected.ToBool(), actual.ToBool(), "%v", path)
+		should.Equal(expected.Size(), actual.Size(), "%v", path)
+		should.Equal(expected.Keys(), actual.Keys(), "%v", path)
+		wildcard := false
+		for _, key := range path {
+			wildcard = wildcard || key == int32('*')
+		}
+		if !wildcard {
+			// the values collected by '*' are wrapped in Any
+			should.Equal(expected.GetInterface(), actual.GetInterface(), "%v", path)
+		}
+		expectedJSON, err := jsoniter.Marshal(expected)
+		should.NoError(err)
+		actualJSON, err := jsoniter.Marshal(actual)
+		should.NoError(err)
+		should.Equal(string(expectedJSON), string(actualJSON), "%v", path)
+	}
+	should.Equal(30, doc.GetPointer("/nested/a/b/c/2").ToInt())
+	should.Equal("a\nb", doc.GetPointer("/esc\"aped").ToString())
+	should.Equal(jsoniter.InvalidValue, doc.GetPointer("/items/-").ValueType())
+	var item struct {
+		ID   int
+		Tags []string
+	}
+	doc.Get("items", 0).ToVal(&item)
+	should.Equal(1, item.ID)
+	should.Equal([]string{"x", "y"}, item.Tags)
+}
+
+func Test_document_edit(t *testing.T) {
+	should := require.New(t)
+	doc := jsoniter.ParseDocument([]byte(`{"a":[1,2],"b":{"c":"d"}}`))
+	edited := doc.Root().Set(3, "a", 1).Append(4, "a").Delete("b")
+	should.Equal(`{"a":[1,3,4]}`, edited.ToString())
+	should.Equal(`{"a":[1,2],"b":{"c":"d"}}`, doc.Root().ToString())
+}
+
+func Test_document_invalid(t *testing.T) {
+	should := require.New(t)
+	inputs := []string{
+		``,
+		`{`,
+		`{"a"}`,
+		`{"a":1,}`,
+		`[1,2`,
+		`[1 2]`,
+		`{"a":tru}`,
+		`"abc`,
+		`{} {}`,
+		`{"a":01}`,
+		strings.Repeat("[", 20000) + strings.Repeat("]", 20000),
+	}
+	for _, input := range inputs {
+		doc := jsoniter.ParseDocument([]byte(input))
+		should.Error(doc.LastError(), input)
+		should.Equal(jsoniter.InvalidValue, doc.Root().ValueType(), input)
+		should.Equal(jsoniter.InvalidValue, doc.Get("a").ValueType(), input)
+	}
+}
+
+func Test_document_large_array(t *testing.T) {
+	should := require.New(t)
+	elements := make([]string, 10000)
+	for i := range elements {
+		elements[i] = `{"n":` + strconv.Itoa(i) + `}`
+	}
+	doc := jsoniter.ConfigCompatibleWithStandardLibrary.ParseDocument([]byte("[" + strings.Join(elements, ",") + "]"))
+	should.NoError(doc.LastError())
+	root := doc.Root()
+	should.Equal(10000, root.Size())
+	for _, i := range []int{0, 1, 5000, 9999} {
+		should.Equal(i, root.Get(i, "n").ToInt())
+	}
+}
+
+func Test_document_large_object(t *testing.T) {
+	should := require.New(t)
+	members := []string{`"k\u0022q":"escaped"`}
+	for i := 99; i >= 0; i-- {
+		members = append(members, `"k`+strconv.Itoa(i)+`":`+strconv.Itoa(i))
+	}
+	members = append(members, `"k5":"duplicate"`)
+	data := []byte("{" + strings.Join(members, ",") + "}")
+	doc := jsoniter.ParseDocument(data)
+	should.NoError(doc.LastError())
+	lazy := jsoniter.Get(data)
+	for i := 0; i < 100; i++ {
+		key := "k" + strconv.Itoa(i)
+		should.Equal(i, doc.Get(key).ToInt(), key)
+		should.Equal(lazy.Get(key).ToInt(), doc.Get(key).ToInt(), key)
+	}
+	should.Equal("escaped", doc.Get(`k"q`).ToString())
+	should.Equal(jsoniter.InvalidValue, doc.Get("k100").ValueType())
+	should.Equal(jsoniter.InvalidValue, doc.Get("a").ValueType())
+	should.Equal(102, doc.Root().Size())
+}
+
+func Benchmark_document_get(b *testing.B) {
+	elements := make([]string, 1000)
+	for i := range elements {
+		elements[i] = `{"n":` + strconv.Itoa(i) + `,"s":"` + strings.Repeat("x", 50) + `"}`
+	}
+	data := []byte("[" + strings.Join(elements, ",") + "]")
+	b.Run("lazy", func(b *testing.B) {
+		any := jsoniter.Get(data)
+		for i := 0; i < b.N; i++ {
+			any.Get(i%1000, "n").ToInt()
+		}
+	})
+	b.Run("document", func(b *testing.B) {
+		any := jsoniter.ParseDocument(data).Root()
+		for i := 0; i < b.N; i++ {
+			any.Get(i%1000, "n").ToInt()
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package any_tests
+
+import (
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_edit_lazy_any(t *testing.T) {
+	should := require.New(t)
+	input := `{"id": 1.50, "user": {"name": "old", "tags": ["a", "b"]}, "big": [ 1, 2, 3 ]}`
+	original := jsoniter.Get([]byte(input))
+
+	edited := original.Set("new", "user", "name")
+	should.Equal(`{"id":1.50,"user":{"name":"new","tags":["a", "b"]},"big":[ 1, 2, 3 ]}`, edited.ToString())
+	should.Equal("new", edited.Get("user", "name").ToString())
+	should.Equal("old", original.Get("user", "name").ToString())
+
+	edited = edited.Set(map[string]int{"x": 1}, "extra")
+	should.Equal(1, edited.Get("extra", "x").ToInt())
+	edited = edited.Delete("id").Append("c", "user", "tags").Set(0, "big", 2)
+	should.Equal(`{"user":{"name":"new","tags":["a","b","c"]},"big":[1,2,0],"extra":{"x":1}}`, edited.ToString())
+	should.Equal(`["a", "b"]`, original.Get("user", "tags").ToString())
+
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+	stream.WriteVal(map[string]jsoniter.Any{"doc": edited.Delete("user").Delete("big", 0)})
+	should.Equal(`{"doc":{"big":[2,0],"extra":{"x":1}}}`, string(stream.Buffer()))
+
+	var decoded struct {
+		User struct {
+			Tags []string
+		}
+	}
+	edited.ToVal(&decoded)
+	should.Equal([]string{"a", "b", "c"}, decoded.User.Tags)
+}
+
+func Test_edit_shares_untouched_values(t *testing.T) {
+	should := require.New(t)
+	base := jsoniter.Get([]byte(`{"a":{"b":1},"c":[1]}`))
+	first := base.Set(2, "a", "b")
+	second := first.Set(3, "a", "b")
+	third := first.Append(2, "c")
+	should.Equal(`{"a":{"b":2},"c":[1]}`, first.ToString())
+	should.Equal(`{"a":{"b":3},"c":[1]}`, second.ToString())
+	should.Equal(`{"a":{"b":2},"c":[1,2]}`, third.ToString())
+	should.Equal(`{"a":{"b":3},"c":[1],"d":{"b":2}}`, second.Set(first.Get("a"), "d").ToString())
+}
+
+func Test_edit_wrapped_any(t *testing.T) {
+	should := require.New(t)
+	wrapped := jsoniter.Wrap(map[string]interface{}{"list": []int{1}})
+	should.Equal(`{"list":[1,2]}`, wrapped.Append(2, "list").ToString())
+	should.Equal(jsoniter.StringValue, jsoniter.WrapInt32(1).Set("x").ValueType())
+}
+
+func Test_edit_invalid_path(t *testing.T) {
+	should := require.New(t)
+	any := jsoniter.Get([]byte(`{"a":[1],"s":"str"}`))
+	should.Equal(jsoniter.InvalidValue, any.Set(1, "missing", "x").ValueType())
+	should.Equal(jsoniter.InvalidValue, any.Set(1, "a", 1).ValueType())
+	should.Equal(jsoniter.InvalidValue, any.Set(1, "s", "x").ValueType())
+	should.Equal(jsoniter.InvalidValue, any.Delete("missing").ValueType())
+	should.Equal(jsoniter.InvalidValue, any.Delete().ValueType())
+	should.Equal(jsoniter.InvalidValue, any.Append(1, "s").ValueType())
+	should.Equal(jsoniter.InvalidValue, any.Get("missing").Set(1, "x").ValueType())
+}
@@ -12,6 +12,9 @@ func Test_wrap_map(t *testing.T) {
 	should.Equal("hello", any.Get("Field1").ToString())
 	any = jsoniter.Wrap(map[string]string{"Field1": "hello"})
 	should.Equal(1, any.Size())
+	any = jsoniter.Wrap(map[string][]int{"Field1": {1, 2}})
+	should.Equal(2, any.Get("Field1", 1).ToInt())
+	should.Equal(jsoniter.InvalidValue, any.Get("Field1", 2).ValueType())
 }
 
 func Test_map_wrapper_any_get_all(t *testing.T) {
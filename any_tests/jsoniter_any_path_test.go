@@ -0,0 +1,86 @@
+package any_tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+var pathTestInput = []byte(`{ "store": {
+	"book": [
+		{ "category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95 },
+		{ "category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99 },
+		{ "category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99 },
+		{ "category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99 }
+	],
+	"bicycle": { "color": "red", "price": 399 }
+}}`)
+
+func Test_path_query(t *testing.T) {
+	should := require.New(t)
+	for _, testCase := range []struct {
+		expr     string
+		expected string
+	}{
+		{`$.store.book[*].author`, `["Nigel Rees","Evelyn Waugh","Herman Melville","J. R. R. Tolkien"]`},
+		{`$..author`, `["Nigel Rees","Evelyn Waugh","Herman Melville","J. R. R. Tolkien"]`},
+		{`$.store..price`, `[8.95,12.99,8.99,22.99,399]`},
+		{`$..book[2].title`, `["Moby Dick"]`},
+		{`$..book[-1].title`, `["The Lord of the Rings"]`},
+		{`$..book[0,1].title`, `["Sayings of the Century","Sword of Honour"]`},
+		{`$..book[1,0].title`, `["Sword of Honour","Sayings of the Century"]`},
+		{`$..book[:2].title`, `["Sayings of the Century","Sword of Honour"]`},
+		{`$..book[1:4:2].title`, `["Sword of Honour","The Lord of the Rings"]`},
+		{`$..book[::-1].price`, `[22.99,8.99,12.99,8.95]`},
+		{`$..book[-2:].price`, `[8.99,22.99]`},
+		{`$..book[?(@.isbn)].title`, `["Moby Dick","The Lord of the Rings"]`},
+		{`$..book[?!@.isbn].title`, `["Sayings of the Century","Sword of Honour"]`},
+		{`$.store.book[?(@.price < 10)].title`, `["Sayings of the Century","Moby Dick"]`},
+		{`$..book[?@.price > 10 && @.category == 'fiction'].author`, `["Evelyn Waugh","J. R. R. Tolkien"]`},
+		{`$..book[?(@.author == "Nigel Rees" || @.price >= 22.99)].price`, `[8.95,22.99]`},
+		{`$..book[?@.price < $.store.bicycle.price && @.price > 12].title`, `["Sword of Honour","The Lord of the Rings"]`},
+		{`$.store[?@.color == 'red'].price`, `[399]`},
+		{`$.store['bicycle','book'][0].category`, `["reference"]`},
+		{`$["store"]["bicycle"]`, `[{ "color": "red", "price": 399 }]`},
+		{`$.store.bicycle.*`, `["red",399]`},
+		{`$.missing`, `[]`},
+		{`$.store.book.title`, `[]`},
+	} {
+		path, err := jsoniter.CompilePath(testCase.expr)
+		should.NoError(err, testCase.expr)
+		should.Equal(testCase.expected, path.Query(pathTestInput).ToString(), testCase.expr)
+	}
+}
+
+func Test_path_query_any_and_iterator(t *testing.T) {
+	should := require.New(t)
+	path := jsoniter.MustCompilePath(`$.store.book[?(@.price < 10)].title`)
+	should.Equal(`["Sayings of the Century","Moby Dick"]`, path.QueryAny(jsoniter.Get(pathTestInput)).ToString())
+	wrapped := jsoniter.Wrap(map[string]interface{}{
+		"store": map[string]interface{}{
+			"book": []interface{}{map[string]interface{}{"title": "cheap", "price": 1}},
+		},
+	})
+	should.Equal(`["cheap"]`, path.QueryAny(wrapped).ToString())
+	iter := jsoniter.Parse(jsoniter.ConfigDefault, bytes.NewReader(pathTestInput), 16)
+	found := path.QueryIterator(iter)
+	should.NoError(found.LastError())
+	should.Equal(2, found.Size())
+	should.Equal("Moby Dick", found.Get(1).ToString())
+	iter = jsoniter.Parse(jsoniter.ConfigDefault, bytes.NewReader(pathTestInput), 16)
+	found = jsoniter.MustCompilePath(`$..book[?@.price < $.store.bicycle.price].price`).QueryIterator(iter)
+	should.Equal(4, found.Size())
+}
+
+func Test_path_query_invalid(t *testing.T) {
+	should := require.New(t)
+	for _, expr := range []string{``, `store`, `$.`, `$[`, `$[1`, `$['a`, `$[?(@.a < )]`, `$[?@..a == 1]`, `$[?1]`, `$.a b`} {
+		_, err := jsoniter.CompilePath(expr)
+		should.Error(err, expr)
+	}
+	found := jsoniter.MustCompilePath(`$.a[*]`).Query([]byte(`{"a":[1,}`))
+	should.Equal(jsoniter.InvalidValue, found.ValueType())
+	should.Error(found.LastError())
+}
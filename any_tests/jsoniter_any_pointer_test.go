@@ -0,0 +1,70 @@
+package any_tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+var pointerTestInput = []byte(`{
+	"foo": ["bar", "baz"],
+	"": 0,
+	"a/b": 1,
+	"c%d": 2,
+	"m~n": 4,
+	"items": [{"name": "first"}, {"name": "second"}]
+}`)
+
+func Test_get_pointer(t *testing.T) {
+	should := require.New(t)
+	should.Equal(jsoniter.ObjectValue, jsoniter.GetPointer(pointerTestInput, "").ValueType())
+	should.Equal(2, jsoniter.GetPointer(pointerTestInput, "/foo").Size())
+	should.Equal("bar", jsoniter.GetPointer(pointerTestInput, "/foo/0").ToString())
+	should.Equal(0, jsoniter.GetPointer(pointerTestInput, "/").ToInt())
+	should.Equal(1, jsoniter.GetPointer(pointerTestInput, "/a~1b").ToInt())
+	should.Equal(2, jsoniter.GetPointer(pointerTestInput, "/c%d").ToInt())
+	should.Equal(4, jsoniter.GetPointer(pointerTestInput, "/m~0n").ToInt())
+	should.Equal("second", jsoniter.GetPointer(pointerTestInput, "/items/1/name").ToString())
+}
+
+func Test_get_pointer_not_found(t *testing.T) {
+	should := require.New(t)
+	should.Equal(jsoniter.InvalidValue, jsoniter.GetPointer(pointerTestInput, "/foo/-").ValueType())
+	should.Equal(jsoniter.InvalidValue, jsoniter.GetPointer(pointerTestInput, "/foo/2").ValueType())
+	should.Equal(jsoniter.InvalidValue, jsoniter.GetPointer(pointerTestInput, "/foo/01").ValueType())
+	should.Equal(jsoniter.InvalidValue, jsoniter.GetPointer(pointerTestInput, "/missing").ValueType())
+	should.Equal(jsoniter.InvalidValue, jsoniter.GetPointer(pointerTestInput, "/a~2b").ValueType())
+	should.Equal(jsoniter.InvalidValue, jsoniter.GetPointer(pointerTestInput, "foo").ValueType())
+	should.NotNil(jsoniter.GetPointer(pointerTestInput, "/missing").LastError())
+}
+
+func Test_any_get_pointer(t *testing.T) {
+	should := require.New(t)
+	any := jsoniter.Get(pointerTestInput)
+	should.Equal("baz", any.GetPointer("/foo/1").ToString())
+	should.Equal("first", any.Get("items").GetPointer("/0/name").ToString())
+	wrapped := jsoniter.Wrap(map[string]interface{}{"a/b": []int{1, 2}})
+	should.Equal(2, wrapped.GetPointer("/a~1b/1").ToInt())
+	should.Equal(jsoniter.InvalidValue, wrapped.GetPointer("/a~1b/1/x").ValueType())
+}
+
+func Test_iterator_seek_pointer(t *testing.T) {
+	should := require.New(t)
+	iter := jsoniter.Parse(jsoniter.ConfigDefault, bytes.NewReader(pointerTestInput), 8)
+	should.True(iter.SeekPointer("/items/1"))
+	var item struct {
+		Name string `json:"name"`
+	}
+	iter.ReadVal(&item)
+	should.Equal("second", item.Name)
+	iter = jsoniter.ParseBytes(jsoniter.ConfigDefault, pointerTestInput)
+	should.False(iter.SeekPointer("/items/2"))
+	iter = jsoniter.ParseBytes(jsoniter.ConfigDefault, pointerTestInput)
+	should.False(iter.SeekPointer("/items/0/name/0"))
+	should.NoError(iter.Error)
+	iter = jsoniter.Parse(jsoniter.ConfigDefault, bytes.NewReader(pointerTestInput), 8)
+	should.True(iter.SeekPointer("/items/0/name"))
+	should.Equal("first", iter.ReadString())
+}
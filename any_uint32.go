@@ -17,6 +17,22 @@ func (any *uint32Any) ValueType() ValueType {
 	return NumberValue
 }
 
+func (any *uint32Any) GetPointer(pointer string) Any {
+	return getPointer(any, pointer)
+}
+
+func (any *uint32Any) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *uint32Any) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *uint32Any) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
 func (any *uint32Any) MustBeValid() Any {
 	return any
 }
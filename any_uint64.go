@@ -17,6 +17,22 @@ func (any *uint64Any) ValueType() ValueType {
 	return NumberValue
 }
 
+func (any *uint64Any) GetPointer(pointer string) Any {
+	return getPointer(any, pointer)
+}
+
+func (any *uint64Any) Set(value interface{}, path ...interface{}) Any {
+	return setAny(any, value, path)
+}
+
+func (any *uint64Any) Delete(path ...interface{}) Any {
+	return deleteAny(any, path)
+}
+
+func (any *uint64Any) Append(value interface{}, path ...interface{}) Any {
+	return appendAny(any, value, path)
+}
+
 func (any *uint64Any) MustBeValid() Any {
 	return any
 }
@@ -0,0 +1,154 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_canonicalize(t *testing.T) {
+	should := require.New(t)
+	// example of RFC 8785 section 3.2.2
+	input := `{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+		"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+		"literals": [null, true, false]
+	}`
+	output, err := jsoniter.Canonicalize([]byte(input))
+	should.NoError(err)
+	should.Equal(`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],`+
+		`"string":"€$\u000f\nA'B\"\\\\\"/"}`, string(output))
+}
+
+func Test_canonicalize_sorts_by_utf16(t *testing.T) {
+	should := require.New(t)
+	// example of RFC 8785 section 3.2.3
+	input := `{
+		"\u20ac": "Euro Sign",
+		"\r": "Carriage Return",
+		"\ufb33": "Hebrew Letter Dalet With Dagesh",
+		"1": "One",
+		"\ud83d\ude00": "Emoji: Grinning Face",
+		"\u0080": "Control",
+		"\u00f6": "Latin Small Letter O With Diaeresis"
+	}`
+	output, err := jsoniter.Canonicalize([]byte(input))
+	should.NoError(err)
+	should.Equal("{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\","+
+		"\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\","+
+		"\"\U0001F600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}", string(output))
+}
+
+func Test_canonical_numbers(t *testing.T) {
+	// test vectors of RFC 8785 appendix B
+	testCases := []struct {
+		bits     uint64
+		expected string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	}
+	api := jsoniter.Config{Canonical: true}.Froze()
+	for _, testCase := range testCases {
+		should := require.New(t)
+		output, err := api.Marshal(math.Float64frombits(testCase.bits))
+		should.NoError(err)
+		should.Equal(testCase.expected, string(output))
+	}
+}
+
+type canonicalRecord struct {
+	Zeta  string              `json:"zeta"`
+	Alpha []float64           `json:"alpha"`
+	Extra map[string]int      `json:"extra"`
+	Raw   jsoniter.RawMessage `json:"raw"`
+	Std   json.RawMessage     `json:"std"`
+	Lazy  jsoniter.Any        `json:"lazy"`
+	Inner struct {
+		B bool `json:"b"`
+		A bool `json:"a"`
+	} `json:"inner"`
+}
+
+func Test_canonical_config(t *testing.T) {
+	should := require.New(t)
+	record := canonicalRecord{
+		Zeta:  "<tag> & \u2028",
+		Alpha: []float64{1e21, 0.1, 100},
+		Extra: map[string]int{"b": 2, "a": 1},
+		Raw:   jsoniter.RawMessage(`{ "y" : 1.50, "x" : [ 2E2 ] }`),
+		Std:   json.RawMessage(`{"d":"\u0041","c":null}`),
+		Lazy:  jsoniter.Get([]byte(`{"n":1,"m":2}`)),
+	}
+	expected := `{"alpha":[1e+21,0.1,100],"extra":{"a":1,"b":2},"inner":{"a":false,"b":false},` +
+		`"lazy":{"m":2,"n":1},"raw":{"x":[200],"y":1.5},"std":{"c":null,"d":"A"},"zeta":"<tag> & ` + "\u2028" + `"}`
+	for _, config := range []jsoniter.Config{
+		{Canonical: true},
+		{Canonical: true, EscapeHTML: true, IndentionStep: 2, MarshalFloatWith6Digits: true},
+		// the decoding options do not apply to the output read back
+		{Canonical: true, MaxStringLength: 4, MaxDepth: 1, MaxElements: 1, MaxNumberLength: 1, AllowJSON5: true},
+	} {
+		api := config.Froze()
+		output, err := api.Marshal(record)
+		should.NoError(err)
+		should.Equal(expected, string(output))
+		str, err := api.MarshalToString(record)
+		should.NoError(err)
+		should.Equal(expected, str)
+		output, err = api.MarshalIndent(record, "", "  ")
+		should.NoError(err)
+		should.Equal(expected, string(output))
+
+		var buf bytes.Buffer
+		encoder := api.NewEncoder(&buf)
+		encoder.SetFlushThreshold(8)
+		should.NoError(encoder.Encode(record))
+		should.NoError(encoder.Encode(record.Extra))
+		should.Equal(expected+"\n"+`{"a":1,"b":2}`+"\n", buf.String())
+	}
+	_, err := jsoniter.Config{Canonical: true, AllowJSON5: true}.Froze().Marshal(jsoniter.RawMessage(`{b:1,'a':2,}`))
+	should.Error(err)
+}
+
+func Test_canonicalize_errors(t *testing.T) {
+	should := require.New(t)
+	for _, input := range []string{
+		`{"a":1,"a":2}`,
+		`{"b":1,"a":2,"b":3}`,
+		`[1e400]`,
+		"[\"\xff\"]",
+		`[1,2`,
+		`{} {}`,
+	} {
+		_, err := jsoniter.Canonicalize([]byte(input))
+		should.Error(err, input)
+	}
+	_, err := jsoniter.Config{Canonical: true}.Froze().Marshal(jsoniter.RawMessage(`{"a":1,"a":2}`))
+	should.Error(err)
+}
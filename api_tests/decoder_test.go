@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"github.com/json-iterator/go"
 	"github.com/stretchr/testify/require"
+	"io"
 	"io/ioutil"
 	"testing"
 )
@@ -62,3 +63,73 @@ func Test_decoder_more(t *testing.T) {
 	decoder := jsoniter.NewDecoder(bytes.NewBufferString("abcde"))
 	should.True(decoder.More())
 }
+
+func Test_decoder_token(t *testing.T) {
+	should := require.New(t)
+	input := `{"a": [1, "b", true, null, {"c": 2.5}], "d": {}} [] 3`
+	decoder1 := json.NewDecoder(bytes.NewBufferString(input))
+	decoder2 := jsoniter.NewDecoder(bytes.NewBufferString(input))
+	for {
+		token1, err1 := decoder1.Token()
+		token2, err2 := decoder2.Token()
+		should.Equal(err1, err2)
+		should.Equal(token1, token2)
+		if err1 != nil {
+			break
+		}
+	}
+}
+
+func Test_decoder_token_mixed_with_decode(t *testing.T) {
+	should := require.New(t)
+	decoder := jsoniter.NewDecoder(bytes.NewBufferString(`{"items": [{"id": 1}, {"id": 2}, {"id": 3}]}`))
+	decoder.UseNumber()
+	token, err := decoder.Token()
+	should.Nil(err)
+	should.Equal(json.Delim('{'), token)
+	token, err = decoder.Token()
+	should.Nil(err)
+	should.Equal("items", token)
+	token, err = decoder.Token()
+	should.Nil(err)
+	should.Equal(json.Delim('['), token)
+	ids := []int{}
+	for decoder.More() {
+		var item struct {
+			ID int `json:"id"`
+		}
+		should.Nil(decoder.Decode(&item))
+		ids = append(ids, item.ID)
+	}
+	should.Equal([]int{1, 2, 3}, ids)
+	token, err = decoder.Token()
+	should.Nil(err)
+	should.Equal(json.Delim(']'), token)
+	token, err = decoder.Token()
+	should.Nil(err)
+	should.Equal(json.Delim('}'), token)
+	_, err = decoder.Token()
+	should.Equal(io.EOF, err)
+}
+
+func Test_decoder_token_use_number(t *testing.T) {
+	should := require.New(t)
+	decoder := jsoniter.NewDecoder(bytes.NewBufferString(`[12345678901234567890]`))
+	decoder.UseNumber()
+	_, err := decoder.Token()
+	should.Nil(err)
+	token, err := decoder.Token()
+	should.Nil(err)
+	should.Equal(json.Number("12345678901234567890"), token)
+}
+
+func Test_decoder_token_invalid(t *testing.T) {
+	should := require.New(t)
+	decoder := jsoniter.NewDecoder(bytes.NewBufferString(`[1 }`))
+	_, err := decoder.Token()
+	should.Nil(err)
+	_, err = decoder.Token()
+	should.Nil(err)
+	_, err = decoder.Token()
+	should.Error(err)
+}
@@ -18,3 +18,41 @@ func TestEncoderHasTrailingNewline(t *testing.T) {
 	stdenc.Encode(1)
 	should.Equal(stdbuf.Bytes(), buf.Bytes())
 }
+
+type chunkRecorder struct {
+	bytes.Buffer
+	chunks int
+}
+
+func (recorder *chunkRecorder) Write(p []byte) (int, error) {
+	recorder.chunks++
+	return recorder.Buffer.Write(p)
+}
+
+func Test_encoder_flush_threshold(t *testing.T) {
+	should := require.New(t)
+	type item struct {
+		Name  string
+		Tags  []string
+		Score float64
+	}
+	value := make([]item, 1000)
+	for i := range value {
+		value[i] = item{Name: "item", Tags: []string{"x", "y"}, Score: float64(i) / 4}
+	}
+	var stdbuf bytes.Buffer
+	should.NoError(json.NewEncoder(&stdbuf).Encode(value))
+
+	recorder := &chunkRecorder{}
+	enc := jsoniter.ConfigCompatibleWithStandardLibrary.NewEncoder(recorder)
+	enc.SetFlushThreshold(256)
+	should.NoError(enc.Encode(value))
+	should.Equal(stdbuf.String(), recorder.String())
+	should.True(recorder.chunks > stdbuf.Len()/512, "only %d writes", recorder.chunks)
+
+	recorder = &chunkRecorder{}
+	enc = jsoniter.ConfigCompatibleWithStandardLibrary.NewEncoder(recorder)
+	should.NoError(enc.Encode(value))
+	should.Equal(stdbuf.String(), recorder.String())
+	should.Equal(1, recorder.chunks)
+}
@@ -0,0 +1,100 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type genericPoint struct {
+	X int `json:"x" xml:"px"`
+	Y int `json:"y" xml:"py"`
+}
+
+func Test_unmarshal_as(t *testing.T) {
+	should := require.New(t)
+	point, err := jsoniter.UnmarshalAs[genericPoint](jsoniter.ConfigDefault, []byte(`{"x":1,"y":2}`))
+	should.NoError(err)
+	should.Equal(genericPoint{1, 2}, point)
+	points, err := jsoniter.UnmarshalAs[[]*genericPoint](jsoniter.ConfigDefault, []byte(`[{"x":1},null]`))
+	should.NoError(err)
+	should.Equal([]*genericPoint{{X: 1}, nil}, points)
+	_, err = jsoniter.UnmarshalAs[genericPoint](jsoniter.ConfigDefault, []byte(`{"x":1} {}`))
+	should.Error(err)
+	_, err = jsoniter.UnmarshalAs[int](jsoniter.ConfigDefault, []byte(`"1"`))
+	should.Error(err)
+
+	api := jsoniter.Config{TagKey: "xml"}.Froze()
+	point, err = jsoniter.UnmarshalAs[genericPoint](api, []byte(`{"px":3,"x":1}`))
+	should.NoError(err)
+	should.Equal(genericPoint{X: 3}, point)
+}
+
+func Test_marshal_as(t *testing.T) {
+	should := require.New(t)
+	output, err := jsoniter.MarshalAs(jsoniter.ConfigDefault, genericPoint{1, 2})
+	should.NoError(err)
+	should.Equal(`{"x":1,"y":2}`, string(output))
+	output, err = jsoniter.MarshalAs(jsoniter.Config{TagKey: "xml"}.Froze(), &genericPoint{1, 2})
+	should.NoError(err)
+	should.Equal(`{"px":1,"py":2}`, string(output))
+	output, err = jsoniter.MarshalAs(jsoniter.ConfigDefault, map[string]int(nil))
+	should.NoError(err)
+	should.Equal(`null`, string(output))
+	_, err = jsoniter.MarshalAs(jsoniter.ConfigDefault, func() {})
+	should.Error(err)
+}
+
+func Test_typed_handles(t *testing.T) {
+	should := require.New(t)
+	decoder := jsoniter.NewTypedDecoder[genericPoint](jsoniter.ConfigDefault)
+	encoder := jsoniter.NewTypedEncoder[genericPoint](jsoniter.ConfigDefault)
+	iter := jsoniter.ParseString(jsoniter.ConfigDefault, `[{"x":1,"y":2},{"x":3}]`)
+	points := []genericPoint{}
+	for iter.ReadArray() {
+		var point genericPoint
+		decoder.Decode(iter, &point)
+		points = append(points, point)
+	}
+	should.NoError(iter.Error)
+	should.Equal([]genericPoint{{1, 2}, {3, 0}}, points)
+
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+	encoder.Encode(stream, &points[1])
+	stream.WriteMore()
+	encoder.Encode(stream, nil)
+	should.Equal(`{"x":3,"y":0},null`, string(stream.Buffer()))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		stream.Reset(nil)
+		encoder.Encode(stream, &points[0])
+	})
+	should.Equal(float64(0), allocs)
+	var point genericPoint
+	should.NoError(decoder.Unmarshal([]byte(`{"y":5}`), &point))
+	should.Equal(genericPoint{Y: 5}, point)
+}
+
+func Test_each_line(t *testing.T) {
+	should := require.New(t)
+	input := "{\"x\":1}\n\n{\"x\":\"bad\"}\n{\"x\":3}\n"
+	points := []genericPoint{}
+	err := jsoniter.EachLine(jsoniter.NewLineReader(jsoniter.ConfigDefault, strings.NewReader(input)), func(point genericPoint) error {
+		points = append(points, point)
+		return nil
+	})
+	lineErr, ok := err.(*jsoniter.LineError)
+	should.True(ok)
+	should.Equal(3, lineErr.Line)
+	should.Equal([]genericPoint{{X: 1}}, points)
+	points = points[:0]
+	reader := jsoniter.NewLineReader(jsoniter.ConfigDefault, strings.NewReader(input)).SkipBadLines(nil)
+	should.NoError(jsoniter.EachLine(reader, func(point genericPoint) error {
+		points = append(points, point)
+		return nil
+	}))
+	should.Equal([]genericPoint{{X: 1}, {X: 3}}, points)
+}
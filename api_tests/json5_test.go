@@ -0,0 +1,252 @@
+package test
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type json5Server struct {
+	Name    string            `json:"name"`
+	Port    int               `json:"port"`
+	Mask    uint32            `json:"mask"`
+	Ratio   float64           `json:"ratio"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels"`
+	Raw     json.RawMessage   `json:"raw"`
+	Timeout int64             `json:"timeout"`
+}
+
+const json5Config = `
+// server config
+{
+	name: 'web "1"', /* single quoted */
+	port: +8080,
+	mask: 0xFFff0000,
+	ratio: .5,
+	tags: ['a', "b", /* c */],
+	labels: {$env: 'prod', 'team-name': "core", "quoted": 'it\'s',},
+	raw: {a: [1, 0x10,], 'b': Infinity},
+	timeout: -0x10, // trailing comma next
+}
+`
+
+func Test_json5_struct(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{AllowJSON5: true}.Froze()
+	var server json5Server
+	should.NoError(api.Unmarshal([]byte(json5Config), &server))
+	should.Equal(json5Server{
+		Name:    `web "1"`,
+		Port:    8080,
+		Mask:    0xFFFF0000,
+		Ratio:   0.5,
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"$env": "prod", "team-name": "core", "quoted": "it's"},
+		Raw:     json.RawMessage(`{"a":[1,16],"b":Infinity}`),
+		Timeout: -16,
+	}, server)
+
+	server = json5Server{}
+	should.NoError(api.NewDecoder(iotestOneByteReader(json5Config)).Decode(&server))
+	should.Equal(8080, server.Port)
+	should.Equal(map[string]string{"$env": "prod", "team-name": "core", "quoted": "it's"}, server.Labels)
+
+	var fields map[string]interface{}
+	should.NoError(api.Unmarshal([]byte(json5Config), &fields))
+	should.Equal(float64(-16), fields["timeout"])
+	should.Equal([]interface{}{"a", "b"}, fields["tags"])
+	should.True(math.IsInf(fields["raw"].(map[string]interface{})["b"].(float64), 1))
+	should.True(api.Valid([]byte(json5Config)))
+}
+
+func Test_json5_numbers(t *testing.T) {
+	testCases := []struct {
+		input string
+		value float64
+	}{
+		{`+1`, 1},
+		{`0x1F`, 31},
+		{`-0XA`, -10},
+		{`.5`, 0.5},
+		{`5.`, 5},
+		{`+.5e1`, 5},
+		{`Infinity`, math.Inf(1)},
+		{`-Infinity`, math.Inf(-1)},
+		{`+Infinity`, math.Inf(1)},
+	}
+	api := jsoniter.Config{AllowJSON5: true}.Froze()
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			should := require.New(t)
+			var value float64
+			should.NoError(api.UnmarshalFromString(testCase.input, &value))
+			should.Equal(testCase.value, value)
+			var value32 float32
+			should.NoError(api.UnmarshalFromString(testCase.input, &value32))
+			should.Equal(float32(testCase.value), value32)
+			should.Equal(testCase.value, api.Get([]byte(`[`+testCase.input+`]`), 0).ToFloat64())
+		})
+	}
+	should := require.New(t)
+	var value float64
+	should.NoError(api.UnmarshalFromString(`NaN`, &value))
+	should.True(math.IsNaN(value))
+	var number json.Number
+	should.NoError(api.UnmarshalFromString(`+0x1F`, &number))
+	should.Equal(json.Number("31"), number)
+	var ints []int8
+	should.NoError(api.UnmarshalFromString(`[+1, 0x7f, -0x80, 0]`, &ints))
+	should.Equal([]int8{1, 127, -128, 0}, ints)
+	should.Error(api.UnmarshalFromString(`[0x80]`, &ints))
+	var big uint64
+	should.NoError(api.UnmarshalFromString(`0xFFFFFFFFFFFFFFFF`, &big))
+	should.Equal(uint64(math.MaxUint64), big)
+	should.Error(api.UnmarshalFromString(`0x10000000000000000`, &big))
+	for _, input := range []string{`0x`, `01`, `1e`, `.`, `+-1`, `Inf`} {
+		should.Error(api.UnmarshalFromString(input, &value), input)
+	}
+}
+
+func Test_json5_any(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{AllowJSON5: true}.Froze()
+	any := api.Get([]byte(json5Config))
+	should.NoError(any.LastError())
+	should.Equal(8080, any.Get("port").ToInt())
+	should.Equal("it's", any.Get("labels", "quoted").ToString())
+	should.Equal(`[1,16]`, any.Get("raw", "a").ToString())
+	iter := jsoniter.ParseString(api, `{a: 0x10, /* comment */ b: ['x',],}`)
+	should.Equal(`{"a":16,"b":["x"]}`, iter.ReadAny().ToString())
+
+	doc := api.ParseDocument([]byte(json5Config))
+	should.NoError(doc.LastError())
+	should.Equal("core", doc.Get("labels", "team-name").ToString())
+	should.Equal(uint32(0xFFFF0000), doc.Get("mask").ToUint32())
+}
+
+func Test_json5_iterator(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{AllowJSON5: true}.Froze()
+	iter := jsoniter.ParseString(api, `{a: 'x', /* b */ 'b': [1, 2,], c: null,}`)
+	should.Equal("a", iter.ReadObject())
+	should.Equal(jsoniter.StringValue, iter.WhatIsNext())
+	should.Equal("x", iter.ReadString())
+	should.Equal("b", iter.ReadObject())
+	should.True(iter.ReadArray())
+	should.Equal(1, iter.ReadInt())
+	should.True(iter.ReadArray())
+	should.Equal(2, iter.ReadInt())
+	should.False(iter.ReadArray())
+	should.Equal("c", iter.ReadObject())
+	iter.Skip()
+	should.Equal("", iter.ReadObject())
+	should.NoError(iter.Error)
+
+	var keys []string
+	iter = jsoniter.ParseString(api, `// comment only line
+	{ key_1 : 1 , $key2: 'two' // comment
+	}`)
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+		keys = append(keys, key)
+		iter.Skip()
+		return true
+	})
+	should.NoError(iter.Error)
+	should.Equal([]string{"key_1", "$key2"}, keys)
+}
+
+func Test_json5_invalid(t *testing.T) {
+	api := jsoniter.Config{AllowJSON5: true}.Froze()
+	for _, input := range []string{
+		`[1,,2]`,
+		`{a: 1,,}`,
+		`[1 /* unterminated`,
+		`[1 / 2]`,
+		`{1a: 1}`,
+		`['\x']`,
+		`{a 1}`,
+		`[,]`,
+		`{,}`,
+		`[ /* empty */ ,]`,
+		`[,1]`,
+		`{,a: 1}`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			should := require.New(t)
+			var value interface{}
+			should.Error(api.UnmarshalFromString(input, &value))
+			should.False(api.Valid([]byte(input)))
+		})
+	}
+}
+
+func Test_json5_trailing_comma_after_element(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{AllowJSON5: true}.Froze()
+	var value interface{}
+	should.NoError(api.UnmarshalFromString(`[[], {}, [1 /* one */ ,], {a: {},},]`, &value))
+	should.Equal([]interface{}{[]interface{}{}, map[string]interface{}{}, []interface{}{float64(1)},
+		map[string]interface{}{"a": map[string]interface{}{}}}, value)
+	var skipped struct {
+		B int `json:"b"`
+	}
+	should.NoError(api.UnmarshalFromString(`{a: [], b: 1, c: {},}`, &skipped))
+	should.Equal(1, skipped.B)
+
+	for _, input := range []string{`[,]`, `{,}`} {
+		should.Error(api.UnmarshalFromString(input, &[]int{}), input)
+		should.Error(api.UnmarshalFromString(input, &map[string]int{}), input)
+		should.Error(api.UnmarshalFromString(input, &skipped), input)
+		iter := jsoniter.ParseString(api, input)
+		iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			iter.Skip()
+			return true
+		})
+		should.Error(iter.Error, input)
+		iter = jsoniter.ParseString(api, input)
+		iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+			iter.Skip()
+			return true
+		})
+		should.Error(iter.Error, input)
+	}
+}
+
+func Test_json5_disabled_by_default(t *testing.T) {
+	for _, input := range []string{
+		`// comment
+		{}`,
+		`[1, 2,]`,
+		`{"a": 1,}`,
+		`'single'`,
+		`{a: 1}`,
+		`0x10`,
+		`+1`,
+		`Infinity`,
+		`NaN`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			should := require.New(t)
+			var value interface{}
+			should.Error(jsoniter.UnmarshalFromString(input, &value))
+			should.Error(jsoniter.ConfigDefault.ParseDocument([]byte(input)).LastError())
+		})
+	}
+	var value string
+	require.Error(t, jsoniter.UnmarshalFromString(`"it\'s"`, &value))
+}
+
+func Test_json5_limits(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{AllowJSON5: true, MaxStringLength: 3, MaxDepth: 1}.Froze()
+	var value map[string]interface{}
+	requireLimitError(should, api.UnmarshalFromString(`{abcd: 1}`, &value), "MaxStringLength", 3)
+	requireLimitError(should, api.UnmarshalFromString(`{a: 'abcd'}`, &value), "MaxStringLength", 3)
+	requireLimitError(should, api.UnmarshalFromString(`{a: [[1]]}`, &value), "MaxDepth", 1)
+	should.NoError(api.UnmarshalFromString(strings.Repeat(" ", 10)+`{a: 'abc',}`, &value))
+}
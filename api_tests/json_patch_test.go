@@ -0,0 +1,115 @@
+package test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_apply_patch(t *testing.T) {
+	should := require.New(t)
+	for _, testCase := range []struct {
+		doc      string
+		ops      string
+		expected string
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"foo":"bar","baz":"qux"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"a":{"b":[1]}}`, `[{"op":"copy","from":"/a/b","path":"/c"},{"op":"add","path":"/c/-","value":2}]`, `{"a":{"b":[1]},"c":[1,2]}`},
+		{`{"baz":"qux","foo":["a",2,"c"]}`,
+			`[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			`{"baz":"qux","foo":["a",2,"c"]}`},
+		{`{"a/b":{"m~n":1}}`, `[{"op":"replace","path":"/a~1b/m~0n","value":2}]`, `{"a/b":{"m~n":2}}`},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+		{`{"n": 12345678901234567890.123456789, "keep": [ 1.50, 2 ]}`,
+			`[{"op":"test","path":"/n","value":12345678901234567890.1234567890},{"op":"add","path":"/m","value":1e400}]`,
+			`{"n":12345678901234567890.123456789,"keep":[ 1.50, 2 ],"m":1e400}`},
+		{`{"a":{"x":1,"y":[true,null]}}`, `[{"op":"test","path":"/a","value":{"y":[true,null],"x":1.0}}]`, `{"a":{"x":1,"y":[true,null]}}`},
+	} {
+		patched, err := jsoniter.ApplyPatch([]byte(testCase.doc), []byte(testCase.ops))
+		should.NoError(err, testCase.ops)
+		should.Equal(testCase.expected, string(patched), testCase.ops)
+	}
+}
+
+func Test_apply_patch_to_large_object(t *testing.T) {
+	should := require.New(t)
+	const size = 20000
+	members := make([]string, size)
+	for i := range members {
+		members[i] = `"k` + strconv.Itoa(i) + `":` + strconv.Itoa(i)
+	}
+	doc := "{" + strings.Join(members, ",") + "}"
+	ops := `[{"op":"remove","path":"/k0"},{"op":"remove","path":"/k1"},{"op":"replace","path":"/k5000","value":-1},` +
+		`{"op":"test","path":"/k19999","value":19999},{"op":"add","path":"/k2","value":"x"},{"op":"add","path":"/new","value":0},` +
+		`{"op":"move","from":"/k3","path":"/moved"},{"op":"remove","path":"/new"}]`
+	patched, err := jsoniter.ApplyPatch([]byte(doc), []byte(ops))
+	should.NoError(err)
+	members[2] = `"k2":"x"`
+	members[5000] = `"k5000":-1`
+	expected := "{" + strings.Join(members[2:3], ",") + "," + strings.Join(members[4:], ",") + `,"moved":3}`
+	should.Equal(expected, string(patched))
+}
+
+func Test_apply_patch_errors(t *testing.T) {
+	should := require.New(t)
+	doc := []byte(`{"foo":["bar"],"n":1.10}`)
+	for _, testCase := range []struct {
+		ops   string
+		index int
+		msg   string
+	}{
+		{`[{"op":"test","path":"/n","value":1.1},{"op":"test","path":"/n","value":"1.10"}]`, 1, `test failed, value is 1.10`},
+		{`[{"op":"remove","path":"/missing"}]`, 0, `path /missing not found`},
+		{`[{"op":"add","path":"/missing/a","value":1}]`, 0, `path /missing not found`},
+		{`[{"op":"add","path":"/foo/2","value":1}]`, 0, `index 2 is out of bounds`},
+		{`[{"op":"replace","path":"/foo/01","value":1}]`, 0, `path /foo/01 not found`},
+		{`[{"op":"move","from":"/foo","path":"/foo/0"}]`, 0, `can not move /foo into itself`},
+		{`[{"op":"add","path":"/n/a","value":1}]`, 0, `path /n is not a container`},
+		{`[{"op":"add","path":"a","value":1}]`, 0, `json pointer "a" must start with /`},
+	} {
+		_, err := jsoniter.ApplyPatch(doc, []byte(testCase.ops))
+		should.Error(err, testCase.ops)
+		patchErr, ok := err.(*jsoniter.PatchError)
+		should.True(ok, err.Error())
+		should.Equal(testCase.index, patchErr.Index)
+		should.Equal(testCase.msg, patchErr.Msg)
+	}
+	for _, ops := range []string{
+		`{"op":"add"}`,
+		`[{"op":"add","path":"/a"}]`,
+		`[{"op":"copy","path":"/a"}]`,
+		`[{"path":"/a"}]`,
+		`[{"op":"remove"}]`,
+	} {
+		_, err := jsoniter.ApplyPatch(doc, []byte(ops))
+		should.Error(err, ops)
+	}
+}
+
+func Test_diff_patch(t *testing.T) {
+	should := require.New(t)
+	a := `{"a":1,"b":{"c":[1,2,3],"d":"x"},"e/f":"removed","g":1.0}`
+	b := `{"a":1,"b":{"c":[1,5],"d":"y"},"h":[],"g":1}`
+	patch, err := jsoniter.DiffPatch([]byte(a), []byte(b))
+	should.NoError(err)
+	should.Equal(`[{"op":"replace","path":"/b/c/1","value":5},{"op":"remove","path":"/b/c/2"},`+
+		`{"op":"replace","path":"/b/d","value":"y"},{"op":"remove","path":"/e~1f"},{"op":"add","path":"/h","value":[]}]`, string(patch))
+	patched, err := jsoniter.ApplyPatch([]byte(a), patch)
+	should.NoError(err)
+	should.Equal(`{"a":1,"b":{"c":[1,5],"d":"y"},"g":1.0,"h":[]}`, string(patched))
+	patch, err = jsoniter.DiffPatch([]byte(a), []byte(a))
+	should.NoError(err)
+	should.Equal(`[]`, string(patch))
+}
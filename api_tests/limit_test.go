@@ -0,0 +1,199 @@
+package test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type limitRecord struct {
+	Name  string            `json:"name"`
+	Items []int             `json:"items"`
+	Attrs map[string]string `json:"attrs"`
+	Value interface{}       `json:"value"`
+}
+
+func requireLimitError(should *require.Assertions, err error, limit string, max int64) *jsoniter.LimitError {
+	limitErr, ok := err.(*jsoniter.LimitError)
+	should.True(ok, "expect *LimitError, but found %T: %v", err, err)
+	should.Equal(limit, limitErr.Limit)
+	should.Equal(max, limitErr.Max)
+	return limitErr
+}
+
+func Test_limit_errors(t *testing.T) {
+	testCases := []struct {
+		config jsoniter.Config
+		input  string
+		limit  string
+		max    int64
+		field  string
+	}{
+		{jsoniter.Config{MaxDepth: 3}, `{"value":[[[1]]]}`, "MaxDepth", 3, "Value"},
+		{jsoniter.Config{MaxStringLength: 4}, `{"name":"hello"}`, "MaxStringLength", 4, "Name"},
+		{jsoniter.Config{MaxStringLength: 4}, `{"name":"he\"llo"}`, "MaxStringLength", 4, "Name"},
+		{jsoniter.Config{MaxStringLength: 4}, `{"value":{"hello":1}}`, "MaxStringLength", 4, "Value"},
+		{jsoniter.Config{MaxElements: 3}, `{"items":[1,2,3,4]}`, "MaxElements", 3, "Items"},
+		{jsoniter.Config{MaxElements: 1}, `{"attrs":{"a":"1","b":"2"}}`, "MaxElements", 1, "Attrs"},
+		{jsoniter.Config{MaxElements: 2}, `{"value":[1,2,3]}`, "MaxElements", 2, "Value"},
+		{jsoniter.Config{MaxNumberLength: 5}, `{"value":1234567}`, "MaxNumberLength", 5, "Value"},
+		{jsoniter.Config{MaxNumberLength: 5, UseNumber: true}, `{"value":1.234567}`, "MaxNumberLength", 5, "Value"},
+		{jsoniter.Config{MaxBytes: 10}, `{"name":"hello"}`, "MaxBytes", 10, ""},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			should := require.New(t)
+			api := testCase.config.Froze()
+			var record limitRecord
+			err := api.Unmarshal([]byte(testCase.input), &record)
+			limitErr := requireLimitError(should, err, testCase.limit, testCase.max)
+			should.Equal(testCase.field, limitErr.Field)
+			should.Contains(err.Error(), "exceeded")
+
+			record = limitRecord{}
+			decoder := api.NewDecoder(iotestOneByteReader(testCase.input))
+			err = decoder.Decode(&record)
+			requireLimitError(should, err, testCase.limit, testCase.max)
+		})
+	}
+}
+
+func Test_limit_not_exceeded(t *testing.T) {
+	should := require.New(t)
+	input := `{"name":"hello","items":[1,2,3],"attrs":{"a":"b"},"value":1}`
+	api := jsoniter.Config{
+		MaxDepth:        2,
+		MaxBytes:        int64(len(input)),
+		MaxStringLength: 5,
+		MaxElements:     4,
+		MaxNumberLength: 1,
+	}.Froze()
+	var record limitRecord
+	should.NoError(api.Unmarshal([]byte(input), &record))
+	should.Equal("hello", record.Name)
+	should.Equal([]int{1, 2, 3}, record.Items)
+	record = limitRecord{}
+	should.NoError(api.NewDecoder(iotestOneByteReader(input)).Decode(&record))
+	should.Equal(map[string]string{"a": "b"}, record.Attrs)
+	should.True(api.Valid([]byte(input)))
+}
+
+func Test_limit_skipped_values(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{MaxStringLength: 4, MaxElements: 2}.Froze()
+	var record limitRecord
+	err := api.Unmarshal([]byte(`{"unknown":"hello"}`), &record)
+	requireLimitError(should, err, "MaxStringLength", 4)
+	err = api.Unmarshal([]byte(`{"unknown":[1,2,3]}`), &record)
+	requireLimitError(should, err, "MaxElements", 2)
+	err = jsoniter.Config{MaxDepth: 2}.Froze().Unmarshal([]byte(`{"unknown":[[1]],"id":1}`), &record)
+	requireLimitError(should, err, "MaxDepth", 2)
+	should.False(api.Valid([]byte(`[1,2,3]`)))
+	should.Error(api.ParseDocument([]byte(`{"a":1,"b":2,"c":3}`)).LastError())
+	requireLimitError(should, api.ParseDocument([]byte(`[1,2,3]`)).LastError(), "MaxElements", 2)
+	var records [][]int
+	err = api.UnmarshalParallel([]byte(`[[1],[2],[3]]`), &records, 2)
+	requireLimitError(should, err, "MaxElements", 2)
+
+	// elements decoded concurrently are nested in the array as with Unmarshal
+	shallow := jsoniter.Config{MaxDepth: 2}.Froze()
+	input := []byte(`[[[1]],[[2]]]`)
+	var nested [][][]int
+	requireLimitError(should, shallow.Unmarshal(input, &nested), "MaxDepth", 2)
+	requireLimitError(should, shallow.UnmarshalParallel(input, &nested, 2), "MaxDepth", 2)
+	should.NoError(shallow.Unmarshal([]byte(`[[1],[2]]`), &records))
+	should.NoError(shallow.UnmarshalParallel([]byte(`[[1],[2]]`), &records, 2))
+}
+
+func Test_limit_max_bytes_stream(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{MaxBytes: 12}.Froze()
+	decoder := api.NewDecoder(strings.NewReader("[1,2] [3,4] [5,6]"))
+	var values []int
+	should.NoError(decoder.Decode(&values))
+	should.Equal([]int{1, 2}, values)
+	should.NoError(decoder.Decode(&values))
+	should.Equal([]int{3, 4}, values)
+	err := decoder.Decode(&values)
+	limitErr := requireLimitError(should, err, "MaxBytes", 12)
+	should.Equal(int64(12), limitErr.Offset)
+}
+
+func Test_default_max_depth(t *testing.T) {
+	should := require.New(t)
+	input := strings.Repeat("[", 10001) + strings.Repeat("]", 10001)
+	var value interface{}
+	err := jsoniter.Unmarshal([]byte(input), &value)
+	requireLimitError(should, err, "MaxDepth", 10000)
+	should.NoError(jsoniter.Config{MaxDepth: 20000}.Froze().Unmarshal([]byte(input), &value))
+}
+
+func Test_decode_context(t *testing.T) {
+	should := require.New(t)
+	decoder := jsoniter.NewDecoder(strings.NewReader(`{"name":"hello"}`))
+	var record limitRecord
+	should.NoError(decoder.DecodeContext(context.Background(), &record))
+	should.Equal("hello", record.Name)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	decoder = jsoniter.NewDecoder(strings.NewReader(`{"name":"hello"}`))
+	should.Equal(context.Canceled, decoder.DecodeContext(ctx, &record))
+}
+
+func Test_decode_context_cancelled_while_reading(t *testing.T) {
+	should := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := &slowReader{input: []byte(`{"items":[1,2,3,4,5,6,7,8,9]}`), afterRead: func(read int) {
+		if read == 10 {
+			cancel()
+		}
+	}}
+	decoder := jsoniter.NewDecoder(reader)
+	var record limitRecord
+	err := decoder.DecodeContext(ctx, &record)
+	should.Equal(context.Canceled, err)
+	should.Equal(10, reader.read)
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	decoder = jsoniter.NewDecoder(io.MultiReader(strings.NewReader(`[1,`), strings.NewReader(`2]`)))
+	should.Equal(context.DeadlineExceeded, decoder.DecodeContext(ctx, &record.Items))
+}
+
+// slowReader reads one byte at a time, calling afterRead with the bytes read so far
+type slowReader struct {
+	input     []byte
+	read      int
+	afterRead func(read int)
+}
+
+func (reader *slowReader) Read(p []byte) (int, error) {
+	if reader.read == len(reader.input) {
+		return 0, io.EOF
+	}
+	p[0] = reader.input[reader.read]
+	reader.read++
+	reader.afterRead(reader.read)
+	return 1, nil
+}
+
+func iotestOneByteReader(input string) io.Reader {
+	return &slowReader{input: []byte(input), afterRead: func(int) {}}
+}
+
+func Test_limit_error_with_field_path(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{MaxStringLength: 3}.Froze()
+	var records []limitRecord
+	err := api.Unmarshal([]byte(`[{"name":"a"},{"attrs":{"k":"long"}}]`), &records)
+	limitErr := requireLimitError(should, err, "MaxStringLength", 3)
+	should.Equal("[1].Attrs", limitErr.Field)
+	should.True(strings.HasPrefix(err.Error(), "[1].Attrs"))
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"bytes"
 	"encoding/json"
 	"github.com/json-iterator/go"
 	"github.com/stretchr/testify/require"
@@ -34,3 +35,46 @@ func Test_marshal_indent_map(t *testing.T) {
 	should.Nil(err)
 	should.Equal("{\n  \"1\": 2\n}", string(output))
 }
+
+type marshalIndentRaw struct{}
+
+func (marshalIndentRaw) MarshalJSON() ([]byte, error) {
+	return []byte(`{"a":[1,2],"b":{}}`), nil
+}
+
+func Test_marshal_indent_prefix_and_tab(t *testing.T) {
+	should := require.New(t)
+	obj := map[string]interface{}{
+		"array":    []int{1, 2},
+		"empty":    []int{},
+		"emptyObj": struct{}{},
+		"emptyMap": map[string]int{},
+		"nested":   map[string]interface{}{"x": []interface{}{map[string]int{"y": 1}}},
+		"raw":      json.RawMessage(`[ 3 , {"z" : 4} ]`),
+		"marshal":  marshalIndentRaw{},
+	}
+	for _, args := range [][2]string{{"", "  "}, {">", "\t"}, {"//", ""}, {"", ""}, {"", "--"}} {
+		output1, err := json.MarshalIndent(obj, args[0], args[1])
+		should.Nil(err)
+		output2, err := jsoniter.ConfigCompatibleWithStandardLibrary.MarshalIndent(obj, args[0], args[1])
+		should.Nil(err)
+		should.Equal(string(output1), string(output2))
+	}
+}
+
+func Test_encoder_set_indent_prefix(t *testing.T) {
+	should := require.New(t)
+	obj := struct {
+		F1 int
+		F2 []int
+	}{1, []int{2, 3}}
+	buf1 := &bytes.Buffer{}
+	encoder1 := json.NewEncoder(buf1)
+	encoder1.SetIndent("#", "\t")
+	should.Nil(encoder1.Encode(obj))
+	buf2 := &bytes.Buffer{}
+	encoder2 := jsoniter.NewEncoder(buf2)
+	encoder2.SetIndent("#", "\t")
+	should.Nil(encoder2.Encode(obj))
+	should.Equal(buf1.String(), buf2.String())
+}
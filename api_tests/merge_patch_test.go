@@ -0,0 +1,123 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_merge_patch_rfc7396_examples(t *testing.T) {
+	should := require.New(t)
+	for _, testCase := range []struct {
+		original string
+		patch    string
+		expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	} {
+		patched, err := jsoniter.MergePatch([]byte(testCase.original), []byte(testCase.patch))
+		should.NoError(err)
+		should.Equal(testCase.expected, string(patched), testCase.original+" + "+testCase.patch)
+	}
+}
+
+func Test_merge_patch_keeps_untouched_bytes(t *testing.T) {
+	should := require.New(t)
+	original := `{ "z": 1.10, "big": 123456789012345678901234567890, "nested": {"b": [1, 2], "a": true}, "drop": 1 }`
+	patched, err := jsoniter.MergePatch([]byte(original), []byte(`{"nested":{"a":false},"drop":null,"new":1e2}`))
+	should.NoError(err)
+	should.Equal(`{"z":1.10,"big":123456789012345678901234567890,"nested":{"b":[1, 2],"a":false},"new":1e2}`, string(patched))
+	_, err = jsoniter.MergePatch([]byte(original), []byte(`{"a":`))
+	should.Error(err)
+}
+
+func Test_create_merge_patch(t *testing.T) {
+	should := require.New(t)
+	original := `{"a":1,"b":{"c":[1,2],"d":"x"},"e":"removed"}`
+	modified := `{"a":1, "b":{"c":[1, 2],"d":"y"},"f":{"g":1}}`
+	patch, err := jsoniter.CreateMergePatch([]byte(original), []byte(modified))
+	should.NoError(err)
+	should.Equal(`{"b":{"d":"y"},"e":null,"f":{"g":1}}`, string(patch))
+	patched, err := jsoniter.MergePatch([]byte(original), patch)
+	should.NoError(err)
+	should.Equal(`{"a":1,"b":{"c":[1,2],"d":"y"},"f":{"g":1}}`, string(patched))
+}
+
+func Test_merge_patch_into_struct(t *testing.T) {
+	type Address struct {
+		City   string `json:"city"`
+		Street string `json:"street"`
+	}
+	type Embedded struct {
+		Note string `json:"note"`
+	}
+	type Person struct {
+		Embedded
+		Name    string            `json:"name"`
+		Age     int               `json:"age,string"`
+		Address *Address          `json:"address"`
+		Tags    []string          `json:"tags"`
+		Labels  map[string]int    `json:"labels"`
+		Extra   interface{}       `json:"extra"`
+		Home    Address           `json:"home"`
+		Meta    map[string]string `json:"-"`
+	}
+	should := require.New(t)
+	person := Person{
+		Embedded: Embedded{Note: "note"},
+		Name:     "old",
+		Age:      30,
+		Address:  &Address{City: "Paris", Street: "Rue"},
+		Tags:     []string{"a", "b"},
+		Labels:   map[string]int{"x": 1, "y": 2},
+		Extra:    map[string]interface{}{"k": "v", "drop": true},
+		Home:     Address{City: "Lyon", Street: "Main"},
+		Meta:     map[string]string{"kept": "yes"},
+	}
+	address := person.Address
+	err := jsoniter.MergePatchInto(&person, []byte(`{
+		"name": "new", "age": "31", "note": null,
+		"address": {"city": "Berlin"},
+		"tags": ["c"],
+		"labels": {"x": null, "z": 3},
+		"extra": {"drop": null, "add": 1},
+		"home": {"street": null}
+	}`))
+	should.NoError(err)
+	should.Equal("new", person.Name)
+	should.Equal(31, person.Age)
+	should.Equal("", person.Note)
+	should.True(address == person.Address)
+	should.Equal(Address{City: "Berlin", Street: "Rue"}, *person.Address)
+	should.Equal([]string{"c"}, person.Tags)
+	should.Equal(map[string]int{"y": 2, "z": 3}, person.Labels)
+	should.Equal(map[string]interface{}{"k": "v", "add": float64(1)}, person.Extra)
+	should.Equal(Address{City: "Lyon"}, person.Home)
+	should.Equal(map[string]string{"kept": "yes"}, person.Meta)
+
+	should.NoError(jsoniter.MergePatchInto(&person, []byte(`{"address": null}`)))
+	should.Nil(person.Address)
+	err = jsoniter.MergePatchInto(&person, []byte(`{"home": {"city": 1}}`))
+	should.Error(err)
+	should.Contains(err.Error(), "home.city")
+	should.Error(jsoniter.MergePatchInto(person, []byte(`{}`)))
+
+	json5 := jsoniter.Config{AllowJSON5: true}.Froze()
+	should.NoError(json5.MergePatchInto(&person, []byte(`{extra: {k: 'w', add: null,},}`)))
+	should.Equal(map[string]interface{}{"k": "w"}, person.Extra)
+}
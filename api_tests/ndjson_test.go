@@ -0,0 +1,162 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type lineRecord struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func Test_line_reader(t *testing.T) {
+	should := require.New(t)
+	input := "{\"id\":1,\"name\":\"a\"}\r\n\n  \n{\"id\":2,\"name\":\"b\"}\n{\"id\":3}"
+	reader := jsoniter.NewLineReader(jsoniter.ConfigDefault, strings.NewReader(input))
+	records := []lineRecord{}
+	lines := []int{}
+	for {
+		var record lineRecord
+		err := reader.Next(&record)
+		if err == io.EOF {
+			break
+		}
+		should.NoError(err)
+		records = append(records, record)
+		lines = append(lines, reader.Line())
+	}
+	should.Equal([]lineRecord{{1, "a"}, {2, "b"}, {3, ""}}, records)
+	should.Equal([]int{1, 4, 5}, lines)
+}
+
+func Test_line_reader_error(t *testing.T) {
+	should := require.New(t)
+	input := "{\"id\":1}\n{\"id\":\"x\"}\n{\"id\":3}\n"
+	reader := jsoniter.NewLineReader(jsoniter.ConfigDefault, strings.NewReader(input))
+	var record lineRecord
+	should.NoError(reader.Next(&record))
+	err := reader.Next(&record)
+	lineErr, ok := err.(*jsoniter.LineError)
+	should.True(ok)
+	should.Equal(2, lineErr.Line)
+	should.Equal(`{"id":"x"}`, string(lineErr.Raw))
+	should.Contains(lineErr.Error(), "line 2: ")
+	should.NoError(reader.Next(&record))
+	should.Equal(3, record.ID)
+	should.Equal(io.EOF, reader.Next(&record))
+}
+
+func Test_line_reader_skip_bad_lines(t *testing.T) {
+	should := require.New(t)
+	input := "{\"id\":1}\nnot json\n{\"id\":2} {}\n{\"id\":3}\n"
+	skipped := []*jsoniter.LineError{}
+	reader := jsoniter.NewLineReader(jsoniter.ConfigDefault, strings.NewReader(input)).
+		SkipBadLines(func(err *jsoniter.LineError) {
+			skipped = append(skipped, err)
+		})
+	ids := []int{}
+	for {
+		var record lineRecord
+		err := reader.Next(&record)
+		if err == io.EOF {
+			break
+		}
+		should.NoError(err)
+		ids = append(ids, record.ID)
+	}
+	should.Equal([]int{1, 3}, ids)
+	should.Len(skipped, 2)
+	should.Equal(2, skipped[0].Line)
+	should.Equal("not json", string(skipped[0].Raw))
+	should.Equal(3, skipped[1].Line)
+}
+
+func Test_line_reader_long_line(t *testing.T) {
+	should := require.New(t)
+	name := strings.Repeat("x", 200*1024)
+	input := `{"name":"` + name + "\"}\n{\"id\":2}\n"
+	reader := jsoniter.NewLineReader(jsoniter.ConfigDefault, strings.NewReader(input))
+	var record lineRecord
+	should.NoError(reader.Next(&record))
+	should.Equal(name, record.Name)
+	should.NoError(reader.Next(&record))
+	should.Equal(2, record.ID)
+}
+
+func Test_line_writer(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{IndentionStep: 2}.Froze()
+	buf := &bytes.Buffer{}
+	writer := jsoniter.NewLineWriter(api, buf)
+	should.NoError(writer.Write(lineRecord{1, "a\nb"}))
+	should.NoError(writer.Write(map[string][]int{"x": {1, 2}}))
+	should.NoError(writer.Write(json.RawMessage("{\n  \"raw\": [\n    1\n  ]\n}")))
+	should.Error(writer.Write(json.RawMessage("{\n")))
+	should.NoError(writer.Write(nil))
+	should.NoError(writer.Close())
+	should.Equal("{\"id\":1,\"name\":\"a\\nb\"}\n{\"x\":[1,2]}\n{\"raw\":[1]}\nnull\n", buf.String())
+	// the config keeps indention
+	output, err := api.Marshal([]int{1})
+	should.NoError(err)
+	should.Equal("[\n  1\n]", string(output))
+}
+
+type lineRawWritten struct{}
+
+func Test_line_writer_encoder_writing_stream(t *testing.T) {
+	should := require.New(t)
+	registry := jsoniter.NewRegistry()
+	registry.RegisterTypeEncoderFunc("test.lineRawWritten", func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		stream.Write([]byte(`"raw"`))
+	}, nil)
+	api := jsoniter.Config{Registry: registry, IndentPrefix: ">", Indent: "\t"}.Froze()
+	buf := &bytes.Buffer{}
+	writer := jsoniter.NewLineWriter(api, buf)
+	should.NoError(writer.Write(lineRecord{1, "a"}))
+	should.NoError(writer.Write([]lineRawWritten{{}, {}}))
+	should.Equal(0, buf.Len())
+	should.NoError(writer.Close())
+	should.Equal("{\"id\":1,\"name\":\"a\"}\n[\"raw\",\"raw\"]\n", buf.String())
+}
+
+func Test_line_writer_sorted_map_and_any(t *testing.T) {
+	should := require.New(t)
+	buf := &bytes.Buffer{}
+	writer := jsoniter.NewLineWriter(jsoniter.ConfigCompatibleWithStandardLibrary, buf)
+	should.NoError(writer.Write(map[string]int{"b": 1, "a": 2}))
+	should.NoError(writer.Write(jsoniter.Get([]byte(`{"x":[1, 2]}`))))
+	should.Equal(0, buf.Len())
+	should.NoError(writer.Close())
+	should.Equal("{\"a\":2,\"b\":1}\n{\"x\":[1, 2]}\n", buf.String())
+}
+
+func Test_line_writer_round_trip(t *testing.T) {
+	should := require.New(t)
+	buf := &bytes.Buffer{}
+	writer := jsoniter.NewLineWriter(jsoniter.ConfigDefault, buf)
+	for i := 0; i < 10000; i++ {
+		should.NoError(writer.Write(lineRecord{ID: i, Name: "record"}))
+	}
+	should.NoError(writer.Close())
+	reader := jsoniter.NewLineReader(jsoniter.ConfigDefault, buf)
+	count := 0
+	for {
+		var record lineRecord
+		err := reader.Next(&record)
+		if err == io.EOF {
+			break
+		}
+		should.NoError(err)
+		should.Equal(count, record.ID)
+		count++
+	}
+	should.Equal(10000, count)
+}
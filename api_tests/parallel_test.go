@@ -0,0 +1,145 @@
+package test
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type parallelRecord struct {
+	ID   int               `json:"id"`
+	Tags []string          `json:"tags"`
+	Meta map[string]string `json:"meta"`
+}
+
+func parallelInput(n int) []byte {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = `{"id":` + strconv.Itoa(i) + `,"tags":["a","b"],"meta":{"k":"v"}}`
+	}
+	return []byte("[\n" + strings.Join(parts, ",\n") + "\n]")
+}
+
+func Test_unmarshal_parallel(t *testing.T) {
+	should := require.New(t)
+	data := parallelInput(5000)
+	var expected []parallelRecord
+	should.NoError(jsoniter.ConfigDefault.Unmarshal(data, &expected))
+	for _, workers := range []int{0, 1, 3, 16} {
+		var records []parallelRecord
+		should.NoError(jsoniter.ConfigDefault.UnmarshalParallel(data, &records, workers))
+		should.Equal(expected, records)
+	}
+	var empty []int
+	should.NoError(jsoniter.ConfigDefault.UnmarshalParallel([]byte(` [ ] `), &empty, 4))
+	should.NotNil(empty)
+	should.Len(empty, 0)
+	ints := []int{1}
+	should.NoError(jsoniter.ConfigDefault.UnmarshalParallel([]byte(`null`), &ints, 4))
+	should.Nil(ints)
+	var obj map[string]int
+	should.NoError(jsoniter.ConfigDefault.UnmarshalParallel([]byte(`{"a":1}`), &obj, 4))
+	should.Equal(map[string]int{"a": 1}, obj)
+}
+
+func Test_unmarshal_parallel_error(t *testing.T) {
+	should := require.New(t)
+	inputs := []string{
+		`[1,2,"x",4,"y"]`,
+		`[1,2,3`,
+		`[1,2}`,
+		`[1,2] 3`,
+		`[{"id":1},{"id":"2"}]`,
+	}
+	for _, input := range inputs {
+		data := []byte(input)
+		if strings.Contains(input, "id") {
+			var expected, records []parallelRecord
+			expectedErr := jsoniter.ConfigDefault.Unmarshal(data, &expected)
+			should.Error(expectedErr)
+			should.Equal(expectedErr.Error(), jsoniter.ConfigDefault.UnmarshalParallel(data, &records, 4).Error())
+			continue
+		}
+		var expected, ints []int
+		expectedErr := jsoniter.ConfigDefault.Unmarshal(data, &expected)
+		should.Error(expectedErr, input)
+		err := jsoniter.ConfigDefault.UnmarshalParallel(data, &ints, 4)
+		should.Error(err, input)
+		should.Equal(expectedErr.Error(), err.Error(), input)
+	}
+	// the first failed element is reported, however the elements are scheduled
+	parts := make([]string, 10000)
+	for i := range parts {
+		parts[i] = strconv.Itoa(i)
+	}
+	parts[7000] = `"bad"`
+	parts[100] = `"first"`
+	data := []byte("[" + strings.Join(parts, ",") + "]")
+	var expected, ints []int
+	expectedErr := jsoniter.ConfigDefault.Unmarshal(data, &expected)
+	for i := 0; i < 10; i++ {
+		err := jsoniter.ConfigDefault.UnmarshalParallel(data, &ints, 8)
+		should.Equal(expectedErr.Error(), err.Error())
+	}
+}
+
+func Test_line_reader_read_batch(t *testing.T) {
+	should := require.New(t)
+	lines := []string{}
+	for i := 1; i <= 25; i++ {
+		lines = append(lines, `{"id":`+strconv.Itoa(i)+`}`)
+	}
+	lines[11] = `{"id":"bad"}`
+	lines = append(lines, "", "")
+	input := strings.Join(lines, "\n")
+	reader := jsoniter.NewLineReader(jsoniter.ConfigDefault, strings.NewReader(input))
+	ids := []int{}
+	var batch []parallelRecord
+	var lineErr *jsoniter.LineError
+	for {
+		err := reader.ReadBatch(&batch, 10, 4)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var ok bool
+			lineErr, ok = err.(*jsoniter.LineError)
+			should.True(ok)
+		}
+		for _, record := range batch {
+			ids = append(ids, record.ID)
+		}
+	}
+	should.NotNil(lineErr)
+	should.Equal(12, lineErr.Line)
+	should.Equal(`{"id":"bad"}`, string(lineErr.Raw))
+	expected := []int{}
+	for i := 1; i <= 25; i++ {
+		if i != 12 {
+			expected = append(expected, i)
+		}
+	}
+	should.Equal(expected, ids)
+}
+
+func Test_line_reader_read_batch_skip(t *testing.T) {
+	should := require.New(t)
+	input := "{\"id\":1,\"tags\":[\"a\"]}\nbad\n{\"id\":3}\n{\"id\":4} 1\n{\"id\":5}\n"
+	skipped := []int{}
+	reader := jsoniter.NewLineReader(jsoniter.ConfigDefault, strings.NewReader(input)).
+		SkipBadLines(func(err *jsoniter.LineError) {
+			skipped = append(skipped, err.Line)
+		})
+	var batch []parallelRecord
+	should.NoError(reader.ReadBatch(&batch, 100, 2))
+	should.Equal([]parallelRecord{{ID: 1, Tags: []string{"a"}}, {ID: 3}, {ID: 5}}, batch)
+	should.Equal([]int{2, 4}, skipped)
+	should.Equal(5, reader.Line())
+	should.Equal(io.EOF, reader.ReadBatch(&batch, 100, 2))
+	should.Len(batch, 0)
+	should.Error(reader.ReadBatch(batch, 100, 2))
+}
@@ -0,0 +1,174 @@
+package test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/json-iterator/go"
+	"github.com/json-iterator/go/schema"
+	"github.com/modern-go/reflect2"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaOfBase struct {
+	ID      int64  `json:"id"`
+	Created uint32 `json:",omitempty"`
+}
+
+type schemaOfExtra struct {
+	Note string
+}
+
+type schemaOfNode struct {
+	schemaOfBase
+	*schemaOfExtra
+	Name     string            `json:"name"`
+	Count    int               `json:"count,string"`
+	Price    float64           `json:"price,omitempty"`
+	Children []*schemaOfNode   `json:"children"`
+	Labels   map[int]string    `json:"labels"`
+	Raw      json.RawMessage   `json:"raw"`
+	Data     []byte            `json:"data"`
+	Pair     [2]bool           `json:"pair"`
+	Meta     struct{ A bool }  `json:"meta"`
+	Ignored  string            `json:"-"`
+	Any      interface{}       `json:"any"`
+	Parent   *schemaOfNode     `json:"parent"`
+	Attrs    map[string]string `json:"attrs,omitempty"`
+}
+
+func Test_schema_of_struct(t *testing.T) {
+	should := require.New(t)
+	data, err := jsoniter.ConfigDefault.SchemaOf(reflect2.TypeOf(schemaOfNode{}))
+	should.NoError(err)
+	should.Equal(`{"$defs":{"test.schemaOfNode":{"properties":{`+
+		`"Created":{"minimum":0,"type":"integer"},`+
+		`"Note":{"type":"string"},`+
+		`"any":{},`+
+		`"attrs":{"additionalProperties":{"type":"string"},"type":["object","null"]},`+
+		`"children":{"items":{"anyOf":[{"$ref":"#/$defs/test.schemaOfNode"},{"type":"null"}]},"type":["array","null"]},`+
+		`"count":{"type":"string"},`+
+		`"data":{"contentEncoding":"base64","type":["string","null"]},`+
+		`"id":{"type":"integer"},`+
+		`"labels":{"additionalProperties":{"type":"string"},"propertyNames":{"pattern":"^-?[0-9]+$"},"type":["object","null"]},`+
+		`"meta":{"properties":{"A":{"type":"boolean"}},"required":["A"],"type":"object"},`+
+		`"name":{"type":"string"},`+
+		`"pair":{"items":{"type":"boolean"},"maxItems":2,"minItems":2,"type":"array"},`+
+		`"parent":{"anyOf":[{"$ref":"#/$defs/test.schemaOfNode"},{"type":"null"}]},`+
+		`"price":{"type":"number"},`+
+		`"raw":{}},`+
+		`"required":["id","name","count","children","labels","raw","data","pair","meta","any","parent"],"type":"object"}},`+
+		`"$ref":"#/$defs/test.schemaOfNode","$schema":"https://json-schema.org/draft/2020-12/schema"}`, string(data))
+
+	compiled, err := schema.Compile(data)
+	should.NoError(err)
+	node := schemaOfNode{Name: "root", Children: []*schemaOfNode{{Name: "child"}}, Raw: json.RawMessage(`[1]`)}
+	output, err := jsoniter.ConfigDefault.Marshal(node)
+	should.NoError(err)
+	should.NoError(compiled.Validate(output))
+	should.Error(compiled.Validate([]byte(strings.Replace(string(output), `"count":"0"`, `"count":0`, 1))))
+}
+
+type schemaOfInner struct {
+	A int `json:"a"`
+}
+
+func Test_schema_of_tag_options(t *testing.T) {
+	should := require.New(t)
+	type options struct {
+		A int           `json:"a"`
+		B int           `json:"b,required"`
+		C time.Time     `json:"c,omitzero"`
+		D schemaOfInner `json:"d,omitzero"`
+		E int           `json:"e,default=3"`
+	}
+	data, err := jsoniter.ConfigDefault.SchemaOf(reflect2.TypeOf(options{}))
+	should.NoError(err)
+	should.Contains(string(data), `"test.options":{"properties":{"a":{"type":"integer"},"b":{"type":"integer"},`+
+		`"c":{},"d":{"$ref":"#/$defs/test.schemaOfInner"},"e":{"default":3,"type":"integer"}},`+
+		`"required":["a","b","e"],"type":"object"}`)
+	compiled := schema.MustCompile(data)
+	output, err := jsoniter.ConfigDefault.Marshal(options{})
+	should.NoError(err)
+	should.Equal(`{"a":0,"b":0,"e":0}`, string(output))
+	should.NoError(compiled.Validate(output))
+
+	type invalidDefault struct {
+		A int `json:"a,default=abc"`
+	}
+	_, err = jsoniter.ConfigDefault.SchemaOf(reflect2.TypeOf(invalidDefault{}))
+	should.Error(err)
+	should.Contains(err.Error(), "invalidDefault.A: invalid default")
+}
+
+func Test_schema_of_inline_map(t *testing.T) {
+	should := require.New(t)
+	type withExtra struct {
+		ID    int            `json:"id"`
+		Extra map[string]int `json:",extra"`
+	}
+	type withAny struct {
+		ID    int          `json:"id"`
+		Extra jsoniter.Any `json:",inline"`
+	}
+	api := jsoniter.Config{DisallowUnknownFields: true}.Froze()
+	data, err := api.SchemaOf(reflect2.TypeOf(withExtra{}))
+	should.NoError(err)
+	should.Contains(string(data), `"additionalProperties":{"type":"integer"},"properties":{"id":{"type":"integer"}}`)
+	compiled := schema.MustCompile(data)
+	output, err := api.Marshal(withExtra{ID: 1, Extra: map[string]int{"zz": 2}})
+	should.NoError(err)
+	should.NoError(compiled.Validate(output))
+	should.Error(compiled.Validate([]byte(`{"id":1,"zz":"x"}`)))
+
+	data, err = api.SchemaOf(reflect2.TypeOf(withAny{}))
+	should.NoError(err)
+	should.Contains(string(data), `"additionalProperties":{},"properties":{"id":{"type":"integer"}}`)
+	output, err = api.Marshal(withAny{ID: 1, Extra: jsoniter.Wrap(map[string]interface{}{"zz": []int{1}})})
+	should.NoError(err)
+	should.Equal(`{"id":1,"zz":[1]}`, string(output))
+	should.NoError(schema.MustCompile(data).Validate(output))
+}
+
+type schemaOfRenameExtension struct {
+	jsoniter.DummyExtension
+}
+
+func (extension *schemaOfRenameExtension) UpdateStructDescriptor(structDescriptor *jsoniter.StructDescriptor) {
+	for _, binding := range structDescriptor.Fields {
+		binding.ToNames = []string{strings.ToUpper(binding.Field.Name())}
+	}
+}
+
+func Test_schema_of_follows_config(t *testing.T) {
+	should := require.New(t)
+	type Item struct {
+		Name  string `json:"name" xml:"label"`
+		Float float64
+		Extra string `xml:"-"`
+	}
+	api := jsoniter.Config{TagKey: "xml", OnlyTaggedField: true, DisallowUnknownFields: true,
+		MarshalFloatWith6Digits: true}.Froze()
+	data, err := api.SchemaOf(reflect2.TypeOf([]Item{}))
+	should.NoError(err)
+	should.Equal(`{"$defs":{"test.Item":{"additionalProperties":false,"properties":{"label":{"type":"string"}},`+
+		`"required":["label"],"type":"object"}},"$schema":"https://json-schema.org/draft/2020-12/schema",`+
+		`"items":{"$ref":"#/$defs/test.Item"},"type":["array","null"]}`, string(data))
+	compiled := schema.MustCompile(data)
+	output, err := api.Marshal([]Item{{Name: "a", Float: 1.5}})
+	should.NoError(err)
+	should.NoError(compiled.Validate(output))
+	should.Error(compiled.Validate([]byte(`[{"label":"a","Float":1}]`)))
+
+	api = jsoniter.Config{}.Froze()
+	api.RegisterExtension(&schemaOfRenameExtension{})
+	data, err = api.SchemaOf(reflect2.TypeOf(&Item{}))
+	should.NoError(err)
+	should.Contains(string(data), `"properties":{"EXTRA":{"type":"string"},"FLOAT":{"type":"number"},"NAME":{"type":"string"}}`)
+	should.Contains(string(data), `"anyOf":[{"$ref":"#/$defs/test.Item"},{"type":"null"}]`)
+
+	_, err = api.SchemaOf(reflect2.TypeOf(map[[2]int]int{}))
+	should.Error(err)
+}
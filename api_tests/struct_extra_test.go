@@ -0,0 +1,121 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type extraOneField struct {
+	ID    int                            `json:"id"`
+	Extra map[string]jsoniter.RawMessage `json:",extra"`
+}
+
+type extraThreeFields struct {
+	ID    int                    `json:"id"`
+	Name  string                 `json:"name"`
+	Tags  []string               `json:"tags"`
+	Extra map[string]interface{} `json:",extra"`
+}
+
+type extraTenFields struct {
+	F1    int          `json:"f1"`
+	F2    int          `json:"f2"`
+	F3    int          `json:"f3"`
+	F4    int          `json:"f4"`
+	F5    int          `json:"f5"`
+	F6    int          `json:"f6"`
+	F7    int          `json:"f7"`
+	F8    int          `json:"f8"`
+	F9    int          `json:"f9"`
+	F10   int          `json:"f10"`
+	Extra jsoniter.Any `json:",extra"`
+}
+
+func Test_struct_extra_raw_message(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{SortMapKeys: true}.Froze()
+	input := `{"z":{"nested":[1, 2]},"id":1,"a\"b":"x","n":null}`
+	var decoded extraOneField
+	should.NoError(api.UnmarshalFromString(input, &decoded))
+	should.Equal(1, decoded.ID)
+	should.Equal(map[string]jsoniter.RawMessage{
+		"z":   jsoniter.RawMessage(`{"nested":[1, 2]}`),
+		`a"b`: jsoniter.RawMessage(`"x"`),
+		"n":   nil,
+	}, decoded.Extra)
+	output, err := api.MarshalToString(decoded)
+	should.NoError(err)
+	should.Equal(`{"id":1,"a\"b":"x","n":null,"z":{"nested":[1, 2]}}`, output)
+
+	decoded = extraOneField{}
+	should.NoError(api.UnmarshalFromString(`{"ID":2}`, &decoded))
+	should.Equal(extraOneField{ID: 2}, decoded)
+	caseSensitive := jsoniter.Config{CaseSensitive: true}.Froze()
+	should.NoError(caseSensitive.UnmarshalFromString(`{"ID":2}`, &decoded))
+	should.Equal(jsoniter.RawMessage(`2`), decoded.Extra["ID"])
+
+	// the keys of the fields are not written twice
+	output, err = api.MarshalToString(extraOneField{ID: 1, Extra: map[string]jsoniter.RawMessage{"id": []byte(`2`), "b": []byte(`3`)}})
+	should.NoError(err)
+	should.Equal(`{"id":1,"b":3}`, output)
+}
+
+func Test_struct_extra_interface_map(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{SortMapKeys: true}.Froze()
+	var decoded extraThreeFields
+	should.NoError(api.UnmarshalFromString(`{"name":"n","version":2,"id":1,"meta":{"a":true}}`, &decoded))
+	should.Equal(extraThreeFields{ID: 1, Name: "n", Extra: map[string]interface{}{
+		"version": float64(2),
+		"meta":    map[string]interface{}{"a": true},
+	}}, decoded)
+	output, err := api.MarshalToString(&decoded)
+	should.NoError(err)
+	should.Equal(`{"id":1,"name":"n","tags":null,"meta":{"a":true},"version":2}`, output)
+
+	// captured into the existing map
+	should.NoError(api.UnmarshalFromString(`{"more":"x"}`, &decoded))
+	should.Len(decoded.Extra, 3)
+	json5 := jsoniter.Config{AllowJSON5: true}.Froze()
+	decoded = extraThreeFields{}
+	should.NoError(json5.UnmarshalFromString(`{id: 1, other: 'o', 'quoted': 2,}`, &decoded))
+	should.Equal(map[string]interface{}{"other": "o", "quoted": float64(2)}, decoded.Extra)
+	should.Error(api.UnmarshalFromString(`{"id":1,"other":}`, &decoded))
+}
+
+func Test_struct_extra_any(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{SortMapKeys: true}.Froze()
+	var decoded extraTenFields
+	should.NoError(api.UnmarshalFromString(`{"f1":1,"x":{"y":[1,2]},"f10":10,"w":"v"}`, &decoded))
+	should.Equal(1, decoded.F1)
+	should.Equal(10, decoded.F10)
+	should.Equal(jsoniter.ObjectValue, decoded.Extra.ValueType())
+	should.Equal(2, decoded.Extra.Get("x", "y", 1).ToInt())
+	should.Equal("v", decoded.Extra.Get("w").ToString())
+	output, err := api.MarshalToString(decoded)
+	should.NoError(err)
+	should.Equal(`{"f1":1,"f2":0,"f3":0,"f4":0,"f5":0,"f6":0,"f7":0,"f8":0,"f9":0,"f10":10,"w":"v","x":{"y":[1,2]}}`, output)
+
+	// members of the object already set are kept
+	preset := extraTenFields{Extra: jsoniter.Get([]byte(`{"kept":1}`))}
+	should.NoError(api.UnmarshalFromString(`{"added":2}`, &preset))
+	should.Equal(1, preset.Extra.Get("kept").ToInt())
+	should.Equal(2, preset.Extra.Get("added").ToInt())
+	output, err = api.MarshalToString(extraTenFields{Extra: jsoniter.Wrap(1)})
+	should.NoError(err)
+	should.Equal(`{"f1":0,"f2":0,"f3":0,"f4":0,"f5":0,"f6":0,"f7":0,"f8":0,"f9":0,"f10":0}`, output)
+	output, err = api.MarshalToString(extraTenFields{Extra: jsoniter.Wrap(map[string]int{"f1": 1, "f11": 11})})
+	should.NoError(err)
+	should.Equal(`{"f1":0,"f2":0,"f3":0,"f4":0,"f5":0,"f6":0,"f7":0,"f8":0,"f9":0,"f10":0,"f11":11}`, output)
+
+	var empty struct {
+		Extra jsoniter.Any `json:",extra"`
+	}
+	should.NoError(api.UnmarshalFromString(`{"a":1}`, &empty))
+	output, err = api.MarshalToString(empty)
+	should.NoError(err)
+	should.Equal(`{"a":1}`, output)
+}
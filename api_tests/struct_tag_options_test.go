@@ -0,0 +1,277 @@
+package test
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type requiredTwoFields struct {
+	ID   int    `json:"id,required"`
+	Name string `json:"name"`
+}
+
+type requiredManyFields struct {
+	F1  int `json:"f1"`
+	F2  int `json:"f2"`
+	F3  int `json:"f3"`
+	F4  int `json:"f4"`
+	F5  int `json:"f5"`
+	F6  int `json:"f6"`
+	F7  int `json:"f7"`
+	F8  int `json:"f8"`
+	F9  int `json:"f9"`
+	F10 int `json:"f10"`
+	F11 int `json:"f11,required"`
+}
+
+type requiredNested struct {
+	ID    int             `json:"id,required"`
+	Child *requiredNested `json:"child"`
+}
+
+func Test_struct_tag_required(t *testing.T) {
+	should := require.New(t)
+	var two requiredTwoFields
+	should.NoError(jsoniter.UnmarshalFromString(`{"name":"a","id":1}`, &two))
+	should.Equal(requiredTwoFields{1, "a"}, two)
+	should.NoError(jsoniter.UnmarshalFromString(`{"id":null}`, &two))
+	err := jsoniter.UnmarshalFromString(`{"name":"a"}`, &two)
+	should.Error(err)
+	should.Contains(err.Error(), "required field id is missing")
+	should.NoError(jsoniter.UnmarshalFromString(`null`, &two))
+
+	var many requiredManyFields
+	should.NoError(jsoniter.UnmarshalFromString(`{"f11":11}`, &many))
+	should.Error(jsoniter.UnmarshalFromString(`{"f1":1}`, &many))
+	strict := jsoniter.Config{DisallowUnknownFields: true}.Froze()
+	should.Error(strict.UnmarshalFromString(`{"name":"a"}`, &two))
+	should.NoError(strict.UnmarshalFromString(`{"ID":2}`, &two))
+
+	var nested requiredNested
+	should.NoError(jsoniter.UnmarshalFromString(`{"id":1,"child":{"id":2}}`, &nested))
+	should.Equal(2, nested.Child.ID)
+	should.Error(jsoniter.UnmarshalFromString(`{"child":{"id":2}}`, &nested))
+	should.Error(jsoniter.UnmarshalFromString(`{"id":1,"child":{}}`, &nested))
+	should.NoError(jsoniter.UnmarshalFromString(`[{"id":1},{"id":2,"child":null}]`, &[]requiredNested{}))
+}
+
+type defaultOptions struct {
+	Port  int            `json:"port,default=8080"`
+	Host  string         `json:"host,omitempty,default=\"localhost\""`
+	Tags  []string       `json:"tags,default=[\"a\",\"b\"]"`
+	Attrs map[string]int `json:"attrs,default={\"x\":1,\"y\":2}"`
+	DefaultLevel
+}
+
+type DefaultLevel struct {
+	Level int `json:"level,default=3"`
+}
+
+func Test_struct_tag_default(t *testing.T) {
+	should := require.New(t)
+	var options defaultOptions
+	should.NoError(jsoniter.UnmarshalFromString(`{}`, &options))
+	should.Equal(defaultOptions{
+		Port:         8080,
+		Host:         "localhost",
+		Tags:         []string{"a", "b"},
+		Attrs:        map[string]int{"x": 1, "y": 2},
+		DefaultLevel: DefaultLevel{3},
+	}, options)
+
+	options = defaultOptions{}
+	should.NoError(jsoniter.UnmarshalFromString(`{"port":0,"host":"example.com","tags":null,"level":1}`, &options))
+	should.Equal(defaultOptions{
+		Host:         "example.com",
+		Attrs:        map[string]int{"x": 1, "y": 2},
+		DefaultLevel: DefaultLevel{1},
+	}, options)
+
+	var invalid struct {
+		Port int `json:"port,default=abc"`
+	}
+	// reported when the decoder is created, whether the field is present or not
+	err := jsoniter.UnmarshalFromString(`{"port":1}`, &invalid)
+	should.Error(err)
+	should.Contains(err.Error(), "invalid default of field port: readUint64: expect number")
+	should.Equal(1, strings.Count(err.Error(), "invalid default"))
+	var trailing struct {
+		Nested struct {
+			Port int `json:"port,default=1x"`
+		}
+	}
+	err = jsoniter.UnmarshalFromString(`{}`, &trailing)
+	should.Error(err)
+	should.Contains(err.Error(), "invalid default of field port: ReadObject: there are bytes left after the default")
+}
+
+type defaultThenOptions struct {
+	Port int      `json:"port,default=1,omitempty"`
+	Tags []string `json:"tags,default=[\"a\",\"b\"],omitempty"`
+	Name string   `json:"name,default=\"x,y\",required"`
+}
+
+func Test_struct_tag_options_after_default(t *testing.T) {
+	should := require.New(t)
+	var options defaultThenOptions
+	should.NoError(jsoniter.UnmarshalFromString(`{"name":"z"}`, &options))
+	should.Equal(defaultThenOptions{Port: 1, Tags: []string{"a", "b"}, Name: "z"}, options)
+	should.Error(jsoniter.UnmarshalFromString(`{}`, &options))
+	output, err := jsoniter.MarshalToString(defaultThenOptions{})
+	should.NoError(err)
+	should.Equal(`{"name":""}`, output)
+}
+
+type defaultNode struct {
+	Value int          `json:"value,default=1"`
+	Next  *defaultNode `json:"next,default={\"next\":null}"`
+}
+
+func Test_struct_tag_default_of_recursive_type(t *testing.T) {
+	should := require.New(t)
+	var node defaultNode
+	should.NoError(jsoniter.UnmarshalFromString(`{"value":2}`, &node))
+	should.Equal(defaultNode{Value: 2, Next: &defaultNode{Value: 1}}, node)
+}
+
+type zeroByMethod struct {
+	Value int
+}
+
+func (value zeroByMethod) IsZero() bool {
+	return value.Value <= 0
+}
+
+type zeroByPtrMethod struct {
+	Value int
+}
+
+func (value *zeroByPtrMethod) IsZero() bool {
+	return value.Value == 42
+}
+
+type omitZeroInner struct {
+	Inner int `json:"inner,omitzero"`
+}
+
+func Test_struct_tag_omitzero(t *testing.T) {
+	should := require.New(t)
+	type omitZero struct {
+		Time    time.Time       `json:"time,omitzero"`
+		Struct  struct{ A int } `json:"struct,omitzero"`
+		Method  zeroByMethod    `json:"method,omitzero"`
+		Ptr     zeroByPtrMethod `json:"ptr,omitzero"`
+		Slice   []int           `json:"slice,omitzero"`
+		Float   float64         `json:"float,omitzero"`
+		Array   [2]string       `json:"array,omitzero"`
+		Pointer *zeroByMethod   `json:"pointer,omitzero"`
+		Iface   interface{}     `json:"iface,omitzero"`
+		Both    string          `json:"both,omitempty,omitzero"`
+		*omitZeroInner
+	}
+	output, err := jsoniter.MarshalToString(omitZero{Method: zeroByMethod{-1}, Ptr: zeroByPtrMethod{42}})
+	should.NoError(err)
+	should.Equal(`{}`, output)
+	output, err = jsoniter.MarshalToString(omitZero{
+		Time:          time.Unix(0, 0).UTC(),
+		Struct:        struct{ A int }{1},
+		Method:        zeroByMethod{1},
+		Ptr:           zeroByPtrMethod{0},
+		Slice:         []int{},
+		Float:         math.Copysign(0, -1),
+		Array:         [2]string{"", "b"},
+		Pointer:       &zeroByMethod{1},
+		Iface:         0,
+		omitZeroInner: &omitZeroInner{1},
+	})
+	should.NoError(err)
+	should.Equal(`{"time":"1970-01-01T00:00:00Z","struct":{"A":1},"method":{"Value":1},"ptr":{"Value":0},`+
+		`"slice":[],"float":-0,"array":["","b"],"pointer":{"Value":1},"iface":0,"inner":1}`, output)
+}
+
+type inlineChild struct {
+	A int `json:"a"`
+	B int `json:"b"`
+}
+
+type inlineExtraHolder struct {
+	Extra map[string]interface{} `json:",inline"`
+}
+
+func Test_struct_tag_inline_struct(t *testing.T) {
+	should := require.New(t)
+	type parent struct {
+		Name  string      `json:"name"`
+		Child inlineChild `json:"child,inline"`
+		B     string      `json:"b"`
+	}
+	output, err := jsoniter.MarshalToString(parent{Name: "n", Child: inlineChild{1, 2}, B: "top"})
+	should.NoError(err)
+	should.Equal(`{"name":"n","a":1,"b":"top"}`, output)
+	var decoded parent
+	should.NoError(jsoniter.UnmarshalFromString(`{"name":"n","a":3,"b":"top"}`, &decoded))
+	should.Equal(parent{Name: "n", Child: inlineChild{A: 3}, B: "top"}, decoded)
+
+	type pointer struct {
+		Child *inlineChild `json:",inline"`
+	}
+	output, err = jsoniter.MarshalToString(pointer{})
+	should.NoError(err)
+	should.Equal(`{}`, output)
+	var decodedPtr pointer
+	should.NoError(jsoniter.UnmarshalFromString(`{"a":1,"b":2}`, &decodedPtr))
+	should.Equal(&inlineChild{1, 2}, decodedPtr.Child)
+	output, err = jsoniter.MarshalToString(decodedPtr)
+	should.NoError(err)
+	should.Equal(`{"a":1,"b":2}`, output)
+}
+
+func Test_struct_tag_inline_map(t *testing.T) {
+	should := require.New(t)
+	type event struct {
+		ID    int                    `json:"id"`
+		Extra map[string]interface{} `json:",inline"`
+	}
+	api := jsoniter.Config{SortMapKeys: true}.Froze()
+	var decoded event
+	should.NoError(api.UnmarshalFromString(`{"z":[1],"id":1,"a":"x"}`, &decoded))
+	should.Equal(event{ID: 1, Extra: map[string]interface{}{"z": []interface{}{float64(1)}, "a": "x"}}, decoded)
+	output, err := api.MarshalToString(decoded)
+	should.NoError(err)
+	should.Equal(`{"id":1,"a":"x","z":[1]}`, output)
+	output, err = api.MarshalToString(event{ID: 2})
+	should.NoError(err)
+	should.Equal(`{"id":2}`, output)
+	strict := jsoniter.Config{DisallowUnknownFields: true, ObjectFieldMustBeSimpleString: true}.Froze()
+	decoded = event{}
+	should.NoError(strict.UnmarshalFromString(`{"unknown":true}`, &decoded))
+	should.Equal(map[string]interface{}{"unknown": true}, decoded.Extra)
+
+	type onlyMap struct {
+		Values map[string]int `json:",inline"`
+	}
+	output, err = api.MarshalToString(onlyMap{map[string]int{"b": 2, "a": 1}})
+	should.NoError(err)
+	should.Equal(`{"a":1,"b":2}`, output)
+	var values onlyMap
+	should.Error(api.UnmarshalFromString(`{"a":"x"}`, &values))
+
+	type embedding struct {
+		ID int `json:"id"`
+		*inlineExtraHolder
+	}
+	var embedded embedding
+	should.NoError(api.UnmarshalFromString(`{"id":1,"other":2}`, &embedded))
+	should.Equal(map[string]interface{}{"other": float64(2)}, embedded.Extra)
+	output, err = api.MarshalToString(embedding{ID: 3})
+	should.NoError(err)
+	should.Equal(`{"id":3}`, output)
+	indented, err := api.MarshalIndent(embedded, "", "  ")
+	should.NoError(err)
+	should.Equal(strings.Join([]string{`{`, `  "id": 1,`, `  "other": 2`, `}`}, "\n"), string(indented))
+}
@@ -0,0 +1,243 @@
+package jsoniter
+
+import (
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Canonicalize rewrites JSON data in the canonical form of RFC 8785, JSON Canonicalization Scheme:
+// no whitespace, object members sorted by the UTF-16 code units of their keys,
+// numbers serialized as ECMAScript does and strings with the minimal escaping.
+// Numbers out of the range of float64, invalid UTF-8 and duplicate keys are reported as error.
+func Canonicalize(data []byte) ([]byte, error) {
+	return ConfigDefault.(*frozenConfig).appendCanonical(nil, data)
+}
+
+// canonicalConfig reads back the values encoded in canonical mode, strictly and without limits,
+// as the decoding options of the stream config are not meant for its output
+var canonicalConfig = Config{MaxDepth: math.MaxInt32}.Froze().(*frozenConfig)
+
+// canonicalize rewrites the value written to stream since start in canonical form
+func (stream *Stream) canonicalize(start int) {
+	if stream.Error != nil {
+		return
+	}
+	data := append([]byte(nil), stream.buf[start:]...)
+	var err error
+	stream.buf, err = canonicalConfig.appendCanonical(stream.buf[:start], data)
+	if err != nil {
+		stream.Error = err
+	}
+}
+
+func (cfg *frozenConfig) appendCanonical(buf []byte, data []byte) ([]byte, error) {
+	iter := cfg.BorrowIterator(data)
+	defer cfg.ReturnIterator(iter)
+	buf = appendCanonicalValue(buf, iter)
+	if iter.Error == nil || iter.Error == io.EOF {
+		if c := iter.nextToken(); c != 0 {
+			iter.ReportError("Canonicalize", "there are bytes left after the value")
+		}
+	}
+	if iter.Error != nil && iter.Error != io.EOF {
+		return nil, iter.Error
+	}
+	return buf, nil
+}
+
+// canonicalMember is a member of object written to buf, from start to end
+type canonicalMember struct {
+	key   []uint16
+	start int
+	end   int
+}
+
+func appendCanonicalValue(buf []byte, iter *Iterator) []byte {
+	switch iter.WhatIsNext() {
+	case StringValue:
+		return appendCanonicalString(buf, iter, iter.ReadString())
+	case NumberValue:
+		start := iter.head
+		iter.Skip()
+		number := string(iter.buf[start:iter.head])
+		val, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			iter.ReportError("Canonicalize", "number out of range: "+number)
+			return buf
+		}
+		return appendES6Number(buf, val)
+	case NilValue:
+		iter.ReadNil()
+		return append(buf, "null"...)
+	case BoolValue:
+		if iter.ReadBool() {
+			return append(buf, "true"...)
+		}
+		return append(buf, "false"...)
+	case ArrayValue:
+		buf = append(buf, '[')
+		start := len(buf)
+		iter.ReadArrayCB(func(iter *Iterator) bool {
+			if len(buf) != start {
+				buf = append(buf, ',')
+			}
+			buf = appendCanonicalValue(buf, iter)
+			return true
+		})
+		return append(buf, ']')
+	case ObjectValue:
+		buf = append(buf, '{')
+		start := len(buf)
+		var members []canonicalMember
+		iter.ReadMapCB(func(iter *Iterator, key string) bool {
+			if len(members) != 0 {
+				buf = append(buf, ',')
+			}
+			member := canonicalMember{key: utf16.Encode([]rune(key)), start: len(buf)}
+			buf = appendCanonicalString(buf, iter, key)
+			buf = append(buf, ':')
+			buf = appendCanonicalValue(buf, iter)
+			member.end = len(buf)
+			members = append(members, member)
+			return true
+		})
+		return append(sortCanonicalMembers(buf, start, members, iter), '}')
+	}
+	iter.ReportError("Canonicalize", "unexpected value")
+	return buf
+}
+
+// sortCanonicalMembers reorders the members written to buf from start, which are separated by comma
+func sortCanonicalMembers(buf []byte, start int, members []canonicalMember, iter *Iterator) []byte {
+	less := func(i, j int) bool {
+		return compareUTF16(members[i].key, members[j].key) < 0
+	}
+	sorted := sort.SliceIsSorted(members, less)
+	if !sorted {
+		sort.SliceStable(members, less)
+	}
+	for i := 1; i < len(members); i++ {
+		if compareUTF16(members[i-1].key, members[i].key) == 0 {
+			iter.ReportError("Canonicalize", "duplicate key "+string(utf16.Decode(members[i].key)))
+			return buf
+		}
+	}
+	if sorted {
+		return buf
+	}
+	body := append([]byte(nil), buf[start:]...)
+	buf = buf[:start]
+	for i, member := range members {
+		if i != 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, body[member.start-start:member.end-start]...)
+	}
+	return buf
+}
+
+func compareUTF16(a []uint16, b []uint16) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+// appendCanonicalString escapes only ", \ and the control characters, with the short escapes where there is one
+func appendCanonicalString(buf []byte, iter *Iterator, s string) []byte {
+	if !utf8.ValidString(s) {
+		iter.ReportError("Canonicalize", "invalid UTF-8 in string")
+		return buf
+	}
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= ' ' && c != '"' && c != '\\' {
+			continue
+		}
+		buf = append(buf, s[start:i]...)
+		switch c {
+		case '"', '\\':
+			buf = append(buf, '\\', c)
+		case '\b':
+			buf = append(buf, '\\', 'b')
+		case '\t':
+			buf = append(buf, '\\', 't')
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		case '\f':
+			buf = append(buf, '\\', 'f')
+		case '\r':
+			buf = append(buf, '\\', 'r')
+		default:
+			buf = append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+		}
+		start = i + 1
+	}
+	buf = append(buf, s[start:]...)
+	return append(buf, '"')
+}
+
+// appendES6Number formats val as Number.prototype.toString of ECMAScript,
+// with the shortest digits parsed back to val
+func appendES6Number(buf []byte, val float64) []byte {
+	if val == 0 {
+		return append(buf, '0') // -0 included
+	}
+	if val < 0 {
+		buf = append(buf, '-')
+		val = -val
+	}
+	var scratch [32]byte
+	formatted := strconv.AppendFloat(scratch[:0], val, 'e', -1, 64)
+	mark := len(formatted) - 1
+	for formatted[mark] != 'e' {
+		mark--
+	}
+	exp, _ := strconv.Atoi(string(formatted[mark+1:]))
+	digits := formatted[:1]
+	if formatted[1] == '.' {
+		digits = append(formatted[:1:1], formatted[2:mark]...)
+	}
+	// the value is 0.digits * 10^point
+	point := exp + 1
+	switch {
+	case len(digits) <= point && point <= 21:
+		buf = append(buf, digits...)
+		for i := len(digits); i < point; i++ {
+			buf = append(buf, '0')
+		}
+	case 0 < point && point <= 21:
+		buf = append(buf, digits[:point]...)
+		buf = append(buf, '.')
+		buf = append(buf, digits[point:]...)
+	case -6 < point && point <= 0:
+		buf = append(buf, '0', '.')
+		for i := point; i < 0; i++ {
+			buf = append(buf, '0')
+		}
+		buf = append(buf, digits...)
+	default:
+		buf = append(buf, digits[0])
+		if len(digits) > 1 {
+			buf = append(buf, '.')
+			buf = append(buf, digits[1:]...)
+		}
+		buf = append(buf, 'e')
+		if point > 0 {
+			buf = append(buf, '+')
+		}
+		buf = strconv.AppendInt(buf, int64(point-1), 10)
+	}
+	return buf
+}
@@ -0,0 +1,458 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+type kind int
+
+const (
+	fallbackKind kind = iota // left to Stream.WriteVal and Iterator.ReadVal
+	basicKind
+	structKind
+	ptrKind
+	sliceKind
+	mapKind
+)
+
+// generate returns the source of the codecs and the source of their registration
+func (gen *generator) generate() ([]byte, []byte, error) {
+	body := &bytes.Buffer{}
+	for _, typ := range gen.targets {
+		gen.encodeStruct(body, typ)
+		gen.decodeStruct(body, typ)
+	}
+	imports := map[string]bool{"github.com/json-iterator/go": true}
+	if gen.helpers["strings"] {
+		imports["strings"] = true
+	}
+	if gen.helpers["sort"] {
+		imports["sort"] = true
+	}
+	if gen.helpers["readQuoted"] {
+		imports["io"] = true
+		body.WriteString(readQuotedHelper)
+	}
+	if gen.helpers["writeQuoted"] {
+		body.WriteString(writeQuotedHelper)
+	}
+	if gen.helpers["isEmpty"] {
+		imports["github.com/modern-go/reflect2"] = true
+		body.WriteString(isEmptyHelper)
+	}
+	codecs := &bytes.Buffer{}
+	gen.writeHeader(codecs, imports)
+	codecs.Write(body.Bytes())
+
+	register := &bytes.Buffer{}
+	gen.writeHeader(register, map[string]bool{"unsafe": true, "github.com/json-iterator/go": true})
+	register.WriteString("func init() {\n")
+	for _, typ := range gen.targets {
+		typeName := strconv.Quote(gen.pkgName + "." + typ.name)
+		fmt.Fprintf(register, "jsoniter.RegisterTypeEncoder(%s, %s{})\n", typeName, codecName(typ.name))
+		fmt.Fprintf(register, "jsoniter.RegisterTypeDecoder(%s, %s{})\n", typeName, codecName(typ.name))
+	}
+	register.WriteString("}\n")
+	for _, typ := range gen.targets {
+		fmt.Fprintf(register, `
+type %[1]s struct{}
+
+func (%[1]s) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	%[2]s(stream, (*%[4]s)(ptr))
+}
+
+func (%[1]s) IsEmpty(ptr unsafe.Pointer) bool {
+	return false
+}
+
+func (%[1]s) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	%[3]s(iter, (*%[4]s)(ptr))
+}
+`, codecName(typ.name), encoderName(typ.name), decoderName(typ.name), typ.name)
+	}
+	codecsSource, err := format.Source(codecs.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+	registerSource, err := format.Source(register.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+	return codecsSource, registerSource, nil
+}
+
+func (gen *generator) writeHeader(buf *bytes.Buffer, imports map[string]bool) {
+	fmt.Fprintf(buf, "%s\n\npackage %s\n\nimport (\n", generatedHeader, gen.pkgName)
+	standard := []string{}
+	others := []string{}
+	for _, path := range sortedKeys(imports) {
+		if strings.Contains(path, ".") {
+			others = append(others, path)
+		} else {
+			standard = append(standard, path)
+		}
+	}
+	for _, path := range standard {
+		fmt.Fprintf(buf, "%q\n", path)
+	}
+	if len(standard) != 0 {
+		buf.WriteString("\n")
+	}
+	for _, path := range others {
+		fmt.Fprintf(buf, "%q\n", path)
+	}
+	buf.WriteString(")\n")
+}
+
+func encoderName(typeName string) string {
+	return "jsoniterEncode" + exported(typeName)
+}
+
+func decoderName(typeName string) string {
+	return "jsoniterDecode" + exported(typeName)
+}
+
+func codecName(typeName string) string {
+	return "jsoniterCodec" + exported(typeName)
+}
+
+func exported(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func (gen *generator) kindOf(typ ast.Expr) kind {
+	switch typ := unparen(typ).(type) {
+	case *ast.Ident:
+		if _, isBasic := gen.basicOf(typ); isBasic {
+			return basicKind
+		}
+		if gen.generated[typ.Name] {
+			return structKind
+		}
+	case *ast.StarExpr:
+		if gen.kindOf(typ.X) != fallbackKind {
+			return ptrKind
+		}
+	case *ast.ArrayType:
+		if basic, _ := gen.basicOf(typ.Elt); typ.Len != nil || basic == "byte" || basic == "uint8" {
+			// arrays, and byte slices encoded as base64
+			return fallbackKind
+		}
+		if gen.kindOf(typ.Elt) != fallbackKind {
+			return sliceKind
+		}
+	case *ast.MapType:
+		if key, isIdent := typ.Key.(*ast.Ident); isIdent && key.Name == "string" && gen.basics["string"] == "" &&
+			gen.kindOf(typ.Value) != fallbackKind {
+			return mapKind
+		}
+	}
+	return fallbackKind
+}
+
+func unparen(typ ast.Expr) ast.Expr {
+	if paren, isParen := typ.(*ast.ParenExpr); isParen {
+		return unparen(paren.X)
+	}
+	return typ
+}
+
+// elemOf returns the element type of pointer, slice and map
+func elemOf(typ ast.Expr) ast.Expr {
+	switch typ := unparen(typ).(type) {
+	case *ast.StarExpr:
+		return typ.X
+	case *ast.ArrayType:
+		return typ.Elt
+	case *ast.MapType:
+		return typ.Value
+	}
+	return nil
+}
+
+func (gen *generator) temp(prefix string) string {
+	gen.tmp++
+	return prefix + strconv.Itoa(gen.tmp)
+}
+
+// addressOf returns the pointer of addressable expr
+func addressOf(expr string) string {
+	if strings.HasPrefix(expr, "*") {
+		return expr[1:]
+	}
+	return "&" + expr
+}
+
+// operand wraps dereference, so expr can be indexed
+func operand(expr string) string {
+	if strings.HasPrefix(expr, "*") {
+		return "(" + expr + ")"
+	}
+	return expr
+}
+
+func (gen *generator) encodeStruct(buf *bytes.Buffer, typ *structType) {
+	fmt.Fprintf(buf, "func %s(stream *jsoniter.Stream, v *%s) {\n", encoderName(typ.name), typ.name)
+	if len(typ.fields) == 0 {
+		buf.WriteString("stream.WriteEmptyObject()\n}\n\n")
+		return
+	}
+	buf.WriteString("stream.WriteObjectStart()\n")
+	// fields before the first unconditional field track if a field has been written
+	firstWritten := len(typ.fields)
+	for i, field := range typ.fields {
+		if gen.conditionOf(field) == "" {
+			firstWritten = i
+			break
+		}
+	}
+	tracked := firstWritten != 0 && len(typ.fields) > 1
+	if tracked {
+		buf.WriteString("more := false\n")
+	}
+	for i, field := range typ.fields {
+		condition := gen.conditionOf(field)
+		if condition != "" {
+			fmt.Fprintf(buf, "if %s {\n", condition)
+		}
+		if i <= firstWritten && i != 0 {
+			buf.WriteString("if more {\nstream.WriteMore()\n}\n")
+		} else if i > firstWritten {
+			buf.WriteString("stream.WriteMore()\n")
+		}
+		fmt.Fprintf(buf, "stream.WriteObjectField(%q)\n", field.name)
+		gen.encodeValue(buf, field.expr(), field.typ, field.asString)
+		if tracked && i < firstWritten {
+			buf.WriteString("more = true\n")
+		}
+		if condition != "" {
+			buf.WriteString("}\n")
+		}
+	}
+	buf.WriteString("stream.WriteObjectEnd()\n}\n\n")
+}
+
+func (field *field) expr() string {
+	names := make([]string, len(field.path))
+	for i, step := range field.path {
+		names[i] = step.name
+	}
+	return "v." + strings.Join(names, ".")
+}
+
+// conditionOf returns the condition to write the field, empty if the field is always written
+func (gen *generator) conditionOf(field *field) string {
+	conditions := []string{}
+	prefix := "v"
+	for _, step := range field.path[:len(field.path)-1] {
+		prefix += "." + step.name
+		if step.ptr {
+			conditions = append(conditions, prefix+" != nil")
+		}
+	}
+	if field.omitempty {
+		if notEmpty := gen.notEmpty(field.expr(), field.typ); notEmpty != "" {
+			conditions = append(conditions, notEmpty)
+		}
+	}
+	return strings.Join(conditions, " && ")
+}
+
+func (gen *generator) notEmpty(expr string, typ ast.Expr) string {
+	switch typ := unparen(typ).(type) {
+	case *ast.StarExpr, *ast.InterfaceType, *ast.FuncType, *ast.ChanType:
+		return expr + " != nil"
+	case *ast.MapType:
+		return "len(" + expr + ") != 0"
+	case *ast.ArrayType:
+		if typ.Len == nil {
+			return "len(" + expr + ") != 0"
+		}
+		return ""
+	case *ast.StructType:
+		return ""
+	case *ast.Ident:
+		if basic, isBasic := gen.basicOf(typ); isBasic {
+			switch basic {
+			case "string":
+				return expr + ` != ""`
+			case "bool":
+				return expr
+			}
+			return expr + " != 0"
+		}
+		if gen.structs[typ.Name] != nil && !gen.marshalers[typ.Name] {
+			return ""
+		}
+	}
+	gen.helpers["isEmpty"] = true
+	return "!jsoniterIsEmpty(stream, " + addressOf(expr) + ")"
+}
+
+func (gen *generator) encodeValue(buf *bytes.Buffer, expr string, typ ast.Expr, asString bool) {
+	typ = unparen(typ)
+	switch gen.kindOf(typ) {
+	case basicKind:
+		basic, _ := gen.basicOf(typ)
+		value := expr
+		if basic != typ.(*ast.Ident).Name {
+			value = basic + "(" + expr + ")"
+		}
+		switch {
+		case basic == "string" && asString:
+			gen.helpers["writeQuoted"] = true
+			fmt.Fprintf(buf, "jsoniterWriteQuoted(stream, %s)\n", value)
+		case basic == "string" && gen.escapeHTML:
+			fmt.Fprintf(buf, "stream.WriteStringWithHTMLEscaped(%s)\n", value)
+		case asString:
+			fmt.Fprintf(buf, "stream.WriteRaw(`\"`)\nstream.Write%s(%s)\nstream.WriteRaw(`\"`)\n", basicTypes[basic], value)
+		default:
+			fmt.Fprintf(buf, "stream.Write%s(%s)\n", basicTypes[basic], value)
+		}
+	case structKind:
+		fmt.Fprintf(buf, "%s(stream, %s)\n", encoderName(typ.(*ast.Ident).Name), addressOf(expr))
+	case ptrKind:
+		fmt.Fprintf(buf, "if %s == nil {\nstream.WriteNil()\n} else {\n", expr)
+		gen.encodeValue(buf, "*"+expr, elemOf(typ), false)
+		buf.WriteString("}\n")
+	case sliceKind:
+		index := gen.temp("i")
+		fmt.Fprintf(buf, "if %s == nil {\nstream.WriteNil()\n} else {\nstream.WriteArrayStart()\n", expr)
+		fmt.Fprintf(buf, "for %[1]s := range %[2]s {\nif %[1]s != 0 {\nstream.WriteMore()\n}\n", index, expr)
+		gen.encodeValue(buf, operand(expr)+"["+index+"]", elemOf(typ), false)
+		buf.WriteString("}\nstream.WriteArrayEnd()\n}\n")
+	case mapKind:
+		if !gen.sortMapKeys {
+			index, key, elem := gen.temp("i"), gen.temp("key"), gen.temp("elem")
+			fmt.Fprintf(buf, "if %s == nil {\nstream.WriteNil()\n} else {\nstream.WriteObjectStart()\n", expr)
+			fmt.Fprintf(buf, "%[1]s := 0\nfor %[2]s, %[3]s := range %[4]s {\nif %[1]s != 0 {\nstream.WriteMore()\n}\n%[1]s++\n", index, key, elem, expr)
+			fmt.Fprintf(buf, "stream.WriteObjectField(%s)\n", key)
+			gen.encodeValue(buf, elem, elemOf(typ), false)
+			buf.WriteString("}\nstream.WriteObjectEnd()\n}\n")
+			return
+		}
+		gen.helpers["sort"] = true
+		keys, index, key, elem := gen.temp("keys"), gen.temp("i"), gen.temp("key"), gen.temp("elem")
+		fmt.Fprintf(buf, "if %s == nil {\nstream.WriteNil()\n} else {\nstream.WriteObjectStart()\n", expr)
+		fmt.Fprintf(buf, "%[1]s := make([]string, 0, len(%[2]s))\nfor %[3]s := range %[2]s {\n%[1]s = append(%[1]s, %[3]s)\n}\n", keys, expr, key)
+		fmt.Fprintf(buf, "sort.Strings(%s)\n", keys)
+		fmt.Fprintf(buf, "for %[1]s, %[2]s := range %[3]s {\nif %[1]s != 0 {\nstream.WriteMore()\n}\n", index, key, keys)
+		fmt.Fprintf(buf, "stream.WriteObjectField(%s)\n%s := %s[%s]\n", key, elem, operand(expr), key)
+		gen.encodeValue(buf, elem, elemOf(typ), false)
+		buf.WriteString("}\nstream.WriteObjectEnd()\n}\n")
+	default:
+		fmt.Fprintf(buf, "stream.WriteVal(%s)\n", addressOf(expr))
+	}
+}
+
+func (gen *generator) decodeStruct(buf *bytes.Buffer, typ *structType) {
+	fmt.Fprintf(buf, "func %s(iter *jsoniter.Iterator, v *%s) {\n", decoderName(typ.name), typ.name)
+	buf.WriteString("iter.ReadObjectCB(func(iter *jsoniter.Iterator, field string) bool {\n")
+	if len(typ.fields) == 0 {
+		buf.WriteString("iter.Skip()\nreturn true\n})\n}\n\n")
+		return
+	}
+	if gen.caseSensitive {
+		buf.WriteString("switch field {\n")
+	} else {
+		gen.helpers["strings"] = true
+		buf.WriteString("switch strings.ToLower(field) {\n")
+	}
+	cases := map[string]bool{}
+	for _, field := range typ.fields {
+		name := field.name
+		if !gen.caseSensitive {
+			name = strings.ToLower(name)
+		}
+		if cases[name] {
+			continue
+		}
+		cases[name] = true
+		fmt.Fprintf(buf, "case %q:\n", name)
+		prefix := "v"
+		for _, step := range field.path[:len(field.path)-1] {
+			prefix += "." + step.name
+			if step.ptr {
+				fmt.Fprintf(buf, "if %[1]s == nil {\n%[1]s = new(%[2]s)\n}\n", prefix, step.name)
+			}
+		}
+		gen.decodeValue(buf, field.expr(), field.typ, field.asString)
+	}
+	buf.WriteString("default:\niter.Skip()\n}\nreturn true\n})\n}\n\n")
+}
+
+func (gen *generator) decodeValue(buf *bytes.Buffer, target string, typ ast.Expr, asString bool) {
+	typ = unparen(typ)
+	switch gen.kindOf(typ) {
+	case basicKind:
+		basic, _ := gen.basicOf(typ)
+		value := "iter.Read" + basicTypes[basic] + "()"
+		if name := typ.(*ast.Ident).Name; basic != name {
+			value = name + "(" + value + ")"
+		}
+		switch {
+		case asString:
+			gen.helpers["readQuoted"] = true
+			fmt.Fprintf(buf, "if !iter.ReadNil() {\njsoniterReadQuoted(iter, func(iter *jsoniter.Iterator) {\n%s = %s\n})\n}\n", target, value)
+		case basic == "string":
+			fmt.Fprintf(buf, "%s = %s\n", target, value)
+		default:
+			fmt.Fprintf(buf, "if !iter.ReadNil() {\n%s = %s\n}\n", target, value)
+		}
+	case structKind:
+		fmt.Fprintf(buf, "%s(iter, %s)\n", decoderName(typ.(*ast.Ident).Name), addressOf(target))
+	case ptrKind:
+		fmt.Fprintf(buf, "if iter.ReadNil() {\n%[1]s = nil\n} else {\nif %[1]s == nil {\n%[1]s = new(%[2]s)\n}\n", target, gen.source(elemOf(typ)))
+		gen.decodeValue(buf, "*"+target, elemOf(typ), false)
+		buf.WriteString("}\n")
+	case sliceKind:
+		elem := gen.temp("elem")
+		fmt.Fprintf(buf, "if iter.ReadNil() {\n%[1]s = nil\n} else {\nif %[1]s == nil {\n%[1]s = %[2]s{}\n} else {\n%[1]s = %[3]s[:0]\n}\n", target, gen.source(typ), operand(target))
+		fmt.Fprintf(buf, "iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {\nvar %s %s\n", elem, gen.source(elemOf(typ)))
+		gen.decodeValue(buf, elem, elemOf(typ), false)
+		fmt.Fprintf(buf, "%[1]s = append(%[1]s, %[2]s)\nreturn true\n})\n}\n", target, elem)
+	case mapKind:
+		key, elem := gen.temp("key"), gen.temp("elem")
+		fmt.Fprintf(buf, "if iter.ReadNil() {\n%[1]s = nil\n} else {\nif %[1]s == nil {\n%[1]s = %[2]s{}\n}\n", target, gen.source(typ))
+		fmt.Fprintf(buf, "iter.ReadMapCB(func(iter *jsoniter.Iterator, %s string) bool {\nvar %s %s\n", key, elem, gen.source(elemOf(typ)))
+		gen.decodeValue(buf, elem, elemOf(typ), false)
+		fmt.Fprintf(buf, "%s[%s] = %s\nreturn true\n})\n}\n", operand(target), key, elem)
+	default:
+		fmt.Fprintf(buf, "iter.ReadVal(%s)\n", addressOf(target))
+	}
+}
+
+const readQuotedHelper = `// jsoniterReadQuoted reads the value quoted in string, for the string tag option
+func jsoniterReadQuoted(iter *jsoniter.Iterator, read func(iter *jsoniter.Iterator)) {
+	quoted := iter.Pool().BorrowIterator([]byte(iter.ReadString()))
+	defer iter.Pool().ReturnIterator(quoted)
+	read(quoted)
+	if quoted.Error != nil && quoted.Error != io.EOF {
+		iter.ReportError("jsoniterReadQuoted", quoted.Error.Error())
+	}
+}
+
+`
+
+const writeQuotedHelper = `// jsoniterWriteQuoted writes the string quoted twice, for the string tag option
+func jsoniterWriteQuoted(stream *jsoniter.Stream, s string) {
+	quoted := stream.Pool().BorrowStream(nil)
+	defer stream.Pool().ReturnStream(quoted)
+	quoted.WriteString(s)
+	stream.WriteString(string(quoted.Buffer()))
+}
+
+`
+
+const isEmptyHelper = `// jsoniterIsEmpty checks omitempty of the value of type not known by the generator
+func jsoniterIsEmpty(stream *jsoniter.Stream, ptr interface{}) bool {
+	encoder := stream.Pool().(jsoniter.API).EncoderOf(reflect2.TypeOf(ptr).(reflect2.PtrType).Elem())
+	return encoder.IsEmpty(reflect2.PtrOf(ptr))
+}
+
+`
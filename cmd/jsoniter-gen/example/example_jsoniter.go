@@ -0,0 +1,443 @@
+// Code generated by jsoniter-gen. DO NOT EDIT.
+
+package example
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+func jsoniterEncodeAudit(stream *jsoniter.Stream, v *Audit) {
+	stream.WriteObjectStart()
+	stream.WriteObjectField("created_by")
+	stream.WriteStringWithHTMLEscaped(v.CreatedBy)
+	stream.WriteMore()
+	stream.WriteObjectField("created_at")
+	stream.WriteVal(&v.CreatedAt)
+	if v.Revision != 0 {
+		stream.WriteMore()
+		stream.WriteObjectField("revision")
+		stream.WriteInt(v.Revision)
+	}
+	stream.WriteObjectEnd()
+}
+
+func jsoniterDecodeAudit(iter *jsoniter.Iterator, v *Audit) {
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch strings.ToLower(field) {
+		case "created_by":
+			v.CreatedBy = iter.ReadString()
+		case "created_at":
+			iter.ReadVal(&v.CreatedAt)
+		case "revision":
+			if !iter.ReadNil() {
+				v.Revision = iter.ReadInt()
+			}
+		default:
+			iter.Skip()
+		}
+		return true
+	})
+}
+
+func jsoniterEncodeNotes(stream *jsoniter.Stream, v *Notes) {
+	stream.WriteObjectStart()
+	stream.WriteObjectField("note")
+	stream.WriteStringWithHTMLEscaped(v.Note)
+	stream.WriteObjectEnd()
+}
+
+func jsoniterDecodeNotes(iter *jsoniter.Iterator, v *Notes) {
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch strings.ToLower(field) {
+		case "note":
+			v.Note = iter.ReadString()
+		default:
+			iter.Skip()
+		}
+		return true
+	})
+}
+
+func jsoniterEncodeOrder(stream *jsoniter.Stream, v *Order) {
+	stream.WriteObjectStart()
+	stream.WriteObjectField("created_by")
+	stream.WriteStringWithHTMLEscaped(v.Audit.CreatedBy)
+	stream.WriteMore()
+	stream.WriteObjectField("created_at")
+	stream.WriteVal(&v.Audit.CreatedAt)
+	if v.Audit.Revision != 0 {
+		stream.WriteMore()
+		stream.WriteObjectField("revision")
+		stream.WriteInt(v.Audit.Revision)
+	}
+	if v.Notes != nil {
+		stream.WriteMore()
+		stream.WriteObjectField("note")
+		stream.WriteStringWithHTMLEscaped(v.Notes.Note)
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("id")
+	stream.WriteRaw(`"`)
+	stream.WriteUint64(v.ID)
+	stream.WriteRaw(`"`)
+	stream.WriteMore()
+	stream.WriteObjectField("customer")
+	stream.WriteStringWithHTMLEscaped(v.Customer)
+	stream.WriteMore()
+	stream.WriteObjectField("status")
+	stream.WriteInt(int(v.Status))
+	if v.Total != 0 {
+		stream.WriteMore()
+		stream.WriteObjectField("total")
+		stream.WriteFloat64(v.Total)
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("items")
+	if v.Items == nil {
+		stream.WriteNil()
+	} else {
+		stream.WriteArrayStart()
+		for i1 := range v.Items {
+			if i1 != 0 {
+				stream.WriteMore()
+			}
+			if v.Items[i1] == nil {
+				stream.WriteNil()
+			} else {
+				jsoniterEncodeItem(stream, v.Items[i1])
+			}
+		}
+		stream.WriteArrayEnd()
+	}
+	if len(v.Tags) != 0 {
+		stream.WriteMore()
+		stream.WriteObjectField("tags")
+		if v.Tags == nil {
+			stream.WriteNil()
+		} else {
+			stream.WriteObjectStart()
+			keys2 := make([]string, 0, len(v.Tags))
+			for key4 := range v.Tags {
+				keys2 = append(keys2, key4)
+			}
+			sort.Strings(keys2)
+			for i3, key4 := range keys2 {
+				if i3 != 0 {
+					stream.WriteMore()
+				}
+				stream.WriteObjectField(key4)
+				elem5 := v.Tags[key4]
+				stream.WriteStringWithHTMLEscaped(elem5)
+			}
+			stream.WriteObjectEnd()
+		}
+	}
+	if v.Parent != nil {
+		stream.WriteMore()
+		stream.WriteObjectField("parent")
+		if v.Parent == nil {
+			stream.WriteNil()
+		} else {
+			jsoniterEncodeOrder(stream, v.Parent)
+		}
+	}
+	if v.Extra != nil {
+		stream.WriteMore()
+		stream.WriteObjectField("extra")
+		stream.WriteVal(&v.Extra)
+	}
+	if !jsoniterIsEmpty(stream, &v.Raw) {
+		stream.WriteMore()
+		stream.WriteObjectField("raw")
+		stream.WriteVal(&v.Raw)
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("payload")
+	stream.WriteVal(&v.Payload)
+	if len(v.Matrix) != 0 {
+		stream.WriteMore()
+		stream.WriteObjectField("matrix")
+		if v.Matrix == nil {
+			stream.WriteNil()
+		} else {
+			stream.WriteArrayStart()
+			for i6 := range v.Matrix {
+				if i6 != 0 {
+					stream.WriteMore()
+				}
+				if v.Matrix[i6] == nil {
+					stream.WriteNil()
+				} else {
+					stream.WriteArrayStart()
+					for i7 := range v.Matrix[i6] {
+						if i7 != 0 {
+							stream.WriteMore()
+						}
+						stream.WriteInt(v.Matrix[i6][i7])
+					}
+					stream.WriteArrayEnd()
+				}
+			}
+			stream.WriteArrayEnd()
+		}
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("totals")
+	if v.Totals == nil {
+		stream.WriteNil()
+	} else {
+		stream.WriteObjectStart()
+		keys8 := make([]string, 0, len(v.Totals))
+		for key10 := range v.Totals {
+			keys8 = append(keys8, key10)
+		}
+		sort.Strings(keys8)
+		for i9, key10 := range keys8 {
+			if i9 != 0 {
+				stream.WriteMore()
+			}
+			stream.WriteObjectField(key10)
+			elem11 := v.Totals[key10]
+			stream.WriteFloat32(elem11)
+		}
+		stream.WriteObjectEnd()
+	}
+	if !jsoniterIsEmpty(stream, &v.Timeout) {
+		stream.WriteMore()
+		stream.WriteObjectField("timeout")
+		stream.WriteVal(&v.Timeout)
+	}
+	if v.Label != "" {
+		stream.WriteMore()
+		stream.WriteObjectField("label")
+		jsoniterWriteQuoted(stream, v.Label)
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("price")
+	stream.WriteVal(&v.Price)
+	stream.WriteObjectEnd()
+}
+
+func jsoniterDecodeOrder(iter *jsoniter.Iterator, v *Order) {
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch strings.ToLower(field) {
+		case "created_by":
+			v.Audit.CreatedBy = iter.ReadString()
+		case "created_at":
+			iter.ReadVal(&v.Audit.CreatedAt)
+		case "revision":
+			if !iter.ReadNil() {
+				v.Audit.Revision = iter.ReadInt()
+			}
+		case "note":
+			if v.Notes == nil {
+				v.Notes = new(Notes)
+			}
+			v.Notes.Note = iter.ReadString()
+		case "id":
+			if !iter.ReadNil() {
+				jsoniterReadQuoted(iter, func(iter *jsoniter.Iterator) {
+					v.ID = iter.ReadUint64()
+				})
+			}
+		case "customer":
+			v.Customer = iter.ReadString()
+		case "status":
+			if !iter.ReadNil() {
+				v.Status = Status(iter.ReadInt())
+			}
+		case "total":
+			if !iter.ReadNil() {
+				v.Total = iter.ReadFloat64()
+			}
+		case "items":
+			if iter.ReadNil() {
+				v.Items = nil
+			} else {
+				if v.Items == nil {
+					v.Items = []*Item{}
+				} else {
+					v.Items = v.Items[:0]
+				}
+				iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+					var elem12 *Item
+					if iter.ReadNil() {
+						elem12 = nil
+					} else {
+						if elem12 == nil {
+							elem12 = new(Item)
+						}
+						jsoniterDecodeItem(iter, elem12)
+					}
+					v.Items = append(v.Items, elem12)
+					return true
+				})
+			}
+		case "tags":
+			if iter.ReadNil() {
+				v.Tags = nil
+			} else {
+				if v.Tags == nil {
+					v.Tags = map[string]string{}
+				}
+				iter.ReadMapCB(func(iter *jsoniter.Iterator, key13 string) bool {
+					var elem14 string
+					elem14 = iter.ReadString()
+					v.Tags[key13] = elem14
+					return true
+				})
+			}
+		case "parent":
+			if iter.ReadNil() {
+				v.Parent = nil
+			} else {
+				if v.Parent == nil {
+					v.Parent = new(Order)
+				}
+				jsoniterDecodeOrder(iter, v.Parent)
+			}
+		case "extra":
+			iter.ReadVal(&v.Extra)
+		case "raw":
+			iter.ReadVal(&v.Raw)
+		case "payload":
+			iter.ReadVal(&v.Payload)
+		case "matrix":
+			if iter.ReadNil() {
+				v.Matrix = nil
+			} else {
+				if v.Matrix == nil {
+					v.Matrix = [][]int{}
+				} else {
+					v.Matrix = v.Matrix[:0]
+				}
+				iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+					var elem15 []int
+					if iter.ReadNil() {
+						elem15 = nil
+					} else {
+						if elem15 == nil {
+							elem15 = []int{}
+						} else {
+							elem15 = elem15[:0]
+						}
+						iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+							var elem16 int
+							if !iter.ReadNil() {
+								elem16 = iter.ReadInt()
+							}
+							elem15 = append(elem15, elem16)
+							return true
+						})
+					}
+					v.Matrix = append(v.Matrix, elem15)
+					return true
+				})
+			}
+		case "totals":
+			if iter.ReadNil() {
+				v.Totals = nil
+			} else {
+				if v.Totals == nil {
+					v.Totals = map[string]float32{}
+				}
+				iter.ReadMapCB(func(iter *jsoniter.Iterator, key17 string) bool {
+					var elem18 float32
+					if !iter.ReadNil() {
+						elem18 = iter.ReadFloat32()
+					}
+					v.Totals[key17] = elem18
+					return true
+				})
+			}
+		case "timeout":
+			iter.ReadVal(&v.Timeout)
+		case "label":
+			if !iter.ReadNil() {
+				jsoniterReadQuoted(iter, func(iter *jsoniter.Iterator) {
+					v.Label = iter.ReadString()
+				})
+			}
+		case "price":
+			iter.ReadVal(&v.Price)
+		default:
+			iter.Skip()
+		}
+		return true
+	})
+}
+
+func jsoniterEncodeItem(stream *jsoniter.Stream, v *Item) {
+	stream.WriteObjectStart()
+	stream.WriteObjectField("sku")
+	stream.WriteStringWithHTMLEscaped(v.SKU)
+	stream.WriteMore()
+	stream.WriteObjectField("Quantity")
+	stream.WriteInt32(v.Quantity)
+	if v.Gift {
+		stream.WriteMore()
+		stream.WriteObjectField("Gift")
+		stream.WriteBool(v.Gift)
+	}
+	stream.WriteObjectEnd()
+}
+
+func jsoniterDecodeItem(iter *jsoniter.Iterator, v *Item) {
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch strings.ToLower(field) {
+		case "sku":
+			v.SKU = iter.ReadString()
+		case "quantity":
+			if !iter.ReadNil() {
+				v.Quantity = iter.ReadInt32()
+			}
+		case "gift":
+			if !iter.ReadNil() {
+				v.Gift = iter.ReadBool()
+			}
+		default:
+			iter.Skip()
+		}
+		return true
+	})
+}
+
+func jsoniterEncodeEmpty(stream *jsoniter.Stream, v *Empty) {
+	stream.WriteEmptyObject()
+}
+
+func jsoniterDecodeEmpty(iter *jsoniter.Iterator, v *Empty) {
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, field string) bool {
+		iter.Skip()
+		return true
+	})
+}
+
+// jsoniterReadQuoted reads the value quoted in string, for the string tag option
+func jsoniterReadQuoted(iter *jsoniter.Iterator, read func(iter *jsoniter.Iterator)) {
+	quoted := iter.Pool().BorrowIterator([]byte(iter.ReadString()))
+	defer iter.Pool().ReturnIterator(quoted)
+	read(quoted)
+	if quoted.Error != nil && quoted.Error != io.EOF {
+		iter.ReportError("jsoniterReadQuoted", quoted.Error.Error())
+	}
+}
+
+// jsoniterWriteQuoted writes the string quoted twice, for the string tag option
+func jsoniterWriteQuoted(stream *jsoniter.Stream, s string) {
+	quoted := stream.Pool().BorrowStream(nil)
+	defer stream.Pool().ReturnStream(quoted)
+	quoted.WriteString(s)
+	stream.WriteString(string(quoted.Buffer()))
+}
+
+// jsoniterIsEmpty checks omitempty of the value of type not known by the generator
+func jsoniterIsEmpty(stream *jsoniter.Stream, ptr interface{}) bool {
+	encoder := stream.Pool().(jsoniter.API).EncoderOf(reflect2.TypeOf(ptr).(reflect2.PtrType).Elem())
+	return encoder.IsEmpty(reflect2.PtrOf(ptr))
+}
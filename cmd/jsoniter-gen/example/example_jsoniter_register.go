@@ -0,0 +1,92 @@
+// Code generated by jsoniter-gen. DO NOT EDIT.
+
+package example
+
+import (
+	"unsafe"
+
+	"github.com/json-iterator/go"
+)
+
+func init() {
+	jsoniter.RegisterTypeEncoder("example.Audit", jsoniterCodecAudit{})
+	jsoniter.RegisterTypeDecoder("example.Audit", jsoniterCodecAudit{})
+	jsoniter.RegisterTypeEncoder("example.Notes", jsoniterCodecNotes{})
+	jsoniter.RegisterTypeDecoder("example.Notes", jsoniterCodecNotes{})
+	jsoniter.RegisterTypeEncoder("example.Order", jsoniterCodecOrder{})
+	jsoniter.RegisterTypeDecoder("example.Order", jsoniterCodecOrder{})
+	jsoniter.RegisterTypeEncoder("example.Item", jsoniterCodecItem{})
+	jsoniter.RegisterTypeDecoder("example.Item", jsoniterCodecItem{})
+	jsoniter.RegisterTypeEncoder("example.Empty", jsoniterCodecEmpty{})
+	jsoniter.RegisterTypeDecoder("example.Empty", jsoniterCodecEmpty{})
+}
+
+type jsoniterCodecAudit struct{}
+
+func (jsoniterCodecAudit) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	jsoniterEncodeAudit(stream, (*Audit)(ptr))
+}
+
+func (jsoniterCodecAudit) IsEmpty(ptr unsafe.Pointer) bool {
+	return false
+}
+
+func (jsoniterCodecAudit) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	jsoniterDecodeAudit(iter, (*Audit)(ptr))
+}
+
+type jsoniterCodecNotes struct{}
+
+func (jsoniterCodecNotes) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	jsoniterEncodeNotes(stream, (*Notes)(ptr))
+}
+
+func (jsoniterCodecNotes) IsEmpty(ptr unsafe.Pointer) bool {
+	return false
+}
+
+func (jsoniterCodecNotes) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	jsoniterDecodeNotes(iter, (*Notes)(ptr))
+}
+
+type jsoniterCodecOrder struct{}
+
+func (jsoniterCodecOrder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	jsoniterEncodeOrder(stream, (*Order)(ptr))
+}
+
+func (jsoniterCodecOrder) IsEmpty(ptr unsafe.Pointer) bool {
+	return false
+}
+
+func (jsoniterCodecOrder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	jsoniterDecodeOrder(iter, (*Order)(ptr))
+}
+
+type jsoniterCodecItem struct{}
+
+func (jsoniterCodecItem) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	jsoniterEncodeItem(stream, (*Item)(ptr))
+}
+
+func (jsoniterCodecItem) IsEmpty(ptr unsafe.Pointer) bool {
+	return false
+}
+
+func (jsoniterCodecItem) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	jsoniterDecodeItem(iter, (*Item)(ptr))
+}
+
+type jsoniterCodecEmpty struct{}
+
+func (jsoniterCodecEmpty) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	jsoniterEncodeEmpty(stream, (*Empty)(ptr))
+}
+
+func (jsoniterCodecEmpty) IsEmpty(ptr unsafe.Pointer) bool {
+	return false
+}
+
+func (jsoniterCodecEmpty) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	jsoniterDecodeEmpty(iter, (*Empty)(ptr))
+}
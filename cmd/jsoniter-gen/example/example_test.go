@@ -0,0 +1,73 @@
+package example
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_generated_codecs_registered(t *testing.T) {
+	should := require.New(t)
+	should.IsType(jsoniterCodecOrder{}, jsoniter.ConfigDefault.EncoderOf(reflect2.TypeOf(Order{})))
+	should.IsType(jsoniterCodecItem{}, jsoniter.ConfigDefault.DecoderOf(reflect2.TypeOf(&Item{})))
+}
+
+func Test_generated_encoder_matches_standard_library(t *testing.T) {
+	should := require.New(t)
+	orders := []Order{{}, {
+		Audit:    Audit{CreatedBy: "<admin>", CreatedAt: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), Revision: 3},
+		Notes:    &Notes{Note: "fragile & heavy"},
+		ID:       42,
+		Customer: "bob",
+		Status:   2,
+		Total:    12.5,
+		Items:    []*Item{{SKU: "a", Quantity: 2, Gift: true}, nil, {SKU: "b"}},
+		Tags:     map[string]string{"z": "1", "a": "2"},
+		Parent:   &Order{Customer: "alice", Items: []*Item{}},
+		Extra:    map[string]interface{}{"k": []interface{}{1.5, "v"}},
+		Raw:      json.RawMessage(`{"raw":true}`),
+		Payload:  []byte("payload"),
+		Matrix:   [][]int{{1, 2}, nil, {}},
+		Totals:   map[string]float32{"x": 0.5},
+		Timeout:  time.Second,
+		Label:    `say "hi"`,
+		Price:    Money{Cents: 1250},
+		internal: "hidden",
+		Skipped:  "skipped",
+	}}
+	for _, order := range orders {
+		expected, err := json.Marshal(order)
+		should.NoError(err)
+		actual, err := jsoniter.Marshal(order)
+		should.NoError(err)
+		should.Equal(string(expected), string(actual))
+
+		var decoded Order
+		should.NoError(jsoniter.Unmarshal(actual, &decoded))
+		var standard Order
+		should.NoError(json.Unmarshal(actual, &standard))
+		should.Equal(standard, decoded)
+	}
+	output, err := jsoniter.Marshal(Empty{})
+	should.NoError(err)
+	should.Equal(`{}`, string(output))
+}
+
+func Test_generated_decoder(t *testing.T) {
+	should := require.New(t)
+	order := Order{Items: []*Item{{SKU: "old"}}, Tags: map[string]string{"keep": "1"}}
+	should.NoError(jsoniter.UnmarshalFromString(`{"CUSTOMER":"bob","id":"7","note":"n","status":null,"unknown":[1,{}],
+		"items":[{"sku":"new","quantity":3}],"tags":{"b":"2"},"parent":null,"label":"\"quoted\""}`, &order))
+	should.Equal("bob", order.Customer)
+	should.Equal(uint64(7), order.ID)
+	should.Equal("n", order.Notes.Note)
+	should.Equal([]*Item{{SKU: "new", Quantity: 3}}, order.Items)
+	should.Equal(map[string]string{"keep": "1", "b": "2"}, order.Tags)
+	should.Equal(`quoted`, order.Label)
+	should.Error(jsoniter.UnmarshalFromString(`{"id":"x"}`, &order))
+	should.Error(jsoniter.UnmarshalFromString(`{"items":{}}`, &order))
+}
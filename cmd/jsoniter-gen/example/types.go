@@ -0,0 +1,66 @@
+// Package example holds the types used to test the code generated by jsoniter-gen.
+package example
+
+import (
+	"encoding/json"
+	"time"
+)
+
+//go:generate go run github.com/json-iterator/go/cmd/jsoniter-gen
+
+type Status int
+
+type Audit struct {
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	Revision  int       `json:"revision,omitempty"`
+}
+
+type Notes struct {
+	Note string `json:"note"`
+}
+
+type Order struct {
+	Audit
+	*Notes
+	ID       uint64             `json:"id,string"`
+	Customer string             `json:"customer"`
+	Status   Status             `json:"status"`
+	Total    float64            `json:"total,omitempty"`
+	Items    []*Item            `json:"items"`
+	Tags     map[string]string  `json:"tags,omitempty"`
+	Parent   *Order             `json:"parent,omitempty"`
+	Extra    interface{}        `json:"extra,omitempty"`
+	Raw      json.RawMessage    `json:"raw,omitempty"`
+	Payload  []byte             `json:"payload"`
+	Matrix   [][]int            `json:"matrix,omitempty"`
+	Totals   map[string]float32 `json:"totals"`
+	Timeout  time.Duration      `json:"timeout,omitempty"`
+	Label    string             `json:"label,string,omitempty"`
+	Price    Money              `json:"price"`
+	internal string
+	Skipped  string `json:"-"`
+}
+
+type Item struct {
+	SKU      string `json:"sku"`
+	Quantity int32
+	Gift     bool `json:",omitempty"`
+}
+
+type Money struct {
+	Cents int64
+}
+
+func (money Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(float64(money.Cents) / 100)
+}
+
+func (money *Money) UnmarshalJSON(data []byte) error {
+	var amount float64
+	err := json.Unmarshal(data, &amount)
+	money.Cents = int64(amount * 100)
+	return err
+}
+
+type Empty struct{}
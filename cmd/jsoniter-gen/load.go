@@ -0,0 +1,356 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const generatedHeader = "// Code generated by jsoniter-gen. DO NOT EDIT."
+
+type generator struct {
+	options
+	fset       *token.FileSet
+	pkgName    string
+	structs    map[string]*ast.StructType
+	basics     map[string]string // named types of basic underlying type
+	marshalers map[string]bool   // types with MarshalJSON, UnmarshalJSON, MarshalText or UnmarshalText
+	targets    []*structType
+	generated  map[string]bool
+	warnings   []string
+	tmp        int
+	helpers    map[string]bool
+}
+
+type structType struct {
+	name   string
+	fields []*field
+}
+
+// field is an encoded field, promoted from embedded structs if path has more than one step
+type field struct {
+	name      string
+	path      []step
+	typ       ast.Expr
+	tagged    bool
+	omitempty bool
+	asString  bool
+	ignored   bool
+}
+
+type step struct {
+	name string
+	ptr  bool // embedded pointer, allocated when decoding, the field is omitted if nil when encoding
+}
+
+var basicTypes = map[string]string{
+	"string": "String", "bool": "Bool",
+	"int": "Int", "int8": "Int8", "int16": "Int16", "int32": "Int32", "int64": "Int64", "rune": "Int32",
+	"uint": "Uint", "uint8": "Uint8", "uint16": "Uint16", "uint32": "Uint32", "uint64": "Uint64", "byte": "Uint8",
+	"float32": "Float32", "float64": "Float64",
+}
+
+var marshalerMethods = map[string]bool{
+	"MarshalJSON": true, "UnmarshalJSON": true, "MarshalText": true, "UnmarshalText": true,
+}
+
+// load parses the package in dir, skipping the files generated by previous runs
+func load(dir string, opts options) (*generator, error) {
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	gen := &generator{
+		options:    opts,
+		fset:       token.NewFileSet(),
+		pkgName:    pkg.Name,
+		structs:    map[string]*ast.StructType{},
+		basics:     map[string]string{},
+		marshalers: map[string]bool{},
+		generated:  map[string]bool{},
+		helpers:    map[string]bool{},
+	}
+	names := []string{}
+	for _, fileName := range pkg.GoFiles {
+		file, err := parser.ParseFile(gen.fset, filepath.Join(dir, fileName), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if len(file.Comments) > 0 && file.Comments[0].Text() == strings.TrimPrefix(generatedHeader, "// ")+"\n" {
+			continue
+		}
+		names = append(names, gen.collect(file)...)
+	}
+	underlying := map[string]string{}
+	for name, basic := range gen.basics {
+		underlying[name] = basic
+	}
+	for name := range gen.basics {
+		// named type of named basic type
+		basic := underlying[name]
+		for i := 0; i < len(underlying) && basicTypes[basic] == ""; i++ {
+			basic = underlying[basic]
+		}
+		if basicTypes[basic] == "" {
+			delete(gen.basics, name)
+		} else {
+			gen.basics[name] = basic
+		}
+	}
+	explicit := len(opts.types) != 0
+	if !explicit {
+		for _, name := range names {
+			if gen.structs[name] != nil {
+				opts.types = append(opts.types, name)
+			}
+		}
+	} else {
+		for _, name := range opts.types {
+			if gen.structs[name] == nil {
+				return nil, fmt.Errorf("struct type %s not found in package %s", name, gen.pkgName)
+			}
+		}
+	}
+	for _, name := range opts.types {
+		typ, err := gen.describe(name)
+		if err != nil {
+			if explicit {
+				return nil, err
+			}
+			gen.warnings = append(gen.warnings, err.Error()+", left to reflection")
+			continue
+		}
+		gen.targets = append(gen.targets, typ)
+		gen.generated[name] = true
+	}
+	return gen, nil
+}
+
+// collect records the type declarations and marshaler methods of file, returns the declared type names in order
+func (gen *generator) collect(file *ast.File) []string {
+	names := []string{}
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				spec := spec.(*ast.TypeSpec)
+				if spec.Assign != 0 || hasTypeParams(spec) {
+					continue
+				}
+				names = append(names, spec.Name.Name)
+				switch typ := spec.Type.(type) {
+				case *ast.StructType:
+					gen.structs[spec.Name.Name] = typ
+				case *ast.Ident:
+					gen.basics[spec.Name.Name] = typ.Name
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Recv == nil || len(decl.Recv.List) != 1 || !marshalerMethods[decl.Name.Name] {
+				continue
+			}
+			recv := decl.Recv.List[0].Type
+			if star, isStar := recv.(*ast.StarExpr); isStar {
+				recv = star.X
+			}
+			if ident, isIdent := recv.(*ast.Ident); isIdent {
+				gen.marshalers[ident.Name] = true
+			}
+		}
+	}
+	return names
+}
+
+func hasTypeParams(spec *ast.TypeSpec) bool {
+	params := reflect.ValueOf(spec).Elem().FieldByName("TypeParams")
+	return params.IsValid() && !params.IsNil()
+}
+
+// describe lists the fields of the struct type like describeStruct, then resolves the name conflicts like the encoder
+func (gen *generator) describe(name string) (*structType, error) {
+	if gen.marshalers[name] {
+		return nil, fmt.Errorf("%s implements marshaler", name)
+	}
+	fields, err := gen.describeFields(name, gen.structs[name], nil, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	for i, newField := range fields {
+		for _, oldField := range fields[:i] {
+			if oldField.name == newField.name {
+				oldField.ignored, newField.ignored = resolveConflict(oldField, newField)
+			}
+		}
+	}
+	typ := &structType{name: name}
+	for _, field := range fields {
+		if !field.ignored {
+			typ.fields = append(typ.fields, field)
+		}
+	}
+	return typ, nil
+}
+
+func (gen *generator) describeFields(name string, typ *ast.StructType, path []step, visiting map[string]bool) ([]*field, error) {
+	if visiting[name] {
+		return nil, fmt.Errorf("%s embeds itself", name)
+	}
+	visiting[name] = true
+	defer delete(visiting, name)
+	fields := []*field{}
+	for _, astField := range typ.Fields.List {
+		tagValue := ""
+		if astField.Tag != nil {
+			tagValue, _ = strconv.Unquote(astField.Tag.Value)
+		}
+		tag, hasTag := reflect.StructTag(tagValue).Lookup(gen.tagKey)
+		anonymous := len(astField.Names) == 0
+		fieldNames := []string{}
+		for _, ident := range astField.Names {
+			fieldNames = append(fieldNames, ident.Name)
+		}
+		if anonymous {
+			fieldNames = append(fieldNames, embeddedName(astField.Type))
+		}
+		for _, fieldName := range fieldNames {
+			if gen.onlyTaggedField && !hasTag && !anonymous {
+				continue
+			}
+			if tag == "-" || fieldName == "_" {
+				continue
+			}
+			tagParts := strings.Split(tag, ",")
+			if anonymous && tagParts[0] == "" {
+				embedded, isPtr := astField.Type, false
+				if star, isStar := embedded.(*ast.StarExpr); isStar {
+					embedded, isPtr = star.X, true
+				}
+				if ident, isIdent := embedded.(*ast.Ident); isIdent && gen.structs[ident.Name] != nil {
+					if gen.marshalers[ident.Name] {
+						return nil, fmt.Errorf("%s embeds %s implementing marshaler", name, ident.Name)
+					}
+					embeddedPath := append(append([]step{}, path...), step{fieldName, isPtr})
+					embeddedFields, err := gen.describeFields(ident.Name, gen.structs[ident.Name], embeddedPath, visiting)
+					if err != nil {
+						return nil, err
+					}
+					fields = append(fields, embeddedFields...)
+					continue
+				}
+				if _, isSelector := embedded.(*ast.SelectorExpr); isSelector {
+					return nil, fmt.Errorf("%s embeds %s declared in other package", name, gen.source(astField.Type))
+				}
+			}
+			if unicode.IsLower(rune(fieldName[0])) || fieldName[0] == '_' {
+				continue
+			}
+			jsonName := fieldName
+			if tagParts[0] != "" {
+				jsonName = tagParts[0]
+			}
+			field := &field{
+				name:   jsonName,
+				path:   append(append([]step{}, path...), step{name: fieldName}),
+				typ:    astField.Type,
+				tagged: tag != "",
+			}
+			for _, option := range tagParts[1:] {
+				switch option {
+				case "omitempty":
+					field.omitempty = true
+				case "string":
+					_, isBasic := gen.basicOf(astField.Type)
+					field.asString = isBasic
+				case "inline", "extra", "omitzero", "required":
+					return nil, fmt.Errorf("%s.%s uses tag option %s", name, fieldName, option)
+				default:
+					if strings.HasPrefix(option, "default=") {
+						return nil, fmt.Errorf("%s.%s uses tag option default", name, fieldName)
+					}
+				}
+			}
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// resolveConflict decides which of the fields sharing a name are ignored, same as the struct encoder
+func resolveConflict(old, new *field) (ignoreOld, ignoreNew bool) {
+	if new.tagged {
+		if old.tagged {
+			if len(old.path) > len(new.path) {
+				return true, false
+			} else if len(new.path) > len(old.path) {
+				return false, true
+			}
+			return true, true
+		}
+		return true, false
+	}
+	if old.tagged {
+		return true, false
+	}
+	if len(old.path) > len(new.path) {
+		return true, false
+	} else if len(new.path) > len(old.path) {
+		return false, true
+	}
+	return true, true
+}
+
+func embeddedName(typ ast.Expr) string {
+	switch typ := typ.(type) {
+	case *ast.StarExpr:
+		return embeddedName(typ.X)
+	case *ast.SelectorExpr:
+		return typ.Sel.Name
+	case *ast.Ident:
+		return typ.Name
+	}
+	return "_"
+}
+
+// basicOf returns the basic type of typ, if typ is a basic type or a named type of basic type without marshaler
+func (gen *generator) basicOf(typ ast.Expr) (string, bool) {
+	ident, isIdent := typ.(*ast.Ident)
+	if !isIdent {
+		return "", false
+	}
+	if basicTypes[ident.Name] != "" && gen.structs[ident.Name] == nil && gen.basics[ident.Name] == "" {
+		return ident.Name, true
+	}
+	if gen.marshalers[ident.Name] {
+		return "", false
+	}
+	basic := gen.basics[ident.Name]
+	return basic, basic != ""
+}
+
+func (gen *generator) source(node ast.Node) string {
+	buf := &bytes.Buffer{}
+	printer.Fprint(buf, gen.fset, node)
+	return buf.String()
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
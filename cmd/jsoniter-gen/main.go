@@ -0,0 +1,84 @@
+// Command jsoniter-gen generates reflection free encoders and decoders for the struct types of a package.
+//
+// The generated functions call Iterator and Stream directly, and a second generated file
+// installs them with jsoniter.RegisterTypeEncoder and jsoniter.RegisterTypeDecoder.
+// Field names and options follow the same rules as a Config with the given TagKey, OnlyTaggedField and CaseSensitive.
+// Registration is global, so the generated codecs are used by every API once the package is linked.
+// The encoders escape HTML and sort map keys as told by the flags -escape-html and -sort-map-keys,
+// whatever the EscapeHTML and SortMapKeys of the Config encoding them, the defaults being those of encoding/json.
+//
+// Usage:
+//
+//	//go:generate jsoniter-gen -type Order,Item
+//
+// Values of types declared in other packages, interfaces, arrays and maps with non string keys
+// are still encoded and decoded by the API through Stream.WriteVal and Iterator.ReadVal.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type options struct {
+	types           []string
+	tagKey          string
+	onlyTaggedField bool
+	caseSensitive   bool
+	escapeHTML      bool
+	sortMapKeys     bool
+	output          string
+	register        string
+}
+
+func main() {
+	opts := options{}
+	typeNames := flag.String("type", "", "comma separated struct type names, all struct types of the package if empty")
+	flag.StringVar(&opts.tagKey, "tagkey", "json", "struct tag key, same as Config.TagKey")
+	flag.BoolVar(&opts.onlyTaggedField, "only-tagged-field", false, "skip untagged fields, same as Config.OnlyTaggedField")
+	flag.BoolVar(&opts.caseSensitive, "case-sensitive", false, "match field names case sensitively, same as Config.CaseSensitive")
+	flag.BoolVar(&opts.escapeHTML, "escape-html", true, "escape HTML in strings, same as Config.EscapeHTML")
+	flag.BoolVar(&opts.sortMapKeys, "sort-map-keys", true, "write map entries sorted by key, same as Config.SortMapKeys")
+	flag.StringVar(&opts.output, "output", "", "file of encoders and decoders, <package>_jsoniter.go by default")
+	flag.StringVar(&opts.register, "register", "", "file registering the codecs, <package>_jsoniter_register.go by default")
+	flag.Parse()
+	if *typeNames != "" {
+		opts.types = strings.Split(*typeNames, ",")
+	}
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	if err := run(dir, opts); err != nil {
+		fmt.Fprintln(os.Stderr, "jsoniter-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string, opts options) error {
+	gen, err := load(dir, opts)
+	if err != nil {
+		return err
+	}
+	for _, warning := range gen.warnings {
+		fmt.Fprintln(os.Stderr, "jsoniter-gen:", warning)
+	}
+	codecs, register, err := gen.generate()
+	if err != nil {
+		return err
+	}
+	if opts.output == "" {
+		opts.output = filepath.Join(dir, gen.pkgName+"_jsoniter.go")
+	}
+	if opts.register == "" {
+		opts.register = filepath.Join(dir, gen.pkgName+"_jsoniter_register.go")
+	}
+	if err := ioutil.WriteFile(opts.output, codecs, 0644); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(opts.register, register, 0644)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_generated_example_is_up_to_date(t *testing.T) {
+	should := require.New(t)
+	gen, err := load("example", options{tagKey: "json", escapeHTML: true, sortMapKeys: true})
+	should.NoError(err)
+	should.Equal([]string{"Money implements marshaler, left to reflection"}, gen.warnings)
+	codecs, register, err := gen.generate()
+	should.NoError(err)
+	expected, err := ioutil.ReadFile(filepath.Join("example", "example_jsoniter.go"))
+	should.NoError(err)
+	should.Equal(string(expected), string(codecs))
+	expected, err = ioutil.ReadFile(filepath.Join("example", "example_jsoniter_register.go"))
+	should.NoError(err)
+	should.Equal(string(expected), string(register))
+}
+
+func Test_generate_with_options(t *testing.T) {
+	should := require.New(t)
+	dir, err := ioutil.TempDir("", "jsoniter-gen")
+	should.NoError(err)
+	defer os.RemoveAll(dir)
+	should.NoError(ioutil.WriteFile(filepath.Join(dir, "model.go"), []byte(`package model
+
+import "time"
+
+type user struct {
+	Name    string `+"`xml:\"name\"`"+`
+	Email   string
+	Created time.Time `+"`xml:\"created,omitempty\"`"+`
+	Labels  map[string]string `+"`xml:\"labels\"`"+`
+}
+
+type Wrapper struct {
+	time.Time
+}
+
+type Options struct {
+	Port int `+"`json:\"port,default=8080\"`"+`
+}
+`), 0644))
+	opts := options{tagKey: "xml", onlyTaggedField: true, caseSensitive: true, types: []string{"user"}}
+	should.NoError(run(dir, opts))
+	codecs, err := ioutil.ReadFile(filepath.Join(dir, "model_jsoniter.go"))
+	should.NoError(err)
+	should.Contains(string(codecs), `switch field {`)
+	should.Contains(string(codecs), `case "name":`)
+	should.NotContains(string(codecs), `Email`)
+	should.Contains(string(codecs), `stream.WriteString(v.Name)`)
+	should.Contains(string(codecs), `if !jsoniterIsEmpty(stream, &v.Created) {`)
+	// neither escape-html nor sort-map-keys
+	should.NotContains(string(codecs), `WriteStringWithHTMLEscaped`)
+	should.NotContains(string(codecs), `sort.Strings`)
+	should.Regexp(`for key\d+, elem\d+ := range v.Labels \{`, string(codecs))
+	register, err := ioutil.ReadFile(filepath.Join(dir, "model_jsoniter_register.go"))
+	should.NoError(err)
+	should.Contains(string(register), `jsoniter.RegisterTypeEncoder("model.user", jsoniterCodecUser{})`)
+
+	// regenerating skips the generated files
+	should.NoError(run(dir, opts))
+	_, err = load(dir, options{tagKey: "json", types: []string{"Wrapper"}})
+	should.EqualError(err, "Wrapper embeds time.Time declared in other package")
+	_, err = load(dir, options{tagKey: "json", types: []string{"Options"}})
+	should.EqualError(err, "Options.Port uses tag option default")
+	_, err = load(dir, options{tagKey: "json", types: []string{"missing"}})
+	should.Error(err)
+}
@@ -2,9 +2,12 @@ package jsoniter
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"unsafe"
 
 	"github.com/modern-go/concurrent"
@@ -13,8 +16,25 @@ import (
 
 // Config customize how the API should behave.
 // The API is created from Config by Froze.
+//
+// Indention is enabled by IndentionStep (count of spaces), or by IndentPrefix and Indent,
+// which are used the same way as the prefix and indent arguments of json.MarshalIndent.
+//
+// Canonical makes Marshal and Encoder write the canonical form of RFC 8785, see Canonicalize,
+// IndentionStep, IndentPrefix, Indent, MarshalFloatWith6Digits and EscapeHTML are ignored then.
+//
+// MaxDepth, MaxBytes, MaxStringLength, MaxElements and MaxNumberLength limit the input accepted when decoding,
+// exceeding one of them is reported as *LimitError.
+//
+// AllowJSON5 makes the decoding accept JSON5 (https://json5.org) input: comments, trailing commas,
+// single quoted strings, identifier keys, hex numbers, Infinity, NaN and leading +.
+// json.RawMessage, json.Unmarshaler and Any get such value rewritten as standard JSON.
+//
+// Registry gives the API its own type and field codecs and extensions, instead of the package level ones, see Registry.
 type Config struct {
 	IndentionStep                 int
+	IndentPrefix                  string
+	Indent                        string
 	MarshalFloatWith6Digits       bool
 	EscapeHTML                    bool
 	SortMapKeys                   bool
@@ -25,6 +45,15 @@ type Config struct {
 	ValidateJsonRawMessage        bool
 	ObjectFieldMustBeSimpleString bool
 	CaseSensitive                 bool
+	Canonical                     bool
+	AllowJSON5                    bool
+	Registry                      *Registry
+	MaxDepth                      int   // nesting of arrays and objects, 10000 if not set
+	MaxBytes                      int64 // bytes read by an Iterator, not limited if not set
+	MaxStringLength               int   // bytes of a string, not limited if not set
+	MaxElements                   int   // elements of an array or members of an object not read into a struct, not limited if not set
+	MaxNumberLength               int   // bytes of a number not read into an integer, not limited if not set
+	indentAlways                  bool  // set by MarshalIndent, indent even if IndentPrefix and Indent are empty
 }
 
 // API the public interface of this package.
@@ -37,13 +66,18 @@ type API interface {
 	MarshalIndent(v interface{}, prefix, indent string) ([]byte, error)
 	UnmarshalFromString(str string, v interface{}) error
 	Unmarshal(data []byte, v interface{}) error
+	UnmarshalParallel(data []byte, v interface{}, workers int) error
 	Get(data []byte, path ...interface{}) Any
+	GetPointer(data []byte, pointer string) Any
+	ParseDocument(data []byte) *Document
+	MergePatchInto(v interface{}, patch []byte) error
 	NewEncoder(writer io.Writer) *Encoder
 	NewDecoder(reader io.Reader) *Decoder
 	Valid(data []byte) bool
 	RegisterExtension(extension Extension)
 	DecoderOf(typ reflect2.Type) ValDecoder
 	EncoderOf(typ reflect2.Type) ValEncoder
+	SchemaOf(typ reflect2.Type) ([]byte, error)
 }
 
 // ConfigDefault the default API
@@ -69,22 +103,58 @@ type frozenConfig struct {
 	configBeforeFrozen            Config
 	sortMapKeys                   bool
 	indentionStep                 int
+	indentPrefix                  string
+	indent                        string
 	objectFieldMustBeSimpleString bool
 	onlyTaggedField               bool
 	disallowUnknownFields         bool
 	decoderCache                  *concurrent.Map
 	encoderCache                  *concurrent.Map
+	structFieldsCache             *concurrent.Map
 	encoderExtension              Extension
 	decoderExtension              Extension
 	extraExtensions               []Extension
+	registry                      *Registry
+	registryGeneration            uint32 // generation of the registry the cached codecs were created with
 	streamPool                    *sync.Pool
 	iteratorPool                  *sync.Pool
 	caseSensitive                 bool
+	canonical                     bool
+	allowJSON5                    bool
+	maxDepth                      int
+	maxBytes                      int64
+	maxStringLength               int
+	maxElements                   int
+	maxNumberLength               int
 }
 
 func (cfg *frozenConfig) initCache() {
 	cfg.decoderCache = concurrent.NewMap()
 	cfg.encoderCache = concurrent.NewMap()
+	cfg.structFieldsCache = concurrent.NewMap()
+}
+
+// clearCache drops the cached codecs, for them to be created again with the changed Registry
+func (cfg *frozenConfig) clearCache() {
+	for _, cache := range []*concurrent.Map{cfg.decoderCache, cfg.encoderCache, cfg.structFieldsCache} {
+		cache.Range(func(key, value interface{}) bool {
+			cache.Delete(key)
+			return true
+		})
+	}
+}
+
+// syncRegistry drops the cached codecs once the registry changed since they were created,
+// the package level registrations of defaultRegistry drop no cache
+func (cfg *frozenConfig) syncRegistry() {
+	if cfg.registry == defaultRegistry {
+		return
+	}
+	cached := atomic.LoadUint32(&cfg.registryGeneration)
+	generation := cfg.registry.getGeneration()
+	if cached != generation && atomic.CompareAndSwapUint32(&cfg.registryGeneration, cached, generation) {
+		cfg.clearCache()
+	}
 }
 
 func (cfg *frozenConfig) addDecoderToCache(cacheKey uintptr, decoder ValDecoder) {
@@ -96,6 +166,7 @@ func (cfg *frozenConfig) addEncoderToCache(cacheKey uintptr, encoder ValEncoder)
 }
 
 func (cfg *frozenConfig) getDecoderFromCache(cacheKey uintptr) ValDecoder {
+	cfg.syncRegistry()
 	decoder, found := cfg.decoderCache.Load(cacheKey)
 	if found {
 		return decoder.(ValDecoder)
@@ -104,6 +175,7 @@ func (cfg *frozenConfig) getDecoderFromCache(cacheKey uintptr) ValDecoder {
 }
 
 func (cfg *frozenConfig) getEncoderFromCache(cacheKey uintptr) ValEncoder {
+	cfg.syncRegistry()
 	encoder, found := cfg.encoderCache.Load(cacheKey)
 	if found {
 		return encoder.(ValEncoder)
@@ -129,11 +201,33 @@ func addFrozenConfigToCache(cfg Config, frozenConfig *frozenConfig) {
 func (cfg Config) Froze() API {
 	api := &frozenConfig{
 		sortMapKeys:                   cfg.SortMapKeys,
-		indentionStep:                 cfg.IndentionStep,
+		indentPrefix:                  cfg.IndentPrefix,
+		indent:                        cfg.Indent,
 		objectFieldMustBeSimpleString: cfg.ObjectFieldMustBeSimpleString,
 		onlyTaggedField:               cfg.OnlyTaggedField,
 		disallowUnknownFields:         cfg.DisallowUnknownFields,
 		caseSensitive:                 cfg.CaseSensitive,
+		canonical:                     cfg.Canonical,
+		allowJSON5:                    cfg.AllowJSON5,
+		maxDepth:                      cfg.MaxDepth,
+		maxBytes:                      cfg.MaxBytes,
+		maxStringLength:               cfg.MaxStringLength,
+		maxElements:                   cfg.MaxElements,
+		maxNumberLength:               cfg.MaxNumberLength,
+	}
+	if api.maxDepth <= 0 {
+		api.maxDepth = defaultMaxDepth
+	}
+	if cfg.Canonical {
+		// the output is rewritten in canonical form, which has no indention
+		api.indentPrefix = ""
+		api.indent = ""
+	} else if cfg.Indent == "" && cfg.IndentionStep > 0 {
+		api.indent = strings.Repeat(" ", cfg.IndentionStep)
+	}
+	if api.indent != "" || api.indentPrefix != "" || (cfg.indentAlways && !cfg.Canonical) {
+		// stream.indention counts the nesting levels
+		api.indentionStep = 1
 	}
 	api.streamPool = &sync.Pool{
 		New: func() interface{} {
@@ -148,10 +242,10 @@ func (cfg Config) Froze() API {
 	api.initCache()
 	encoderExtension := EncoderExtension{}
 	decoderExtension := DecoderExtension{}
-	if cfg.MarshalFloatWith6Digits {
+	if cfg.MarshalFloatWith6Digits && !cfg.Canonical {
 		api.marshalFloatWith6Digits(encoderExtension)
 	}
-	if cfg.EscapeHTML {
+	if cfg.EscapeHTML && !cfg.Canonical {
 		api.escapeHTML(encoderExtension)
 	}
 	if cfg.UseNumber {
@@ -163,6 +257,12 @@ func (cfg Config) Froze() API {
 	api.encoderExtension = encoderExtension
 	api.decoderExtension = decoderExtension
 	api.configBeforeFrozen = cfg
+	if cfg.Registry != nil {
+		api.registry = cfg.Registry
+		api.registryGeneration = cfg.Registry.getGeneration()
+	} else {
+		api.registry = defaultRegistry
+	}
 	return api
 }
 
@@ -188,7 +288,7 @@ func (cfg *frozenConfig) validateJsonRawMessage(extension EncoderExtension) {
 		if iter.Error != nil && iter.Error != io.EOF {
 			stream.WriteRaw("null")
 		} else {
-			stream.WriteRaw(string(rawMessage))
+			stream.writeRawValue(rawMessage)
 		}
 	}, func(ptr unsafe.Pointer) bool {
 		return len(*((*json.RawMessage)(ptr))) == 0
@@ -272,14 +372,18 @@ func (cfg *frozenConfig) escapeHTML(encoderExtension EncoderExtension) {
 }
 
 func (cfg *frozenConfig) cleanDecoders() {
-	typeDecoders = map[string]ValDecoder{}
-	fieldDecoders = map[string]ValDecoder{}
+	defaultRegistry.update(func() {
+		defaultRegistry.typeDecoders = map[string]ValDecoder{}
+		defaultRegistry.fieldDecoders = map[string]ValDecoder{}
+	})
 	*cfg = *(cfg.configBeforeFrozen.Froze().(*frozenConfig))
 }
 
 func (cfg *frozenConfig) cleanEncoders() {
-	typeEncoders = map[string]ValEncoder{}
-	fieldEncoders = map[string]ValEncoder{}
+	defaultRegistry.update(func() {
+		defaultRegistry.typeEncoders = map[string]ValEncoder{}
+		defaultRegistry.fieldEncoders = map[string]ValEncoder{}
+	})
 	*cfg = *(cfg.configBeforeFrozen.Froze().(*frozenConfig))
 }
 
@@ -287,6 +391,9 @@ func (cfg *frozenConfig) MarshalToString(v interface{}) (string, error) {
 	stream := cfg.BorrowStream(nil)
 	defer cfg.ReturnStream(stream)
 	stream.WriteVal(v)
+	if cfg.canonical {
+		stream.canonicalize(0)
+	}
 	if stream.Error != nil {
 		return "", stream.Error
 	}
@@ -297,6 +404,9 @@ func (cfg *frozenConfig) Marshal(v interface{}) ([]byte, error) {
 	stream := cfg.BorrowStream(nil)
 	defer cfg.ReturnStream(stream)
 	stream.WriteVal(v)
+	if cfg.canonical {
+		stream.canonicalize(0)
+	}
 	if stream.Error != nil {
 		return nil, stream.Error
 	}
@@ -307,16 +417,11 @@ func (cfg *frozenConfig) Marshal(v interface{}) ([]byte, error) {
 }
 
 func (cfg *frozenConfig) MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
-	if prefix != "" {
-		panic("prefix is not supported")
-	}
-	for _, r := range indent {
-		if r != ' ' {
-			panic("indent can only be space")
-		}
-	}
 	newCfg := cfg.configBeforeFrozen
-	newCfg.IndentionStep = len(indent)
+	newCfg.IndentionStep = 0
+	newCfg.IndentPrefix = prefix
+	newCfg.Indent = indent
+	newCfg.indentAlways = true
 	return newCfg.frozeWithCacheReuse(cfg.extraExtensions).Marshal(v)
 }
 
@@ -342,6 +447,18 @@ func (cfg *frozenConfig) Get(data []byte, path ...interface{}) Any {
 	return locatePath(iter, path)
 }
 
+func (cfg *frozenConfig) GetPointer(data []byte, pointer string) Any {
+	iter := cfg.BorrowIterator(data)
+	defer cfg.ReturnIterator(iter)
+	if !iter.SeekPointer(pointer) {
+		if iter.Error != nil && iter.Error != io.EOF {
+			return &invalidAny{baseAny{}, iter.Error}
+		}
+		return &invalidAny{baseAny{}, fmt.Errorf("%s not found", pointer)}
+	}
+	return iter.readAny()
+}
+
 func (cfg *frozenConfig) Unmarshal(data []byte, v interface{}) error {
 	iter := cfg.BorrowIterator(data)
 	defer cfg.ReturnIterator(iter)
@@ -364,7 +481,7 @@ func (cfg *frozenConfig) NewEncoder(writer io.Writer) *Encoder {
 
 func (cfg *frozenConfig) NewDecoder(reader io.Reader) *Decoder {
 	iter := Parse(cfg, reader, 512)
-	return &Decoder{iter}
+	return &Decoder{iter: iter}
 }
 
 func (cfg *frozenConfig) Valid(data []byte) bool {
@@ -0,0 +1,302 @@
+package jsoniter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// Document is a JSON document parsed once into a tape, an array of values in document order.
+// Containers on the tape know the positions of their children,
+// so navigating from a value to its member, element, size or keys is a lookup instead of a scan of the bytes.
+// The values are exposed as Any, and a Document is safe for concurrent reads.
+type Document struct {
+	cfg      *frozenConfig
+	data     []byte
+	tape     []tapeEntry
+	children []uint32 // elements of arrays, key and value pairs of objects, as tape positions
+	sorted   []uint32 // member numbers of the indexed objects, sorted by key
+	err      error
+}
+
+// indexedObjectSize is the number of members from which an object is indexed by its sorted keys,
+// smaller objects are scanned
+const indexedObjectSize = 8
+
+// tapeEntry is a value of the document
+type tapeEntry struct {
+	kind    ValueType
+	escaped bool   // string with escape sequence, must be unescaped to compare
+	start   uint32 // offset of the value in data
+	end     uint32 // offset after the value in data
+	first   uint32 // children position of the first element or member of container
+	count   uint32 // number of elements or members of container
+	sorted  uint32 // sorted position of the member numbers, if the object has indexedObjectSize members or more
+}
+
+// ParseDocument validates data and builds its tape, the error is reported by LastError
+// and by the Any returned from Root.
+func (cfg *frozenConfig) ParseDocument(data []byte) *Document {
+	doc := &Document{cfg: cfg, data: data}
+	if uint64(len(data)) > math.MaxUint32 {
+		doc.err = fmt.Errorf("ParseDocument: document of %d bytes is too large", len(data))
+		return doc
+	}
+	if cfg.allowJSON5 {
+		// the tape points into data, which is rewritten as standard JSON first
+		var err error
+		if doc.data, err = cfg.rewriteJSON5(data); err != nil {
+			doc.err = err
+			return doc
+		}
+		data = doc.data
+	}
+	iter := cfg.BorrowIterator(data)
+	defer cfg.ReturnIterator(iter)
+	doc.parseValue(iter, nil)
+	if iter.Error == nil || iter.Error == io.EOF {
+		if c := iter.nextToken(); c != 0 {
+			iter.ReportError("ParseDocument", "there are bytes left after the document")
+		}
+	}
+	if iter.Error != nil && iter.Error != io.EOF {
+		doc.err = iter.Error
+		doc.tape = nil
+		doc.children = nil
+		doc.sorted = nil
+	}
+	return doc
+}
+
+// rewriteJSON5 rewrites the JSON5 document as standard JSON
+func (cfg *frozenConfig) rewriteJSON5(data []byte) ([]byte, error) {
+	iter := cfg.BorrowIterator(data)
+	defer cfg.ReturnIterator(iter)
+	rewritten := iter.appendJSON5AsJSON(make([]byte, 0, len(data)))
+	if iter.Error == nil || iter.Error == io.EOF {
+		if c := iter.nextToken(); c != 0 {
+			iter.ReportError("ParseDocument", "there are bytes left after the document")
+		}
+	}
+	if iter.Error != nil && iter.Error != io.EOF {
+		return nil, iter.Error
+	}
+	return rewritten, nil
+}
+
+// parseValue appends the next value and its children to the tape, returns the position of the value.
+// The children of the open containers are kept on stack until their container is closed.
+func (doc *Document) parseValue(iter *Iterator, stack []uint32) ([]uint32, uint32) {
+	c := iter.nextToken()
+	index := uint32(len(doc.tape))
+	doc.tape = append(doc.tape, tapeEntry{kind: valueTypes[c], start: uint32(iter.head - 1)})
+	switch c {
+	case '{':
+		stack = doc.parseObject(iter, stack, index)
+	case '[':
+		stack = doc.parseArray(iter, stack, index)
+	case '"':
+		start := iter.head
+		iter.skipString()
+		doc.tape[index].escaped = bytes.IndexByte(iter.buf[start:iter.head], '\\') != -1
+	case 'n', 't', 'f', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		iter.unreadByte()
+		iter.Skip()
+	case 0:
+		iter.ReportError("ParseDocument", "unexpected end of input")
+	default:
+		if iter.cfg.allowJSON5 && (c == 'I' || c == 'N') {
+			iter.unreadByte()
+			iter.Skip() // Infinity and NaN are kept when rewriting JSON5
+		} else {
+			iter.reportUnexpected("ParseDocument", "value", c)
+		}
+	}
+	doc.tape[index].end = uint32(iter.head)
+	return stack, index
+}
+
+func (doc *Document) parseObject(iter *Iterator, stack []uint32, index uint32) []uint32 {
+	if !iter.incrementDepth() {
+		return stack
+	}
+	base := len(stack)
+	c := iter.nextToken()
+	if c != '}' {
+		iter.unreadByte()
+		for {
+			if c = iter.nextToken(); c != '"' {
+				iter.reportUnexpected("ParseDocument", `"`, c)
+				return stack
+			}
+			iter.unreadByte()
+			var key, value uint32
+			stack, key = doc.parseValue(iter, stack)
+			if c = iter.nextToken(); c != ':' {
+				iter.reportUnexpected("ParseDocument", ":", c)
+				return stack
+			}
+			stack, value = doc.parseValue(iter, stack)
+			if iter.Error != nil && iter.Error != io.EOF {
+				return stack
+			}
+			stack = append(stack, key, value)
+			if !iter.checkElements((len(stack) - base) / 2) {
+				return stack
+			}
+			if c = iter.nextToken(); c != ',' {
+				break
+			}
+		}
+		if c != '}' {
+			iter.reportUnexpected("ParseDocument", "} or ,", c)
+			return stack
+		}
+	}
+	iter.decrementDepth()
+	stack = doc.closeContainer(stack, base, index, 2)
+	doc.indexObject(index)
+	return stack
+}
+
+// indexObject sorts the member numbers of the large object by key, members with the same key keep their order
+func (doc *Document) indexObject(index uint32) {
+	entry := &doc.tape[index]
+	if entry.count < indexedObjectSize {
+		return
+	}
+	members := doc.children[entry.first : entry.first+2*entry.count]
+	keys := make([]string, entry.count)
+	for i := range keys {
+		keys[i] = doc.key(members[2*i])
+	}
+	entry.sorted = uint32(len(doc.sorted))
+	for i := uint32(0); i < entry.count; i++ {
+		doc.sorted = append(doc.sorted, i)
+	}
+	sorted := doc.sorted[entry.sorted:]
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return keys[sorted[i]] < keys[sorted[j]]
+	})
+}
+
+func (doc *Document) parseArray(iter *Iterator, stack []uint32, index uint32) []uint32 {
+	if !iter.incrementDepth() {
+		return stack
+	}
+	base := len(stack)
+	c := iter.nextToken()
+	if c != ']' {
+		iter.unreadByte()
+		for {
+			var element uint32
+			stack, element = doc.parseValue(iter, stack)
+			if iter.Error != nil && iter.Error != io.EOF {
+				return stack
+			}
+			stack = append(stack, element)
+			if !iter.checkElements(len(stack) - base) {
+				return stack
+			}
+			if c = iter.nextToken(); c != ',' {
+				break
+			}
+		}
+		if c != ']' {
+			iter.reportUnexpected("ParseDocument", "] or ,", c)
+			return stack
+		}
+	}
+	iter.decrementDepth()
+	return doc.closeContainer(stack, base, index, 1)
+}
+
+// closeContainer moves the children of the container from stack to the document
+func (doc *Document) closeContainer(stack []uint32, base int, index uint32, width int) []uint32 {
+	entry := &doc.tape[index]
+	entry.first = uint32(len(doc.children))
+	entry.count = uint32((len(stack) - base) / width)
+	doc.children = append(doc.children, stack[base:]...)
+	return stack[:base]
+}
+
+// LastError returns the error found when parsing the document.
+func (doc *Document) LastError() error {
+	return doc.err
+}
+
+// Root returns the top-level value of the document, invalid if the document failed to parse.
+func (doc *Document) Root() Any {
+	if doc.err != nil {
+		return &invalidAny{baseAny{}, doc.err}
+	}
+	return &tapeAny{doc: doc}
+}
+
+// Get is Root().Get(path...)
+func (doc *Document) Get(path ...interface{}) Any {
+	return doc.Root().Get(path...)
+}
+
+// GetPointer is Root().GetPointer(pointer)
+func (doc *Document) GetPointer(pointer string) Any {
+	return doc.Root().GetPointer(pointer)
+}
+
+// raw returns the bytes of the value at index
+func (doc *Document) raw(index uint32) []byte {
+	entry := &doc.tape[index]
+	return doc.data[entry.start:entry.end]
+}
+
+// member returns the tape position of the value of the first member named key, false if not found.
+// Large objects are searched by their sorted keys, small ones are scanned.
+func (doc *Document) member(index uint32, key string) (uint32, bool) {
+	entry := &doc.tape[index]
+	members := doc.children[entry.first : entry.first+2*entry.count]
+	if entry.count >= indexedObjectSize {
+		sorted := doc.sorted[entry.sorted : entry.sorted+entry.count]
+		i := sort.Search(len(sorted), func(i int) bool {
+			return doc.keyAtLeast(members[2*sorted[i]], key)
+		})
+		if i < len(sorted) && doc.keyEquals(members[2*sorted[i]], key) {
+			return members[2*sorted[i]+1], true
+		}
+		return 0, false
+	}
+	for i := 0; i < len(members); i += 2 {
+		if doc.keyEquals(members[i], key) {
+			return members[i+1], true
+		}
+	}
+	return 0, false
+}
+
+func (doc *Document) keyEquals(index uint32, key string) bool {
+	entry := &doc.tape[index]
+	if !entry.escaped {
+		return string(doc.data[entry.start+1:entry.end-1]) == key
+	}
+	return doc.key(index) == key
+}
+
+func (doc *Document) keyAtLeast(index uint32, key string) bool {
+	entry := &doc.tape[index]
+	if !entry.escaped {
+		return string(doc.data[entry.start+1:entry.end-1]) >= key
+	}
+	return doc.key(index) >= key
+}
+
+// key returns the unescaped string at index
+func (doc *Document) key(index uint32) string {
+	entry := &doc.tape[index]
+	if !entry.escaped {
+		return string(doc.data[entry.start+1 : entry.end-1])
+	}
+	iter := doc.cfg.BorrowIterator(doc.raw(index))
+	defer doc.cfg.ReturnIterator(iter)
+	return iter.ReadString()
+}
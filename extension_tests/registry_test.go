@@ -0,0 +1,141 @@
+package test
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+	"github.com/stretchr/testify/require"
+)
+
+type registryAmount int
+
+type registryOrder struct {
+	Amount  registryAmount
+	Comment string
+}
+
+func Test_registry_isolated_from_package_level(t *testing.T) {
+	should := require.New(t)
+	jsoniter.RegisterTypeEncoderFunc("test.registryAmount", func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		stream.WriteString("global")
+	}, nil)
+	cents := jsoniter.NewRegistry()
+	cents.RegisterTypeEncoderFunc("test.registryAmount", func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		stream.WriteString(strconv.Itoa(int(*(*registryAmount)(ptr))) + "c")
+	}, nil)
+	centsAPI := jsoniter.Config{Registry: cents}.Froze()
+	plainAPI := jsoniter.Config{Registry: jsoniter.NewRegistry()}.Froze()
+
+	output, err := centsAPI.MarshalToString(registryOrder{Amount: 5})
+	should.NoError(err)
+	should.Equal(`{"Amount":"5c","Comment":""}`, output)
+	output, err = plainAPI.MarshalToString(registryOrder{Amount: 5})
+	should.NoError(err)
+	should.Equal(`{"Amount":5,"Comment":""}`, output)
+	output, err = jsoniter.Config{}.Froze().MarshalToString(registryOrder{Amount: 5})
+	should.NoError(err)
+	should.Equal(`{"Amount":"global","Comment":""}`, output)
+}
+
+func Test_registry_change_drops_cached_codecs(t *testing.T) {
+	should := require.New(t)
+	registry := jsoniter.NewRegistry()
+	api := jsoniter.Config{Registry: registry}.Froze()
+	var order registryOrder
+	should.NoError(api.UnmarshalFromString(`{"Amount":5,"Comment":"x"}`, &order))
+	should.Equal(registryOrder{Amount: 5, Comment: "x"}, order)
+	indented, err := api.MarshalIndent(order, "", " ")
+	should.NoError(err)
+	should.Contains(string(indented), `"Amount": 5`)
+
+	registry.RegisterFieldDecoderFunc("test.registryOrder", "Comment", func(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+		*(*string)(ptr) = "field " + iter.ReadString()
+	})
+	registry.RegisterTypeEncoderFunc("test.registryAmount", func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		stream.WriteInt(int(*(*registryAmount)(ptr)) * 100)
+	}, nil)
+	should.NoError(api.UnmarshalFromString(`{"Amount":5,"Comment":"x"}`, &order))
+	should.Equal(registryOrder{Amount: 5, Comment: "field x"}, order)
+	output, err := api.MarshalToString(&order)
+	should.NoError(err)
+	should.Equal(`{"Amount":500,"Comment":"field x"}`, output)
+	indented, err = api.MarshalIndent(order, "", " ")
+	should.NoError(err)
+	should.Contains(string(indented), `"Amount": 500`)
+}
+
+func Test_registry_keeps_no_reference_to_apis(t *testing.T) {
+	should := require.New(t)
+	registry := jsoniter.NewRegistry()
+	collected := make(chan bool, 1)
+	func() {
+		api := jsoniter.Config{Registry: registry}.Froze()
+		// the API is in a cycle with its pools, the finalizer is set on the extension only it references
+		extension := &registryAmountExtension{suffix: " api"}
+		runtime.SetFinalizer(extension, func(*registryAmountExtension) {
+			collected <- true
+		})
+		api.RegisterExtension(extension)
+		output, err := api.MarshalToString(registryOrder{Amount: 5})
+		should.NoError(err)
+		should.Equal(`{"Amount":"5 api","Comment":""}`, output)
+	}()
+	for i := 0; i < 100; i++ {
+		runtime.GC()
+		select {
+		case <-collected:
+			registry.RegisterTypeEncoderFunc("test.registryAmount", func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+				stream.WriteString("changed")
+			}, nil)
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	should.Fail("the API frozen with the registry is not collected")
+}
+
+type registryAmountExtension struct {
+	jsoniter.DummyExtension
+	suffix string
+}
+
+func (extension *registryAmountExtension) CreateEncoder(typ reflect2.Type) jsoniter.ValEncoder {
+	if typ.String() != "test.registryAmount" {
+		return nil
+	}
+	return &funcEncoder{fun: func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		stream.WriteString(strconv.Itoa(int(*(*registryAmount)(ptr))) + extension.suffix)
+	}}
+}
+
+func Test_registry_precedence(t *testing.T) {
+	should := require.New(t)
+	registry := jsoniter.NewRegistry()
+	registry.RegisterTypeEncoderFunc("test.registryAmount", func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		stream.WriteString("by name")
+	}, nil)
+	api := jsoniter.Config{Registry: registry}.Froze()
+	amount := registryAmount(1)
+	output, err := api.MarshalToString(&amount)
+	should.NoError(err)
+	should.Equal(`"by name"`, output)
+
+	api.RegisterExtension(&registryAmountExtension{suffix: " api"})
+	registry.RegisterExtension(&registryAmountExtension{suffix: " first"})
+	registry.RegisterExtension(&registryAmountExtension{suffix: " second"})
+	output, err = api.MarshalToString(amount)
+	should.NoError(err)
+	should.Equal(`"1 first"`, output)
+
+	registry.RegisterFieldEncoderFunc("test.registryOrder", "Amount", func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		stream.WriteString("field")
+	}, nil)
+	output, err = api.MarshalToString(registryOrder{Amount: 1})
+	should.NoError(err)
+	should.Equal(`{"Amount":"field","Comment":""}`, output)
+}
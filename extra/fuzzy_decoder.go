@@ -16,14 +16,35 @@ const maxUint = ^uint(0)
 const maxInt = int(maxUint >> 1)
 const minInt = -maxInt - 1
 
-// RegisterFuzzyDecoders decode input from PHP with tolerance.
+// RegisterFuzzyDecoders decode input from PHP with tolerance, for every API.
 // It will handle string/number auto conversation, and treat empty [] as empty struct.
 func RegisterFuzzyDecoders() {
 	jsoniter.RegisterExtension(&tolerateEmptyArrayExtension{})
-	jsoniter.RegisterTypeDecoder("string", &fuzzyStringDecoder{})
-	jsoniter.RegisterTypeDecoder("float32", &fuzzyFloat32Decoder{})
-	jsoniter.RegisterTypeDecoder("float64", &fuzzyFloat64Decoder{})
-	jsoniter.RegisterTypeDecoder("int", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	for typ, decoder := range fuzzyDecoders {
+		jsoniter.RegisterTypeDecoder(typ, decoder)
+	}
+}
+
+// NewFuzzyDecodersExtension returns the extension decoding as RegisterFuzzyDecoders,
+// only for the API it is registered to with API.RegisterExtension.
+func NewFuzzyDecodersExtension() jsoniter.Extension {
+	return &fuzzyDecodersExtension{}
+}
+
+type fuzzyDecodersExtension struct {
+	tolerateEmptyArrayExtension
+}
+
+func (extension *fuzzyDecodersExtension) CreateDecoder(typ reflect2.Type) jsoniter.ValDecoder {
+	return fuzzyDecoders[typ.String()]
+}
+
+// fuzzyDecoders by the type name, as RegisterTypeDecoder takes
+var fuzzyDecoders = map[string]jsoniter.ValDecoder{
+	"string":  &fuzzyStringDecoder{},
+	"float32": &fuzzyFloat32Decoder{},
+	"float64": &fuzzyFloat64Decoder{},
+	"int": &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
 		if isFloat {
 			val := iter.ReadFloat64()
 			if val > float64(maxInt) || val < float64(minInt) {
@@ -34,8 +55,8 @@ func RegisterFuzzyDecoders() {
 		} else {
 			*((*int)(ptr)) = iter.ReadInt()
 		}
-	}})
-	jsoniter.RegisterTypeDecoder("uint", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	}},
+	"uint": &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
 		if isFloat {
 			val := iter.ReadFloat64()
 			if val > float64(maxUint) || val < 0 {
@@ -46,8 +67,8 @@ func RegisterFuzzyDecoders() {
 		} else {
 			*((*uint)(ptr)) = iter.ReadUint()
 		}
-	}})
-	jsoniter.RegisterTypeDecoder("int8", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	}},
+	"int8": &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
 		if isFloat {
 			val := iter.ReadFloat64()
 			if val > float64(math.MaxInt8) || val < float64(math.MinInt8) {
@@ -58,8 +79,8 @@ func RegisterFuzzyDecoders() {
 		} else {
 			*((*int8)(ptr)) = iter.ReadInt8()
 		}
-	}})
-	jsoniter.RegisterTypeDecoder("uint8", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	}},
+	"uint8": &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
 		if isFloat {
 			val := iter.ReadFloat64()
 			if val > float64(math.MaxUint8) || val < 0 {
@@ -70,8 +91,8 @@ func RegisterFuzzyDecoders() {
 		} else {
 			*((*uint8)(ptr)) = iter.ReadUint8()
 		}
-	}})
-	jsoniter.RegisterTypeDecoder("int16", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	}},
+	"int16": &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
 		if isFloat {
 			val := iter.ReadFloat64()
 			if val > float64(math.MaxInt16) || val < float64(math.MinInt16) {
@@ -82,8 +103,8 @@ func RegisterFuzzyDecoders() {
 		} else {
 			*((*int16)(ptr)) = iter.ReadInt16()
 		}
-	}})
-	jsoniter.RegisterTypeDecoder("uint16", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	}},
+	"uint16": &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
 		if isFloat {
 			val := iter.ReadFloat64()
 			if val > float64(math.MaxUint16) || val < 0 {
@@ -94,8 +115,8 @@ func RegisterFuzzyDecoders() {
 		} else {
 			*((*uint16)(ptr)) = iter.ReadUint16()
 		}
-	}})
-	jsoniter.RegisterTypeDecoder("int32", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	}},
+	"int32": &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
 		if isFloat {
 			val := iter.ReadFloat64()
 			if val > float64(math.MaxInt32) || val < float64(math.MinInt32) {
@@ -106,8 +127,8 @@ func RegisterFuzzyDecoders() {
 		} else {
 			*((*int32)(ptr)) = iter.ReadInt32()
 		}
-	}})
-	jsoniter.RegisterTypeDecoder("uint32", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	}},
+	"uint32": &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
 		if isFloat {
 			val := iter.ReadFloat64()
 			if val > float64(math.MaxUint32) || val < 0 {
@@ -118,8 +139,8 @@ func RegisterFuzzyDecoders() {
 		} else {
 			*((*uint32)(ptr)) = iter.ReadUint32()
 		}
-	}})
-	jsoniter.RegisterTypeDecoder("int64", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	}},
+	"int64": &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
 		if isFloat {
 			val := iter.ReadFloat64()
 			if val > float64(math.MaxInt64) || val < float64(math.MinInt64) {
@@ -130,8 +151,8 @@ func RegisterFuzzyDecoders() {
 		} else {
 			*((*int64)(ptr)) = iter.ReadInt64()
 		}
-	}})
-	jsoniter.RegisterTypeDecoder("uint64", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	}},
+	"uint64": &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
 		if isFloat {
 			val := iter.ReadFloat64()
 			if val > float64(math.MaxUint64) || val < 0 {
@@ -142,7 +163,7 @@ func RegisterFuzzyDecoders() {
 		} else {
 			*((*uint64)(ptr)) = iter.ReadUint64()
 		}
-	}})
+	}},
 }
 
 type tolerateEmptyArrayExtension struct {
@@ -391,3 +391,19 @@ func Test_null_to_float64(t *testing.T) {
 	err := jsoniter.Unmarshal(body, &message)
 	should.NoError(err)
 }
+
+func Test_fuzzy_decoders_extension_per_api(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{}.Froze()
+	api.RegisterExtension(NewFuzzyDecodersExtension())
+	var val struct {
+		Name  string
+		Count int
+		Ratio *float64
+	}
+	should.Nil(api.UnmarshalFromString(`{"Name":100,"Count":"10","Ratio":"0.5"}`, &val))
+	should.Equal("100", val.Name)
+	should.Equal(10, val.Count)
+	should.Equal(0.5, *val.Ratio)
+	should.Nil(api.UnmarshalFromString(`[]`, &val))
+}
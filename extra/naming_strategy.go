@@ -6,9 +6,15 @@ import (
 	"unicode"
 )
 
-// SetNamingStrategy rename struct fields uniformly
+// SetNamingStrategy rename struct fields uniformly, for every API
 func SetNamingStrategy(translate func(string) string) {
-	jsoniter.RegisterExtension(&namingStrategyExtension{jsoniter.DummyExtension{}, translate})
+	jsoniter.RegisterExtension(NewNamingStrategyExtension(translate))
+}
+
+// NewNamingStrategyExtension returns the extension renaming as SetNamingStrategy,
+// only for the API it is registered to with API.RegisterExtension.
+func NewNamingStrategyExtension(translate func(string) string) jsoniter.Extension {
+	return &namingStrategyExtension{jsoniter.DummyExtension{}, translate}
 }
 
 type namingStrategyExtension struct {
@@ -3,6 +3,7 @@ package extra
 import (
 	"github.com/json-iterator/go"
 	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
 )
 
@@ -64,3 +65,26 @@ func Test_set_naming_strategy_with_private_field(t *testing.T) {
 	should.Nil(err)
 	should.Equal(`{"user_name":"allen"}`, string(output))
 }
+
+func Test_naming_strategy_extension_per_api(t *testing.T) {
+	should := require.New(t)
+	type user struct {
+		UserName      string
+		FirstLanguage string `json:"lang"`
+	}
+	lowerAPI := jsoniter.Config{}.Froze()
+	lowerAPI.RegisterExtension(NewNamingStrategyExtension(LowerCaseWithUnderscores))
+	upperAPI := jsoniter.Config{}.Froze()
+	upperAPI.RegisterExtension(NewNamingStrategyExtension(strings.ToUpper))
+	obj := user{UserName: "taowen", FirstLanguage: "Chinese"}
+	output, err := lowerAPI.Marshal(obj)
+	should.Nil(err)
+	should.Equal(`{"user_name":"taowen","lang":"Chinese"}`, string(output))
+	output, err = upperAPI.Marshal(obj)
+	should.Nil(err)
+	should.Equal(`{"USERNAME":"taowen","lang":"Chinese"}`, string(output))
+	should.Nil(upperAPI.UnmarshalFromString(`{"USERNAME":"allen","user_name":"bob"}`, &obj))
+	should.Equal("allen", obj.UserName)
+	should.Nil(lowerAPI.UnmarshalFromString(`{"USERNAME":"allen","user_name":"bob"}`, &obj))
+	should.Equal("bob", obj.UserName)
+}
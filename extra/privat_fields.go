@@ -6,9 +6,15 @@ import (
 	"unicode"
 )
 
-// SupportPrivateFields include private fields when encoding/decoding
+// SupportPrivateFields include private fields when encoding/decoding, for every API
 func SupportPrivateFields() {
-	jsoniter.RegisterExtension(&privateFieldsExtension{})
+	jsoniter.RegisterExtension(NewPrivateFieldsExtension())
+}
+
+// NewPrivateFieldsExtension returns the extension including private fields as SupportPrivateFields,
+// only for the API it is registered to with API.RegisterExtension.
+func NewPrivateFieldsExtension() jsoniter.Extension {
+	return &privateFieldsExtension{}
 }
 
 type privateFieldsExtension struct {
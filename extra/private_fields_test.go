@@ -16,3 +16,18 @@ func Test_private_fields(t *testing.T) {
 	should.Nil(jsoniter.UnmarshalFromString(`{"field1":"Hello"}`, &obj))
 	should.Equal("Hello", obj.field1)
 }
+
+func Test_private_fields_extension_per_api(t *testing.T) {
+	type TestObject struct {
+		field1 string
+	}
+	should := require.New(t)
+	api := jsoniter.Config{}.Froze()
+	api.RegisterExtension(NewPrivateFieldsExtension())
+	obj := TestObject{}
+	should.Nil(api.UnmarshalFromString(`{"field1":"Hello"}`, &obj))
+	should.Equal("Hello", obj.field1)
+	output, err := api.Marshal(obj)
+	should.Nil(err)
+	should.Equal(`{"field1":"Hello"}`, string(output))
+}
@@ -2,16 +2,53 @@ package extra
 
 import (
 	"github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
 	"time"
 	"unsafe"
 )
 
 // RegisterTimeAsInt64Codec encode/decode time since number of unit since epoch. the precision is the unit.
+// The codec is registered for every API.
 func RegisterTimeAsInt64Codec(precision time.Duration) {
 	jsoniter.RegisterTypeEncoder("time.Time", &timeAsInt64Codec{precision})
 	jsoniter.RegisterTypeDecoder("time.Time", &timeAsInt64Codec{precision})
 }
 
+// NewTimeAsInt64CodecExtension returns the extension encoding/decoding time as RegisterTimeAsInt64Codec,
+// only for the API it is registered to with API.RegisterExtension.
+func NewTimeAsInt64CodecExtension(precision time.Duration) jsoniter.Extension {
+	return &timeAsInt64CodecExtension{codec: &timeAsInt64Codec{precision}}
+}
+
+var timePtrType = reflect2.TypeOfPtr((*time.Time)(nil))
+var timeType = timePtrType.Elem()
+
+type timeAsInt64CodecExtension struct {
+	jsoniter.DummyExtension
+	codec *timeAsInt64Codec
+}
+
+func (extension *timeAsInt64CodecExtension) CreateEncoder(typ reflect2.Type) jsoniter.ValEncoder {
+	if typ == timeType {
+		return extension.codec
+	}
+	if typ == timePtrType {
+		// otherwise *time.Time takes the global codec of RegisterTimeAsInt64Codec, if there is one
+		return &jsoniter.OptionalEncoder{ValueEncoder: extension.codec}
+	}
+	return nil
+}
+
+func (extension *timeAsInt64CodecExtension) CreateDecoder(typ reflect2.Type) jsoniter.ValDecoder {
+	if typ == timeType {
+		return extension.codec
+	}
+	if typ == timePtrType {
+		return &jsoniter.OptionalDecoder{ValueType: timeType, ValueDecoder: extension.codec}
+	}
+	return nil
+}
+
 type timeAsInt64Codec struct {
 	precision time.Duration
 }
@@ -23,8 +60,9 @@ func (codec *timeAsInt64Codec) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterato
 
 func (codec *timeAsInt64Codec) IsEmpty(ptr unsafe.Pointer) bool {
 	ts := *((*time.Time)(ptr))
-	return ts.UnixNano() == 0
+	return ts.IsZero()
 }
+
 func (codec *timeAsInt64Codec) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
 	ts := *((*time.Time)(ptr))
 	stream.WriteInt64(ts.UnixNano() / codec.precision.Nanoseconds())
@@ -29,3 +29,41 @@ func Test_time_as_int64_keep_microsecond(t *testing.T) {
 	should.Nil(jsoniter.Unmarshal(output, &val))
 	should.Equal(int64(1000001000), val.UnixNano())
 }
+
+func Test_time_as_int64_extension_per_api(t *testing.T) {
+	should := require.New(t)
+	secondAPI := jsoniter.Config{}.Froze()
+	secondAPI.RegisterExtension(NewTimeAsInt64CodecExtension(time.Second))
+	milliAPI := jsoniter.Config{}.Froze()
+	milliAPI.RegisterExtension(NewTimeAsInt64CodecExtension(time.Millisecond))
+	ts := time.Unix(1497952257, 2000000)
+	output, err := secondAPI.Marshal(ts)
+	should.Nil(err)
+	should.Equal("1497952257", string(output))
+	output, err = milliAPI.Marshal(&ts)
+	should.Nil(err)
+	should.Equal("1497952257002", string(output))
+	var val time.Time
+	should.Nil(secondAPI.Unmarshal([]byte("1000"), &val))
+	should.Equal(int64(1000*time.Second), val.UnixNano())
+	should.Nil(milliAPI.Unmarshal([]byte("1000"), &val))
+	should.Equal(int64(time.Second), val.UnixNano())
+	var ptr *time.Time
+	should.Nil(milliAPI.Unmarshal([]byte("1000"), &ptr))
+	should.Equal(int64(time.Second), ptr.UnixNano())
+}
+
+func Test_time_as_int64_omitempty_zero(t *testing.T) {
+	should := require.New(t)
+	type event struct {
+		At time.Time `json:"at,omitempty"`
+	}
+	api := jsoniter.Config{}.Froze()
+	api.RegisterExtension(NewTimeAsInt64CodecExtension(time.Second))
+	output, err := api.MarshalToString(event{})
+	should.Nil(err)
+	should.Equal(`{}`, output)
+	output, err = api.MarshalToString(event{At: time.Unix(0, 0)})
+	should.Nil(err)
+	should.Equal(`{"at":0}`, output)
+}
@@ -0,0 +1,323 @@
+package extra
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// TimeCodecConfig customize the time.Time and time.Duration codecs of NewTimeCodecExtension.
+//
+// The time tag of a struct field chooses its format, over the ones of the config.
+// time.Time is written as an integer count since epoch with unix, unixmilli, unixmicro or unixnano,
+// as float seconds since epoch with unixfloat, as a string with rfc3339, rfc3339nano or any other layout of time.Format.
+// time.Duration is written as a string such as "1h30m" with string,
+// as an integer count with the unit ns, us, ms, s, m or h.
+//
+//	type Event struct {
+//		At      time.Time     `json:"at" time:"unixmilli"`
+//		Day     time.Time     `json:"day" time:"2006-01-02"`
+//		Timeout time.Duration `json:"timeout" time:"string"`
+//	}
+type TimeCodecConfig struct {
+	TimeFormat     string         // format of time.Time without time tag, time.Time.MarshalJSON is used if not set
+	DurationFormat string         // format of time.Duration without time tag, integer nanoseconds if not set
+	Location       *time.Location // location of the decoded time, also of the layouts without zone, kept as read if not set
+}
+
+// RegisterTimeCodec encode/decode time.Time and time.Duration as the config and the time tags say, for every API
+func RegisterTimeCodec(config TimeCodecConfig) {
+	jsoniter.RegisterExtension(NewTimeCodecExtension(config))
+}
+
+// NewTimeCodecExtension returns the extension encoding/decoding as RegisterTimeCodec,
+// only for the API it is registered to with API.RegisterExtension.
+func NewTimeCodecExtension(config TimeCodecConfig) jsoniter.Extension {
+	extension := &timeCodecExtension{location: config.Location}
+	if config.TimeFormat != "" {
+		extension.timeCodec = extension.newTimeCodec(config.TimeFormat)
+	}
+	if config.DurationFormat != "" {
+		extension.durationCodec = newDurationCodec(config.DurationFormat)
+	}
+	return extension
+}
+
+var durationPtrType = reflect2.TypeOfPtr((*time.Duration)(nil))
+var durationType = durationPtrType.Elem()
+
+// nanoseconds of the units of the integer formats
+var unixUnits = map[string]int64{
+	"unix":      int64(time.Second),
+	"unixmilli": int64(time.Millisecond),
+	"unixmicro": int64(time.Microsecond),
+	"unixnano":  int64(time.Nanosecond),
+}
+
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+type timeCodecExtension struct {
+	jsoniter.DummyExtension
+	location      *time.Location
+	timeCodec     jsoniter.ValDecoder
+	durationCodec jsoniter.ValDecoder
+}
+
+func (extension *timeCodecExtension) newTimeCodec(format string) jsoniter.ValDecoder {
+	if unit, found := unixUnits[format]; found {
+		return &unixTimeCodec{unit, extension.location}
+	}
+	if format == "unixfloat" {
+		return &unixFloatTimeCodec{extension.location}
+	}
+	switch strings.ToLower(format) {
+	case "rfc3339":
+		format = time.RFC3339
+	case "rfc3339nano":
+		format = time.RFC3339Nano
+	}
+	return &layoutTimeCodec{format, extension.location}
+}
+
+func newDurationCodec(format string) jsoniter.ValDecoder {
+	if format == "string" {
+		return &stringDurationCodec{}
+	}
+	if unit, found := durationUnits[format]; found {
+		return &integerDurationCodec{unit}
+	}
+	return &timeCodecError{errors.New("unknown duration format " + format)}
+}
+
+// codecOf returns the codec of typ for the format, or the codec of the config if format is empty
+func (extension *timeCodecExtension) codecOf(typ reflect2.Type, format string) jsoniter.ValDecoder {
+	switch typ {
+	case timeType, timePtrType:
+		if format != "" {
+			return extension.newTimeCodec(format)
+		}
+		return extension.timeCodec
+	case durationType, durationPtrType:
+		if format != "" {
+			return newDurationCodec(format)
+		}
+		return extension.durationCodec
+	}
+	return nil
+}
+
+func (extension *timeCodecExtension) UpdateStructDescriptor(structDescriptor *jsoniter.StructDescriptor) {
+	for _, binding := range structDescriptor.Fields {
+		format := binding.Field.Tag().Get("time")
+		if format == "" {
+			continue
+		}
+		typ := binding.Field.Type()
+		codec := extension.codecOf(typ, format)
+		if codec == nil {
+			continue
+		}
+		binding.Encoder = encoderOf(typ, codec)
+		binding.Decoder = decoderOf(typ, codec)
+	}
+}
+
+func (extension *timeCodecExtension) CreateEncoder(typ reflect2.Type) jsoniter.ValEncoder {
+	if codec := extension.codecOf(typ, ""); codec != nil {
+		return encoderOf(typ, codec)
+	}
+	return nil
+}
+
+func (extension *timeCodecExtension) CreateDecoder(typ reflect2.Type) jsoniter.ValDecoder {
+	if codec := extension.codecOf(typ, ""); codec != nil {
+		return decoderOf(typ, codec)
+	}
+	return nil
+}
+
+// encoderOf returns codec as the encoder of typ, which is the type of codec or the pointer to it
+func encoderOf(typ reflect2.Type, codec jsoniter.ValDecoder) jsoniter.ValEncoder {
+	if typ.Kind() == reflect.Ptr {
+		return &jsoniter.OptionalEncoder{ValueEncoder: codec.(jsoniter.ValEncoder)}
+	}
+	return codec.(jsoniter.ValEncoder)
+}
+
+func decoderOf(typ reflect2.Type, codec jsoniter.ValDecoder) jsoniter.ValDecoder {
+	if typ.Kind() == reflect.Ptr {
+		return &jsoniter.OptionalDecoder{ValueType: typ.(reflect2.PtrType).Elem(), ValueDecoder: codec}
+	}
+	return codec
+}
+
+// readNull skips null, which leaves the value unchanged
+func readNull(iter *jsoniter.Iterator) bool {
+	if iter.WhatIsNext() == jsoniter.NilValue {
+		iter.Skip()
+		return true
+	}
+	return false
+}
+
+func inLocation(ts time.Time, location *time.Location) time.Time {
+	if location != nil {
+		return ts.In(location)
+	}
+	return ts
+}
+
+type unixTimeCodec struct {
+	unit     int64
+	location *time.Location
+}
+
+func (codec *unixTimeCodec) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	if readNull(iter) {
+		return
+	}
+	perSecond := int64(time.Second) / codec.unit
+	count := iter.ReadInt64()
+	*((*time.Time)(ptr)) = inLocation(time.Unix(count/perSecond, count%perSecond*codec.unit), codec.location)
+}
+
+func (codec *unixTimeCodec) IsEmpty(ptr unsafe.Pointer) bool {
+	return (*((*time.Time)(ptr))).IsZero()
+}
+
+func (codec *unixTimeCodec) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	ts := *((*time.Time)(ptr))
+	perSecond := int64(time.Second) / codec.unit
+	stream.WriteInt64(ts.Unix()*perSecond + int64(ts.Nanosecond())/codec.unit)
+}
+
+type unixFloatTimeCodec struct {
+	location *time.Location
+}
+
+func (codec *unixFloatTimeCodec) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	if readNull(iter) {
+		return
+	}
+	seconds, fraction := math.Modf(iter.ReadFloat64())
+	*((*time.Time)(ptr)) = inLocation(time.Unix(int64(seconds), int64(math.Round(fraction*1e9))), codec.location)
+}
+
+func (codec *unixFloatTimeCodec) IsEmpty(ptr unsafe.Pointer) bool {
+	return (*((*time.Time)(ptr))).IsZero()
+}
+
+func (codec *unixFloatTimeCodec) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	ts := *((*time.Time)(ptr))
+	stream.WriteFloat64(float64(ts.Unix()) + float64(ts.Nanosecond())/1e9)
+}
+
+type layoutTimeCodec struct {
+	layout   string
+	location *time.Location
+}
+
+func (codec *layoutTimeCodec) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	if readNull(iter) {
+		return
+	}
+	str := iter.ReadString()
+	location := codec.location
+	if location == nil {
+		location = time.UTC
+	}
+	ts, err := time.ParseInLocation(codec.layout, str, location)
+	if err != nil {
+		iter.ReportError("decode time", err.Error())
+		return
+	}
+	*((*time.Time)(ptr)) = inLocation(ts, codec.location)
+}
+
+func (codec *layoutTimeCodec) IsEmpty(ptr unsafe.Pointer) bool {
+	return (*((*time.Time)(ptr))).IsZero()
+}
+
+func (codec *layoutTimeCodec) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	stream.WriteString((*((*time.Time)(ptr))).Format(codec.layout))
+}
+
+type stringDurationCodec struct {
+}
+
+func (codec *stringDurationCodec) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	if readNull(iter) {
+		return
+	}
+	duration, err := time.ParseDuration(iter.ReadString())
+	if err != nil {
+		iter.ReportError("decode duration", err.Error())
+		return
+	}
+	*((*time.Duration)(ptr)) = duration
+}
+
+func (codec *stringDurationCodec) IsEmpty(ptr unsafe.Pointer) bool {
+	return *((*time.Duration)(ptr)) == 0
+}
+
+func (codec *stringDurationCodec) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	stream.WriteString((*((*time.Duration)(ptr))).String())
+}
+
+type integerDurationCodec struct {
+	unit time.Duration
+}
+
+func (codec *integerDurationCodec) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	if readNull(iter) {
+		return
+	}
+	count := iter.ReadInt64()
+	if count > math.MaxInt64/int64(codec.unit) || count < math.MinInt64/int64(codec.unit) {
+		iter.ReportError("decode duration", "exceed range")
+		return
+	}
+	*((*time.Duration)(ptr)) = time.Duration(count) * codec.unit
+}
+
+func (codec *integerDurationCodec) IsEmpty(ptr unsafe.Pointer) bool {
+	return *((*time.Duration)(ptr)) == 0
+}
+
+func (codec *integerDurationCodec) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	stream.WriteInt64(int64(*((*time.Duration)(ptr)) / codec.unit))
+}
+
+// timeCodecError reports the error of the time tag when used
+type timeCodecError struct {
+	err error
+}
+
+func (codec *timeCodecError) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	iter.ReportError("decode duration", codec.err.Error())
+}
+
+func (codec *timeCodecError) IsEmpty(ptr unsafe.Pointer) bool {
+	return false
+}
+
+func (codec *timeCodecError) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	if stream.Error == nil {
+		stream.Error = codec.err
+	}
+}
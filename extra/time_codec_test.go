@@ -0,0 +1,112 @@
+package extra
+
+import (
+	"testing"
+	"time"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type timeCodecEvent struct {
+	Seconds  time.Time      `json:"seconds" time:"unix"`
+	Millis   time.Time      `json:"millis" time:"unixmilli"`
+	Float    time.Time      `json:"float" time:"unixfloat"`
+	Nano     time.Time      `json:"nano" time:"rfc3339nano"`
+	Day      *time.Time     `json:"day" time:"2006-01-02"`
+	Timeout  time.Duration  `json:"timeout" time:"string"`
+	Interval *time.Duration `json:"interval" time:"ms"`
+	Plain    time.Time      `json:"plain"`
+}
+
+func Test_time_codec_tags(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{Registry: jsoniter.NewRegistry()}.Froze()
+	api.RegisterExtension(NewTimeCodecExtension(TimeCodecConfig{Location: time.UTC}))
+	ts := time.Date(2017, 6, 20, 9, 50, 57, 2500000, time.UTC)
+	day := time.Date(2017, 6, 20, 0, 0, 0, 0, time.UTC)
+	interval := 1500 * time.Millisecond
+	event := timeCodecEvent{
+		Seconds:  ts,
+		Millis:   ts,
+		Float:    ts,
+		Nano:     ts,
+		Day:      &day,
+		Timeout:  90 * time.Minute,
+		Interval: &interval,
+		Plain:    ts,
+	}
+	output, err := api.MarshalToString(event)
+	should.Nil(err)
+	should.Equal(`{"seconds":1497952257,"millis":1497952257002,"float":1497952257.0025,`+
+		`"nano":"2017-06-20T09:50:57.0025Z","day":"2017-06-20","timeout":"1h30m0s","interval":1500,`+
+		`"plain":"2017-06-20T09:50:57.0025Z"}`, output)
+
+	var decoded timeCodecEvent
+	should.Nil(api.UnmarshalFromString(output, &decoded))
+	should.Equal(ts.Truncate(time.Second), decoded.Seconds)
+	should.Equal(ts.Truncate(time.Millisecond), decoded.Millis)
+	should.Equal(ts.Truncate(time.Microsecond), decoded.Float.Truncate(time.Microsecond))
+	should.Equal(ts, decoded.Nano)
+	should.Equal(day, *decoded.Day)
+	should.Equal(90*time.Minute, decoded.Timeout)
+	should.Equal(interval, *decoded.Interval)
+
+	should.Nil(api.UnmarshalFromString(`{"day":null,"interval":null,"timeout":"2s"}`, &decoded))
+	should.Nil(decoded.Day)
+	should.Nil(decoded.Interval)
+	should.Equal(2*time.Second, decoded.Timeout)
+	should.NotNil(api.UnmarshalFromString(`{"day":"20/06/2017"}`, &decoded))
+	should.NotNil(api.UnmarshalFromString(`{"timeout":"forever"}`, &decoded))
+}
+
+func Test_time_codec_location(t *testing.T) {
+	should := require.New(t)
+	tokyo := time.FixedZone("JST", 9*3600)
+	api := jsoniter.Config{Registry: jsoniter.NewRegistry()}.Froze()
+	api.RegisterExtension(NewTimeCodecExtension(TimeCodecConfig{TimeFormat: "2006-01-02 15:04", Location: tokyo}))
+	var ts time.Time
+	should.Nil(api.UnmarshalFromString(`"2017-06-20 09:50"`, &ts))
+	should.Equal(time.Date(2017, 6, 20, 0, 50, 0, 0, time.UTC).Unix(), ts.Unix())
+	should.Equal(tokyo, ts.Location())
+	var event timeCodecEvent
+	should.Nil(api.UnmarshalFromString(`{"seconds":0,"nano":"2017-06-20T09:50:57Z"}`, &event))
+	should.Equal(tokyo, event.Seconds.Location())
+	should.Equal("2017-06-20T18:50:57+09:00", event.Nano.Format(time.RFC3339))
+}
+
+func Test_time_codec_config_formats(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{SortMapKeys: true, Registry: jsoniter.NewRegistry()}.Froze()
+	api.RegisterExtension(NewTimeCodecExtension(TimeCodecConfig{TimeFormat: "unixnano", DurationFormat: "s"}))
+	ts := time.Unix(1497952257, 1002)
+	output, err := api.Marshal(map[string]interface{}{"at": ts, "ttl": time.Minute, "ptr": &ts})
+	should.Nil(err)
+	should.Equal(`{"at":1497952257000001002,"ptr":1497952257000001002,"ttl":60}`, string(output))
+	var ttl *time.Duration
+	should.Nil(api.UnmarshalFromString(`30`, &ttl))
+	should.Equal(30*time.Second, *ttl)
+	should.NotNil(api.UnmarshalFromString(`9223372036854775807`, &ttl))
+
+	badAPI := jsoniter.Config{Registry: jsoniter.NewRegistry()}.Froze()
+	badAPI.RegisterExtension(NewTimeCodecExtension(TimeCodecConfig{DurationFormat: "fortnight"}))
+	_, err = badAPI.Marshal(time.Minute)
+	should.NotNil(err)
+	should.NotNil(badAPI.UnmarshalFromString(`1`, ttl))
+}
+
+func Test_time_codec_omitempty(t *testing.T) {
+	should := require.New(t)
+	type event struct {
+		At      time.Time     `json:"at,omitempty" time:"unix"`
+		Timeout time.Duration `json:"timeout,omitempty" time:"string"`
+	}
+	api := jsoniter.Config{Registry: jsoniter.NewRegistry()}.Froze()
+	api.RegisterExtension(NewTimeCodecExtension(TimeCodecConfig{}))
+	output, err := api.MarshalToString(event{})
+	should.Nil(err)
+	should.Equal(`{}`, output)
+	output, err = api.MarshalToString(event{At: time.Unix(0, 0)})
+	should.Nil(err)
+	should.Equal(`{"at":0}`, output)
+}
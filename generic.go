@@ -0,0 +1,124 @@
+package jsoniter
+
+import (
+	"io"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// TypedDecoder decodes values of T with the decoder resolved once,
+// skipping the cache lookup and the interface{} boxing of Iterator.ReadVal.
+type TypedDecoder[T any] struct {
+	api     API
+	decoder ValDecoder
+}
+
+// NewTypedDecoder resolves the decoder of T from api.
+func NewTypedDecoder[T any](api API) *TypedDecoder[T] {
+	return &TypedDecoder[T]{api: api, decoder: api.DecoderOf(reflect2.TypeOf((*T)(nil)))}
+}
+
+// Decode reads the next value from iter into v.
+func (decoder *TypedDecoder[T]) Decode(iter *Iterator, v *T) {
+	depth := iter.depth
+	decoder.decoder.Decode(unsafe.Pointer(v), iter)
+	if typeErr, ok := iter.Error.(*UnmarshalTypeError); ok && typeErr.Type == nil {
+		typeErr.Type = reflect2.TypeOf((*T)(nil)).Type1().Elem()
+	}
+	if iter.depth != depth {
+		iter.ReportError("Decode", "unexpected mismatched nesting")
+	}
+}
+
+// Unmarshal decodes data into v, same as API.Unmarshal.
+func (decoder *TypedDecoder[T]) Unmarshal(data []byte, v *T) error {
+	iter := decoder.api.BorrowIterator(data)
+	defer decoder.api.ReturnIterator(iter)
+	decoder.Decode(iter, v)
+	c := iter.nextToken()
+	if c == 0 {
+		if iter.Error == io.EOF {
+			return nil
+		}
+		return iter.Error
+	}
+	iter.ReportError("Unmarshal", "there are bytes left after unmarshal")
+	return iter.Error
+}
+
+// TypedEncoder encodes values of T with the encoder resolved once,
+// skipping the cache lookup and the interface{} boxing of Stream.WriteVal.
+type TypedEncoder[T any] struct {
+	api     API
+	encoder ValEncoder
+}
+
+// NewTypedEncoder resolves the encoder of T from api.
+func NewTypedEncoder[T any](api API) *TypedEncoder[T] {
+	encoder := api.EncoderOf(reflect2.TypeOf((*T)(nil)).(reflect2.PtrType).Elem())
+	if onePtr, isOnePtr := encoder.(*onePtrEncoder); isOnePtr {
+		// the encoder of T in memory, instead of T stored in interface{}
+		encoder = onePtr.encoder
+	}
+	return &TypedEncoder[T]{api: api, encoder: encoder}
+}
+
+// Encode writes the value pointed by v to stream, nil is written as null.
+func (encoder *TypedEncoder[T]) Encode(stream *Stream, v *T) {
+	if v == nil {
+		stream.WriteNil()
+		return
+	}
+	encoder.encoder.Encode(unsafe.Pointer(v), stream)
+}
+
+// Marshal encodes the value pointed by v, same as API.Marshal.
+func (encoder *TypedEncoder[T]) Marshal(v *T) ([]byte, error) {
+	stream := encoder.api.BorrowStream(nil)
+	defer encoder.api.ReturnStream(stream)
+	encoder.Encode(stream, v)
+	if stream.cfg.canonical {
+		stream.canonicalize(0)
+	}
+	if stream.Error != nil {
+		return nil, stream.Error
+	}
+	result := stream.Buffer()
+	copied := make([]byte, len(result))
+	copy(copied, result)
+	return copied, nil
+}
+
+// UnmarshalAs decodes data as T with api.
+func UnmarshalAs[T any](api API, data []byte) (T, error) {
+	var v T
+	err := NewTypedDecoder[T](api).Unmarshal(data, &v)
+	return v, err
+}
+
+// MarshalAs encodes v of T with api.
+func MarshalAs[T any](api API, v T) ([]byte, error) {
+	return NewTypedEncoder[T](api).Marshal(&v)
+}
+
+// EachLine decodes the remaining lines of reader as T, calling fn with each value until fn returns an error.
+// It returns nil when all the lines are read.
+func EachLine[T any](reader *LineReader, fn func(v T) error) error {
+	decoder := NewTypedDecoder[T](reader.api)
+	for {
+		var v T
+		err := reader.next(func(line []byte) error {
+			return decoder.Unmarshal(line, &v)
+		})
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/json-iterator/go
 
-go 1.12
+go 1.18
 
 require (
 	github.com/davecgh/go-spew v1.1.1
@@ -9,3 +9,8 @@ require (
 	github.com/modern-go/reflect2 v1.0.2
 	github.com/stretchr/testify v1.8.0
 )
+
+require (
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
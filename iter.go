@@ -1,6 +1,7 @@
 package jsoniter
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -77,6 +78,12 @@ type Iterator struct {
 	depth            int
 	captureStartedAt int
 	captured         []byte
+	offset           int64           // absolute offset of buf[0]
+	line             int             // lines before buf[0]
+	lineStart        int64           // absolute offset of the line containing buf[0]
+	ctx              context.Context // checked before reading more from reader, set by Decoder.DecodeContext
+	fieldsRead       *fieldsRead     // fields read of the struct being decoded, set by checkedStructDecoder
+	lastToken        byte            // last token returned by nextToken with AllowJSON5, for the trailing comma
 	Error            error
 	Attachment       interface{} // open for customized decoder
 }
@@ -107,7 +114,7 @@ func Parse(cfg API, reader io.Reader, bufSize int) *Iterator {
 
 // ParseBytes creates an Iterator instance from byte array
 func ParseBytes(cfg API, input []byte) *Iterator {
-	return &Iterator{
+	iter := &Iterator{
 		cfg:    cfg.(*frozenConfig),
 		reader: nil,
 		buf:    input,
@@ -115,6 +122,8 @@ func ParseBytes(cfg API, input []byte) *Iterator {
 		tail:   len(input),
 		depth:  0,
 	}
+	iter.checkInputBytes()
+	return iter
 }
 
 // ParseString creates an Iterator instance from string
@@ -133,9 +142,19 @@ func (iter *Iterator) Reset(reader io.Reader) *Iterator {
 	iter.head = 0
 	iter.tail = 0
 	iter.depth = 0
+	iter.lastToken = 0
+	iter.resetPosition()
 	return iter
 }
 
+// checkInputBytes reports MaxBytes if the whole input is above it, nothing is read then
+func (iter *Iterator) checkInputBytes() {
+	if max := iter.cfg.maxBytes; max > 0 && int64(iter.tail) > max {
+		iter.tail = 0
+		iter.reportLimit("MaxBytes", max)
+	}
+}
+
 // ResetBytes reuse iterator instance by specifying another byte array as input
 func (iter *Iterator) ResetBytes(input []byte) *Iterator {
 	iter.reader = nil
@@ -143,12 +162,19 @@ func (iter *Iterator) ResetBytes(input []byte) *Iterator {
 	iter.head = 0
 	iter.tail = len(input)
 	iter.depth = 0
+	iter.lastToken = 0
+	iter.resetPosition()
+	iter.checkInputBytes()
 	return iter
 }
 
 // WhatIsNext gets ValueType of relatively next json element
 func (iter *Iterator) WhatIsNext() ValueType {
-	valueType := valueTypes[iter.nextToken()]
+	c := iter.nextToken()
+	valueType := valueTypes[c]
+	if valueType == InvalidValue && iter.cfg.allowJSON5 {
+		valueType = json5ValueType(c)
+	}
 	iter.unreadByte()
 	return valueType
 }
@@ -188,6 +214,9 @@ func (iter *Iterator) nextToken() byte {
 				continue
 			}
 			iter.head = i + 1
+			if iter.cfg.allowJSON5 {
+				return iter.nextTokenJSON5(c)
+			}
 			return c
 		}
 		if !iter.loadMore() {
@@ -197,32 +226,9 @@ func (iter *Iterator) nextToken() byte {
 }
 
 // ReportError record a error in iterator instance with current position.
+// The recorded error is a *SyntaxError.
 func (iter *Iterator) ReportError(operation string, msg string) {
-	if iter.Error != nil {
-		if iter.Error != io.EOF {
-			return
-		}
-	}
-	peekStart := iter.head - 10
-	if peekStart < 0 {
-		peekStart = 0
-	}
-	peekEnd := iter.head + 10
-	if peekEnd > iter.tail {
-		peekEnd = iter.tail
-	}
-	parsing := string(iter.buf[peekStart:peekEnd])
-	contextStart := iter.head - 50
-	if contextStart < 0 {
-		contextStart = 0
-	}
-	contextEnd := iter.head + 50
-	if contextEnd > iter.tail {
-		contextEnd = iter.tail
-	}
-	context := string(iter.buf[contextStart:contextEnd])
-	iter.Error = fmt.Errorf("%s: %s, error found in #%v byte of ...|%s|..., bigger context ...|%s|...",
-		operation, msg, iter.head-peekStart, parsing, context)
+	iter.reportSyntaxError(operation, msg, "")
 }
 
 // CurrentBuffer gets current buffer as string for debugging purpose
@@ -263,17 +269,43 @@ func (iter *Iterator) loadMore() bool {
 		iter.captureStartedAt = 0
 	}
 	for {
-		n, err := iter.reader.Read(iter.buf)
+		if !iter.checkContext() {
+			iter.head = iter.tail
+			return false
+		}
+		readBuf := iter.buf
+		max := iter.cfg.maxBytes
+		if max > 0 {
+			// one more byte than allowed, to tell the input ending at the limit from the input exceeding it
+			if allowed := max + 1 - iter.offset - int64(iter.tail); allowed < int64(len(readBuf)) {
+				readBuf = readBuf[:allowed]
+			}
+		}
+		n, err := iter.reader.Read(readBuf)
 		if n == 0 {
 			if err != nil {
 				if iter.Error == nil {
 					iter.Error = err
 				}
+				// everything in buf has been consumed (and captured, if capturing)
+				iter.head = iter.tail
+				if iter.captured != nil {
+					iter.captureStartedAt = iter.tail
+				}
 				return false
 			}
 		} else {
+			iter.trackPosition()
 			iter.head = 0
 			iter.tail = n
+			if max > 0 && iter.offset+int64(n) > max {
+				// the bytes above the limit are dropped, it is reported once the value needs them
+				iter.tail = int(max - iter.offset)
+				if iter.tail == 0 {
+					iter.reportLimit("MaxBytes", max)
+					return false
+				}
+			}
 			return true
 		}
 	}
@@ -328,14 +360,12 @@ func (iter *Iterator) Read() interface{} {
 }
 
 // limit maximum depth of nesting, as allowed by https://tools.ietf.org/html/rfc7159#section-9
-const maxDepth = 10000
-
 func (iter *Iterator) incrementDepth() (success bool) {
 	iter.depth++
-	if iter.depth <= maxDepth {
+	if iter.depth <= iter.cfg.maxDepth {
 		return true
 	}
-	iter.ReportError("incrementDepth", "exceeded max depth")
+	iter.reportLimit("MaxDepth", int64(iter.cfg.maxDepth))
 	return false
 }
 
@@ -19,7 +19,7 @@ func (iter *Iterator) ReadArray() (ret bool) {
 	case ',':
 		return true
 	default:
-		iter.ReportError("ReadArray", "expect [ or , or ] or n, but found "+string([]byte{c}))
+		iter.reportUnexpected("ReadArray", "[ or , or ] or n", c)
 		return
 	}
 }
@@ -39,15 +39,17 @@ func (iter *Iterator) ReadArrayCB(callback func(*Iterator) bool) (ret bool) {
 				return false
 			}
 			c = iter.nextToken()
+			count := 1
 			for c == ',' {
-				if !callback(iter) {
+				count++
+				if !iter.checkElements(count) || !callback(iter) {
 					iter.decrementDepth()
 					return false
 				}
 				c = iter.nextToken()
 			}
 			if c != ']' {
-				iter.ReportError("ReadArrayCB", "expect ] in the end, but found "+string([]byte{c}))
+				iter.reportUnexpected("ReadArrayCB", "]", c)
 				iter.decrementDepth()
 				return false
 			}
@@ -59,6 +61,6 @@ func (iter *Iterator) ReadArrayCB(callback func(*Iterator) bool) (ret bool) {
 		iter.skipThreeBytes('u', 'l', 'l')
 		return true // null
 	}
-	iter.ReportError("ReadArrayCB", "expect [ or n, but found "+string([]byte{c}))
+	iter.reportUnexpectedValue("ReadArrayCB", "[ or n", c, nil)
 	return false
 }
@@ -0,0 +1,325 @@
+package jsoniter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/modern-go/reflect2"
+)
+
+var boolType = reflect.TypeOf(true)
+var stringType = reflect.TypeOf("")
+
+// SyntaxError is reported by Iterator when the input is not valid JSON.
+// It can be extracted from the returned error with errors.As as *jsoniter.SyntaxError,
+// and as *json.SyntaxError if the input was read from bytes and encoding/json rejects it too.
+type SyntaxError struct {
+	Operation string // the Iterator operation that failed, for example ReadObjectCB
+	Msg       string // description of the error
+	Expected  string // the expected token, empty if unknown
+	Offset    int64  // error occurred after reading Offset bytes from the start of the input
+	Line      int    // 1-based line of Offset
+	Column    int    // 1-based byte column of Offset
+	Struct    string // name of the innermost struct type containing the field
+	Field     string // full path of the field from the root value, for example Items[3].Price
+	location  errorLocation
+	input     []byte // the input if it was read from bytes, for the conversion into json.SyntaxError
+}
+
+func (err *SyntaxError) Error() string {
+	return err.location.prefix(err.Field) + fmt.Sprintf("%s: %s", err.Operation, err.Msg) + err.location.suffix()
+}
+
+// As allows errors.As to convert SyntaxError into json.SyntaxError.
+// json.SyntaxError keeps its message in an unexported field, so it is the error encoding/json reports for the input,
+// which is only known if the input was read from bytes.
+func (err *SyntaxError) As(target interface{}) bool {
+	switch target := target.(type) {
+	case **json.SyntaxError:
+		if err.input == nil {
+			return false
+		}
+		stdErr, isSyntaxErr := json.Unmarshal(err.input, &json.RawMessage{}).(*json.SyntaxError)
+		if !isSyntaxErr {
+			return false
+		}
+		*target = stdErr
+		return true
+	}
+	return false
+}
+
+// UnmarshalTypeError is reported by Iterator when a JSON value is well-formed
+// but not appropriate for the Go type it is decoded into.
+// It can be extracted from the returned error with errors.As,
+// either as *jsoniter.UnmarshalTypeError or as *json.UnmarshalTypeError.
+type UnmarshalTypeError struct {
+	Operation string       // the Iterator operation that failed, for example ReadString
+	Value     string       // description of JSON value - "bool", "array", "number", "object", "string"
+	Type      reflect.Type // type of Go value it could not be assigned to, nil if unknown
+	Offset    int64        // offset of the unexpected value from the start of the input
+	Line      int          // 1-based line of Offset
+	Column    int          // 1-based byte column of Offset
+	Struct    string       // name of the innermost struct type containing the field
+	Field     string       // full path of the field from the root value, for example Items[3].Price
+	location  errorLocation
+}
+
+func (err *UnmarshalTypeError) Error() string {
+	typeName := "unknown"
+	if err.Type != nil {
+		typeName = err.Type.String()
+	}
+	return err.location.prefix(err.Field) +
+		fmt.Sprintf("%s: cannot unmarshal %s into Go value of type %s", err.Operation, err.Value, typeName) +
+		err.location.suffix()
+}
+
+// As allows errors.As to convert UnmarshalTypeError into json.UnmarshalTypeError
+func (err *UnmarshalTypeError) As(target interface{}) bool {
+	switch target := target.(type) {
+	case **json.UnmarshalTypeError:
+		*target = &json.UnmarshalTypeError{
+			Value:  err.Value,
+			Type:   err.Type,
+			Offset: err.Offset,
+			Struct: err.Struct,
+			Field:  err.Field,
+		}
+		return true
+	}
+	return false
+}
+
+// errorLocation keeps the textual context of the error, and the root struct type used to prefix the message
+type errorLocation struct {
+	root    string
+	peekAt  int
+	parsing string
+	context string
+}
+
+func (location *errorLocation) prefix(field string) string {
+	switch {
+	case location.root == "" && field == "":
+		return ""
+	case location.root == "":
+		return field + ": "
+	case field == "":
+		return location.root + ": "
+	case strings.HasPrefix(field, "["):
+		return location.root + field + ": "
+	}
+	return location.root + "." + field + ": "
+}
+
+func (location *errorLocation) suffix() string {
+	return fmt.Sprintf(", error found in #%v byte of ...|%s|..., bigger context ...|%s|...",
+		location.peekAt, location.parsing, location.context)
+}
+
+func (iter *Iterator) errorLocation() errorLocation {
+	peekStart := iter.head - 10
+	if peekStart < 0 {
+		peekStart = 0
+	}
+	peekEnd := iter.head + 10
+	if peekEnd > iter.tail {
+		peekEnd = iter.tail
+	}
+	contextStart := iter.head - 50
+	if contextStart < 0 {
+		contextStart = 0
+	}
+	contextEnd := iter.head + 50
+	if contextEnd > iter.tail {
+		contextEnd = iter.tail
+	}
+	return errorLocation{
+		peekAt:  iter.head - peekStart,
+		parsing: string(iter.buf[peekStart:peekEnd]),
+		context: string(iter.buf[contextStart:contextEnd]),
+	}
+}
+
+// position returns the absolute offset, line and column of buf[head]
+func (iter *Iterator) position(head int) (offset int64, line int, column int) {
+	offset = iter.offset + int64(head)
+	line = iter.line
+	lineStart := iter.lineStart
+	for i := 0; i < head; i++ {
+		if iter.buf[i] == '\n' {
+			line++
+			lineStart = iter.offset + int64(i) + 1
+		}
+	}
+	return offset, line + 1, int(offset-lineStart) + 1
+}
+
+// trackPosition accounts the bytes of buf that are about to be replaced by loadMore
+func (iter *Iterator) trackPosition() {
+	for i := 0; i < iter.tail; i++ {
+		if iter.buf[i] == '\n' {
+			iter.line++
+			iter.lineStart = iter.offset + int64(i) + 1
+		}
+	}
+	iter.offset += int64(iter.tail)
+}
+
+func (iter *Iterator) resetPosition() {
+	iter.offset = 0
+	iter.line = 0
+	iter.lineStart = 0
+}
+
+// InputOffset returns the absolute offset of the current position, counted from the start of the input
+func (iter *Iterator) InputOffset() int64 {
+	return iter.offset + int64(iter.head)
+}
+
+func (iter *Iterator) reportUnexpected(operation string, expected string, c byte) {
+	iter.reportSyntaxError(operation, "expect "+expected+", but found "+string([]byte{c}), expected)
+}
+
+func (iter *Iterator) reportSyntaxError(operation string, msg string, expected string) {
+	if iter.Error != nil && !iter.isEOF() {
+		return
+	}
+	offset, line, column := iter.position(iter.head)
+	iter.Error = &SyntaxError{
+		Operation: operation,
+		Msg:       msg,
+		Expected:  expected,
+		Offset:    offset,
+		Line:      line,
+		Column:    column,
+		location:  iter.errorLocation(),
+		input:     iter.inputBytes(),
+	}
+}
+
+// inputBytes returns the whole input if the iterator reads bytes, nil if it reads from a reader
+func (iter *Iterator) inputBytes() []byte {
+	if iter.reader != nil {
+		return nil
+	}
+	return iter.buf[:iter.tail]
+}
+
+// reportUnexpectedValue reports an UnmarshalTypeError if the already consumed c starts a valid JSON value,
+// otherwise it is a syntax error
+func (iter *Iterator) reportUnexpectedValue(operation string, expected string, c byte, typ reflect.Type) {
+	var value string
+	switch valueTypes[c] {
+	case StringValue:
+		value = "string"
+	case NumberValue:
+		value = "number"
+	case BoolValue:
+		value = "bool"
+	case ArrayValue:
+		value = "array"
+	case ObjectValue:
+		value = "object"
+	default:
+		iter.reportUnexpected(operation, expected, c)
+		return
+	}
+	if iter.Error != nil && !iter.isEOF() {
+		return
+	}
+	head := iter.head
+	if head > 0 {
+		head-- // point to the start of the value
+	}
+	offset, line, column := iter.position(head)
+	iter.Error = &UnmarshalTypeError{
+		Operation: operation,
+		Value:     value,
+		Type:      typ,
+		Offset:    offset,
+		Line:      line,
+		Column:    column,
+		location:  iter.errorLocation(),
+	}
+}
+
+func (iter *Iterator) isEOF() bool {
+	return iter.Error == io.EOF
+}
+
+// addErrorField prepends the struct field name or the [index] of array element to the error path.
+// Returns false if the error does not carry a path.
+func (iter *Iterator) addErrorField(elem string, typ reflect.Type) bool {
+	switch err := iter.Error.(type) {
+	case *SyntaxError:
+		err.Field = joinErrorPath(elem, err.Field)
+		err.location.root = ""
+	case *UnmarshalTypeError:
+		err.Field = joinErrorPath(elem, err.Field)
+		err.location.root = ""
+		if err.Type == nil {
+			err.Type = typ
+		}
+	case *LimitError:
+		err.Field = joinErrorPath(elem, err.Field)
+	default:
+		return isAbortError(iter.Error)
+	}
+	return true
+}
+
+func (iter *Iterator) addErrorIndex(index int, typ reflect.Type) bool {
+	return iter.addErrorField("["+strconv.Itoa(index)+"]", typ)
+}
+
+// isPathError tells if the error carries its own field path
+func (iter *Iterator) isPathError() bool {
+	switch iter.Error.(type) {
+	case *SyntaxError, *UnmarshalTypeError:
+		return true
+	}
+	return isAbortError(iter.Error)
+}
+
+// addErrorStruct records the struct type decoding the field where error occurred.
+// Returns false if the error does not carry a path.
+func (iter *Iterator) addErrorStruct(typ reflect2.Type) bool {
+	root := ""
+	if len(typ.Type1().Name()) != 0 {
+		root = typ.String()
+	}
+	switch err := iter.Error.(type) {
+	case *SyntaxError:
+		if err.Struct == "" && err.Field != "" {
+			err.Struct = typ.Type1().Name()
+		}
+		err.location.root = root
+	case *UnmarshalTypeError:
+		if err.Struct == "" && err.Field != "" {
+			err.Struct = typ.Type1().Name()
+		}
+		if err.Type == nil {
+			err.Type = typ.Type1()
+		}
+		err.location.root = root
+	default:
+		return isAbortError(iter.Error)
+	}
+	return true
+}
+
+func joinErrorPath(elem string, path string) string {
+	if path == "" {
+		return elem
+	}
+	if strings.HasPrefix(path, "[") {
+		return elem + path
+	}
+	return elem + "." + path
+}
@@ -69,6 +69,15 @@ func (iter *Iterator) ReadBigInt() (ret *big.Int) {
 //ReadFloat32 read float32
 func (iter *Iterator) ReadFloat32() (ret float32) {
 	c := iter.nextToken()
+	switch valueTypes[c] {
+	case StringValue, BoolValue, ArrayValue, ObjectValue:
+		iter.reportUnexpectedValue("ReadFloat32", "number", c, nil)
+		return
+	}
+	if iter.cfg.allowJSON5 {
+		iter.unreadByte()
+		return float32(iter.readJSON5Float(32))
+	}
 	if c == '-' {
 		return -iter.readPositiveFloat32()
 	}
@@ -115,6 +124,9 @@ non_decimal_loop:
 		case invalidCharForNumber:
 			return iter.readFloat32SlowPath()
 		case endOfNumber:
+			if !iter.checkNumberLength(i - iter.head) {
+				return
+			}
 			iter.head = i
 			return float32(value)
 		case dotInNumber:
@@ -138,6 +150,9 @@ non_decimal_loop:
 			switch ind {
 			case endOfNumber:
 				if decimalPlaces > 0 && decimalPlaces < len(pow10) {
+					if !iter.checkNumberLength(i - iter.head) {
+						return
+					}
 					iter.head = i
 					return float32(float64(value) / float64(pow10[decimalPlaces]))
 				}
@@ -157,6 +172,9 @@ non_decimal_loop:
 }
 
 func (iter *Iterator) readNumberAsString() (ret string) {
+	if iter.cfg.allowJSON5 {
+		return iter.readJSON5Number()
+	}
 	strBuf := [16]byte{}
 	str := strBuf[0:0]
 load_loop:
@@ -172,10 +190,16 @@ load_loop:
 				break load_loop
 			}
 		}
+		if !iter.checkNumberLength(len(str)) {
+			return
+		}
 		if !iter.loadMore() {
 			break
 		}
 	}
+	if !iter.checkNumberLength(len(str)) {
+		return
+	}
 	if iter.Error != nil && iter.Error != io.EOF {
 		return
 	}
@@ -206,6 +230,15 @@ func (iter *Iterator) readFloat32SlowPath() (ret float32) {
 // ReadFloat64 read float64
 func (iter *Iterator) ReadFloat64() (ret float64) {
 	c := iter.nextToken()
+	switch valueTypes[c] {
+	case StringValue, BoolValue, ArrayValue, ObjectValue:
+		iter.reportUnexpectedValue("ReadFloat64", "number", c, nil)
+		return
+	}
+	if iter.cfg.allowJSON5 {
+		iter.unreadByte()
+		return iter.readJSON5Float(64)
+	}
 	if c == '-' {
 		return -iter.readPositiveFloat64()
 	}
@@ -252,6 +285,9 @@ non_decimal_loop:
 		case invalidCharForNumber:
 			return iter.readFloat64SlowPath()
 		case endOfNumber:
+			if !iter.checkNumberLength(i - iter.head) {
+				return
+			}
 			iter.head = i
 			return float64(value)
 		case dotInNumber:
@@ -275,6 +311,9 @@ non_decimal_loop:
 			switch ind {
 			case endOfNumber:
 				if decimalPlaces > 0 && decimalPlaces < len(pow10) {
+					if !iter.checkNumberLength(i - iter.head) {
+						return
+					}
 					iter.head = i
 					return float64(value) / float64(pow10[decimalPlaces])
 				}
@@ -0,0 +1,180 @@
+package jsoniter
+
+import (
+	"math/bits"
+	"unsafe"
+)
+
+// stage 1 structural index in the style of simdjson:
+// the input is scanned 64 bytes at a time, and each block is turned into bitmasks,
+// bit i of a mask is set if byte i of the block is of the class.
+// Escapes and strings are then resolved with bit operations on the masks,
+// carrying the state from one block to the next.
+
+// blockMasks is the index of one 64 bytes block
+type blockMasks struct {
+	quote     uint64 // "
+	backslash uint64 // \
+	open      uint64 // { or [
+	close     uint64 // } or ]
+	control   uint64 // bytes below 0x20, invalid inside strings
+}
+
+const blockSize = 64
+
+// padding for the partial block at the end of buffer, spaces are in none of the classes
+var blankBlock = [blockSize]byte{
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ',
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ',
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ',
+	' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ',
+}
+
+// indexBuffer indexes the first block of buf, returns the number of bytes indexed, at most 64
+func indexBuffer(buf []byte, masks *blockMasks) int {
+	if len(buf) >= blockSize {
+		indexBlock((*[blockSize]byte)(unsafe.Pointer(&buf[0])), masks)
+		return blockSize
+	}
+	block := blankBlock
+	copy(block[:], buf)
+	indexBlock(&block, masks)
+	return len(buf)
+}
+
+const (
+	swarLow  = 0x0101010101010101
+	swarHigh = 0x8080808080808080
+	evenBits = 0x5555555555555555
+)
+
+// indexBlockSWAR indexes the block 8 bytes at a time with SIMD within a register
+func indexBlockSWAR(block *[blockSize]byte, masks *blockMasks) {
+	*masks = blockMasks{}
+	for i := 0; i < blockSize; i += 8 {
+		x := uint64(block[i]) | uint64(block[i+1])<<8 | uint64(block[i+2])<<16 | uint64(block[i+3])<<24 |
+			uint64(block[i+4])<<32 | uint64(block[i+5])<<40 | uint64(block[i+6])<<48 | uint64(block[i+7])<<56
+		folded := x | 0x20*swarLow // { and [, } and ] differ only by 0x20
+		masks.quote |= swarGather(swarEqual(x, '"')) << uint(i)
+		masks.backslash |= swarGather(swarEqual(x, '\\')) << uint(i)
+		masks.open |= swarGather(swarEqual(folded, '{')) << uint(i)
+		masks.close |= swarGather(swarEqual(folded, '}')) << uint(i)
+		masks.control |= swarGather(swarLess(x, 0x20)) << uint(i)
+	}
+}
+
+// swarEqual sets the high bit of the bytes of x equal to b, without false positives
+func swarEqual(x uint64, b byte) uint64 {
+	t := x ^ (swarLow * uint64(b))
+	return ^(((t &^ swarHigh) + ^uint64(swarHigh)) | t | ^uint64(swarHigh))
+}
+
+// swarLess sets the high bit of the bytes of x less than b, b must not be above 0x80
+func swarLess(x uint64, b byte) uint64 {
+	return ^(((x &^ swarHigh) + swarLow*uint64(0x80-b)) | x | ^uint64(swarHigh))
+}
+
+// swarGather packs the high bits of the 8 bytes into the low 8 bits
+func swarGather(high uint64) uint64 {
+	return ((high >> 7) * 0x0102040810204080) >> 56
+}
+
+// structuralScanner resolves escapes and strings block by block
+type structuralScanner struct {
+	escapeCarry   uint64 // 1 if the previous block ended with an odd backslash run
+	inStringCarry uint64 // all ones if the previous block ended inside a string
+}
+
+// escaped returns the bytes escaped by a backslash, of the block with n bytes indexed
+func (scanner *structuralScanner) escaped(backslash uint64, n int) uint64 {
+	if backslash == 0 && scanner.escapeCarry == 0 {
+		return 0
+	}
+	// runs of backslashes escape the byte after them if the run is odd,
+	// which is told by the parity of the run start and the run end
+	starts := backslash &^ (backslash << 1)
+	evenStartMask := evenBits ^ scanner.escapeCarry
+	evenStarts := starts & evenStartMask
+	oddStarts := starts &^ evenStartMask
+	evenCarries := backslash + evenStarts
+	oddCarries, carry := bits.Add64(backslash, oddStarts, 0)
+	oddCarries |= scanner.escapeCarry
+	evenCarryEnds := evenCarries &^ backslash
+	oddCarryEnds := oddCarries &^ backslash
+	escaped := (evenCarryEnds &^ evenBits) | (oddCarryEnds & evenBits)
+	if n < blockSize {
+		// the run ending at the last byte escapes the first byte of the next block
+		scanner.escapeCarry = escaped >> uint(n) & 1
+		return escaped & (1<<uint(n) - 1)
+	}
+	scanner.escapeCarry = carry
+	return escaped
+}
+
+// inString returns the bytes inside strings, including the opening quotes and excluding the closing quotes
+func (scanner *structuralScanner) inString(quote uint64) uint64 {
+	quote ^= quote << 1
+	quote ^= quote << 2
+	quote ^= quote << 4
+	quote ^= quote << 8
+	quote ^= quote << 16
+	quote ^= quote << 32
+	quote ^= scanner.inStringCarry
+	scanner.inStringCarry = uint64(int64(quote) >> 63)
+	return quote
+}
+
+// indexQuote returns the index of the first " in buf, -1 if not found
+func indexQuote(buf []byte) int {
+	masks := blockMasks{}
+	for i := 0; i < len(buf); i += blockSize {
+		indexBuffer(buf[i:], &masks)
+		if masks.quote != 0 {
+			return i + bits.TrailingZeros64(masks.quote)
+		}
+	}
+	return -1
+}
+
+// indexStringStop returns the index of the first ", \ or control character in buf, -1 if not found
+func indexStringStop(buf []byte) int {
+	masks := blockMasks{}
+	for i := 0; i < len(buf); i += blockSize {
+		indexBuffer(buf[i:], &masks)
+		if stops := masks.quote | masks.backslash | masks.control; stops != 0 {
+			return i + bits.TrailingZeros64(stops)
+		}
+	}
+	return -1
+}
+
+// indexStringEnd returns the index of the first unescaped " in buf, -1 if not found.
+// The escape state is kept in scanner, to continue with the next buffer.
+func (scanner *structuralScanner) indexStringEnd(buf []byte) int {
+	masks := blockMasks{}
+	for i := 0; i < len(buf); i += blockSize {
+		n := indexBuffer(buf[i:], &masks)
+		if quote := masks.quote &^ scanner.escaped(masks.backslash, n); quote != 0 {
+			return i + bits.TrailingZeros64(quote)
+		}
+	}
+	return -1
+}
+
+// indexBrackets calls found with the index and the kind of each bracket outside strings in buf, until it returns false.
+// The string state is kept in scanner, to continue with the next buffer.
+func (scanner *structuralScanner) indexBrackets(buf []byte, found func(i int, open bool) bool) {
+	masks := blockMasks{}
+	for i := 0; i < len(buf); i += blockSize {
+		n := indexBuffer(buf[i:], &masks)
+		quote := masks.quote &^ scanner.escaped(masks.backslash, n)
+		brackets := (masks.open | masks.close) &^ scanner.inString(quote)
+		for brackets != 0 {
+			bit := bits.TrailingZeros64(brackets)
+			brackets &= brackets - 1
+			if !found(i+bit, masks.open>>uint(bit)&1 == 1) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build amd64 && !purego
+// +build amd64,!purego
+
+package jsoniter
+
+// indexBlock indexes the block with SSE2, which every amd64 cpu has
+//
+//go:noescape
+func indexBlock(block *[blockSize]byte, masks *blockMasks)
@@ -0,0 +1,82 @@
+//go:build amd64 && !purego
+// +build amd64,!purego
+
+#include "textflag.h"
+
+// func indexBlock(block *[blockSize]byte, masks *blockMasks)
+TEXT ·indexBlock(SB), NOSPLIT, $0-16
+	MOVQ block+0(FP), SI
+	MOVQ masks+8(FP), DI
+
+	// broadcast the byte classes
+	MOVQ $0x2222222222222222, AX // "
+	MOVQ AX, X8
+	PUNPCKLQDQ X8, X8
+	MOVQ $0x5c5c5c5c5c5c5c5c, AX // \
+	MOVQ AX, X9
+	PUNPCKLQDQ X9, X9
+	MOVQ $0x2020202020202020, AX // { and [, } and ] differ only by 0x20
+	MOVQ AX, X10
+	PUNPCKLQDQ X10, X10
+	MOVQ $0x7b7b7b7b7b7b7b7b, AX // {
+	MOVQ AX, X11
+	PUNPCKLQDQ X11, X11
+	MOVQ $0x7d7d7d7d7d7d7d7d, AX // }
+	MOVQ AX, X12
+	PUNPCKLQDQ X12, X12
+	MOVQ $0x1f1f1f1f1f1f1f1f, AX // control characters are at most 0x1f
+	MOVQ AX, X13
+	PUNPCKLQDQ X13, X13
+
+	XORQ R8, R8   // quote
+	XORQ R9, R9   // backslash
+	XORQ R10, R10 // open
+	XORQ R11, R11 // close
+	XORQ R12, R12 // control
+	XORQ CX, CX   // offset of the 16 bytes chunk
+
+chunk:
+	MOVOU (SI)(CX*1), X0
+
+	MOVO     X0, X1
+	PCMPEQB  X8, X1
+	PMOVMSKB X1, AX
+	SHLQ     CX, AX
+	ORQ      AX, R8
+
+	MOVO     X0, X1
+	PCMPEQB  X9, X1
+	PMOVMSKB X1, AX
+	SHLQ     CX, AX
+	ORQ      AX, R9
+
+	MOVO     X0, X2
+	POR      X10, X2
+	MOVO     X2, X1
+	PCMPEQB  X11, X1
+	PMOVMSKB X1, AX
+	SHLQ     CX, AX
+	ORQ      AX, R10
+	PCMPEQB  X12, X2
+	PMOVMSKB X2, AX
+	SHLQ     CX, AX
+	ORQ      AX, R11
+
+	// c <= 0x1f if max(c, 0x1f) == 0x1f
+	MOVO     X0, X1
+	PMAXUB   X13, X1
+	PCMPEQB  X13, X1
+	PMOVMSKB X1, AX
+	SHLQ     CX, AX
+	ORQ      AX, R12
+
+	ADDQ $16, CX
+	CMPQ CX, $64
+	JNE  chunk
+
+	MOVQ R8, 0(DI)
+	MOVQ R9, 8(DI)
+	MOVQ R10, 16(DI)
+	MOVQ R11, 24(DI)
+	MOVQ R12, 32(DI)
+	RET
@@ -0,0 +1,8 @@
+//go:build !amd64 || purego
+// +build !amd64 purego
+
+package jsoniter
+
+func indexBlock(block *[blockSize]byte, masks *blockMasks) {
+	indexBlockSWAR(block, masks)
+}
@@ -0,0 +1,172 @@
+package jsoniter
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func indexBlockReference(block *[blockSize]byte) blockMasks {
+	masks := blockMasks{}
+	for i, c := range block {
+		bit := uint64(1) << uint(i)
+		switch {
+		case c == '"':
+			masks.quote |= bit
+		case c == '\\':
+			masks.backslash |= bit
+		case c == '{' || c == '[':
+			masks.open |= bit
+		case c == '}' || c == ']':
+			masks.close |= bit
+		case c < 0x20:
+			masks.control |= bit
+		}
+	}
+	return masks
+}
+
+func Test_index_block(t *testing.T) {
+	should := require.New(t)
+	random := rand.New(rand.NewSource(1))
+	alphabet := []byte("\"\\{}[]:, a\x00\x1f\x20\x7f\x80\xff\x5b\x7b\x3b\x5d\x7d\x02\xa2\xdc")
+	for n := 0; n < 2000; n++ {
+		block := [blockSize]byte{}
+		for i := range block {
+			if n%2 == 0 {
+				block[i] = byte(random.Intn(256))
+			} else {
+				block[i] = alphabet[random.Intn(len(alphabet))]
+			}
+		}
+		expected := indexBlockReference(&block)
+		masks := blockMasks{}
+		indexBlock(&block, &masks)
+		should.Equal(expected, masks)
+		indexBlockSWAR(&block, &masks)
+		should.Equal(expected, masks)
+	}
+}
+
+// stringEndReference finds the first unescaped quote byte by byte
+func stringEndReference(buf []byte) int {
+	for i := 0; i < len(buf); i++ {
+		switch buf[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+func Test_index_string_end(t *testing.T) {
+	should := require.New(t)
+	random := rand.New(rand.NewSource(2))
+	alphabet := []byte(`\\\\\\"ab`)
+	for n := 0; n < 5000; n++ {
+		buf := make([]byte, random.Intn(300))
+		for i := range buf {
+			buf[i] = alphabet[random.Intn(len(alphabet))]
+		}
+		expected := stringEndReference(buf)
+		scanner := structuralScanner{}
+		should.Equal(expected, scanner.indexStringEnd(buf), string(buf))
+		// the same buffer split in two, the escape state is carried
+		split := 0
+		if len(buf) > 0 {
+			split = random.Intn(len(buf))
+		}
+		scanner = structuralScanner{}
+		end := scanner.indexStringEnd(buf[:split])
+		if end == -1 {
+			end = scanner.indexStringEnd(buf[split:])
+			if end != -1 {
+				end += split
+			}
+		}
+		should.Equal(expected, end, string(buf))
+	}
+}
+
+func Test_index_brackets(t *testing.T) {
+	should := require.New(t)
+	input := []byte(`{"a":"}]\"[{","b\\":[1,{"c":"\\\"}"}],"d":"` + string(make([]byte, 100)) + `"}`)
+	for split := 0; split <= len(input); split++ {
+		found := []int{}
+		scanner := structuralScanner{}
+		collect := func(offset int) func(int, bool) bool {
+			return func(i int, open bool) bool {
+				found = append(found, offset+i)
+				return true
+			}
+		}
+		scanner.indexBrackets(input[:split], collect(0))
+		scanner.indexBrackets(input[split:], collect(split))
+		should.Equal([]int{0, 20, 23, 35, 36, len(input) - 1}, found, split)
+	}
+}
+
+func Test_skip_with_index(t *testing.T) {
+	should := require.New(t)
+	long := `"` + strings.Repeat(`ab\\\"c{[`, 40) + `"`
+	inputs := []string{
+		`{"a":[1,{"b":"}"},"\\"],"c\"]":` + long + `,"d":{}}`,
+		`[` + long + `,[[],{}],"\u0041\\",-1.5e3,true,null]`,
+		long,
+	}
+	for _, input := range inputs {
+		for bufSize := 1; bufSize < 100; bufSize += 7 {
+			iter := Parse(ConfigDefault, strings.NewReader(input+` 42`), bufSize)
+			iter.Skip()
+			should.NoError(iter.Error, input)
+			should.Equal(42, iter.ReadInt(), input)
+		}
+		iter := ParseString(ConfigDefault, input+` 42`)
+		should.Equal(input, string(iter.SkipAndReturnBytes()))
+	}
+	should.Equal("a\\", ConfigDefault.Get([]byte(`{"x\\":1,"y\"":2,"y":{"z":"a\\"}}`), "y", "z").ToString())
+}
+
+func Test_object_fields_from_small_buffer(t *testing.T) {
+	should := require.New(t)
+	input := `{"store":{"book":[1,2],"bike" :"red"}, "count":2}`
+	for bufSize := 1; bufSize < 20; bufSize++ {
+		iter := Parse(ConfigDefault, strings.NewReader(input), bufSize)
+		fields := []string{}
+		iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+			fields = append(fields, field)
+			if field != "store" {
+				iter.Skip()
+				return true
+			}
+			return iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+				fields = append(fields, field+"="+string(iter.SkipAndReturnBytes()))
+				return true
+			})
+		})
+		should.NoError(iter.Error)
+		should.Equal([]string{"store", `book=[1,2]`, `bike="red"`, "count"}, fields, bufSize)
+		iter = Parse(ConfigDefault, strings.NewReader(input), bufSize)
+		should.Equal("[2]", MustCompilePath("$.store.book[1]").QueryIterator(iter).ToString(), bufSize)
+	}
+}
+
+func Benchmark_get_from_large_object(b *testing.B) {
+	fields := []string{}
+	for i := 0; i < 200; i++ {
+		fields = append(fields, `"field`+strconv.Itoa(i)+`":{"name":"`+strings.Repeat("x", 100)+`","tags":["a","b\"c"],"n":[1,2,3]}`)
+	}
+	data := []byte(`{` + strings.Join(fields, ",") + `,"target":1}`)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if ConfigDefault.Get(data, "target").ToInt() != 1 {
+			b.Fatal("not found")
+		}
+	}
+}
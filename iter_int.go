@@ -120,13 +120,22 @@ func (iter *Iterator) ReadUint32() (ret uint32) {
 }
 
 func (iter *Iterator) readUint32(c byte) (ret uint32) {
+	if c == '+' && iter.cfg.allowJSON5 {
+		c = iter.readByte()
+	}
 	ind := intDigits[c]
 	if ind == 0 {
+		if iter.cfg.allowJSON5 {
+			if value, ok := iter.readJSON5Hex(math.MaxUint32); ok {
+				iter.assertInteger()
+				return uint32(value)
+			}
+		}
 		iter.assertInteger()
 		return 0 // single zero
 	}
 	if ind == invalidCharForNumber {
-		iter.ReportError("readUint32", "unexpected character: "+string([]byte{byte(ind)}))
+		iter.reportUnexpectedValue("readUint32", "number", c, nil)
 		return
 	}
 	value := uint32(ind)
@@ -242,13 +251,22 @@ func (iter *Iterator) ReadUint64() uint64 {
 }
 
 func (iter *Iterator) readUint64(c byte) (ret uint64) {
+	if c == '+' && iter.cfg.allowJSON5 {
+		c = iter.readByte()
+	}
 	ind := intDigits[c]
 	if ind == 0 {
+		if iter.cfg.allowJSON5 {
+			if value, ok := iter.readJSON5Hex(math.MaxUint64); ok {
+				iter.assertInteger()
+				return uint64(value)
+			}
+		}
 		iter.assertInteger()
 		return 0 // single zero
 	}
 	if ind == invalidCharForNumber {
-		iter.ReportError("readUint64", "unexpected character: "+string([]byte{byte(ind)}))
+		iter.reportUnexpectedValue("readUint64", "number", c, nil)
 		return
 	}
 	value := uint64(ind)
@@ -0,0 +1,402 @@
+package jsoniter
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+)
+
+// JSON5 syntax accepted by Config.AllowJSON5, on top of the JSON read by the Iterator
+
+// nextTokenJSON5 continues nextToken after c, skipping the comments and the comma before } or ].
+// The comma is only trailing after an element or a member, not after [, { or another comma.
+func (iter *Iterator) nextTokenJSON5(c byte) byte {
+	c = iter.skipComments(c)
+	if c == ',' && iter.lastToken != '[' && iter.lastToken != '{' && iter.lastToken != ',' {
+		next := iter.skipComments(iter.nextNonWhitespace())
+		if next == '}' || next == ']' {
+			iter.lastToken = next
+			return next // trailing comma
+		}
+		iter.unreadByte()
+	}
+	iter.lastToken = c
+	return c
+}
+
+// nextNonWhitespace is nextToken without the JSON5 syntax
+func (iter *Iterator) nextNonWhitespace() byte {
+	for iter.skipWhitespacesWithoutLoadMore() {
+		if !iter.loadMore() {
+			return 0
+		}
+	}
+	c := iter.buf[iter.head]
+	iter.head++
+	return c
+}
+
+// skipComments skips the comments starting with c, returns the token after them
+func (iter *Iterator) skipComments(c byte) byte {
+	for c == '/' {
+		if !iter.skipComment() {
+			return 0
+		}
+		c = iter.nextNonWhitespace()
+	}
+	return c
+}
+
+// skipComment skips the // or /* comment after the consumed /
+func (iter *Iterator) skipComment() bool {
+	switch c := iter.readByte(); c {
+	case '/':
+		for {
+			for i := iter.head; i < iter.tail; i++ {
+				if iter.buf[i] == '\n' {
+					iter.head = i + 1
+					return true
+				}
+			}
+			if !iter.loadMore() {
+				return true // the comment ends with the input
+			}
+		}
+	case '*':
+		star := false
+		for {
+			for i := iter.head; i < iter.tail; i++ {
+				c := iter.buf[i]
+				if star && c == '/' {
+					iter.head = i + 1
+					return true
+				}
+				star = c == '*'
+			}
+			if !iter.loadMore() {
+				iter.ReportError("skipComment", "unterminated comment")
+				return false
+			}
+		}
+	default:
+		iter.reportUnexpected("skipComment", "/ or *", c)
+		return false
+	}
+}
+
+// json5ValueType is valueTypes with the values only JSON5 has
+func json5ValueType(c byte) ValueType {
+	switch c {
+	case '\'':
+		return StringValue
+	case '+', '.', 'I', 'N':
+		return NumberValue
+	}
+	return valueTypes[c]
+}
+
+// skipJSON5 skips the single quoted string or the number starting with c, false if c starts neither
+func (iter *Iterator) skipJSON5(c byte) bool {
+	switch json5ValueType(c) {
+	case StringValue:
+		iter.readSingleQuotedString()
+	case NumberValue:
+		iter.unreadByte()
+		iter.readNumberAsString()
+	default:
+		return false
+	}
+	return true
+}
+
+// readSingleQuotedString reads the string after the consumed ', in which " needs no escape
+func (iter *Iterator) readSingleQuotedString() string {
+	var str []byte
+	for iter.Error == nil {
+		c := iter.readByte()
+		if c == '\'' {
+			return string(str)
+		}
+		if c == '\\' {
+			str = iter.readEscapedChar(iter.readByte(), str)
+		} else if c < ' ' && iter.Error == nil {
+			iter.ReportError("readSingleQuotedString",
+				fmt.Sprintf(`invalid control character found: %d`, c))
+			return ""
+		} else {
+			str = append(str, c)
+		}
+		if !iter.checkStringLength(len(str)) {
+			return ""
+		}
+	}
+	iter.ReportError("readSingleQuotedString", "unexpected end of input")
+	return ""
+}
+
+// readJSON5Key reads the object key starting with c, quoted with " or ', or an identifier
+func (iter *Iterator) readJSON5Key(c byte) string {
+	switch {
+	case c == '"':
+		iter.unreadByte()
+		return iter.ReadString()
+	case c == '\'':
+		return iter.readSingleQuotedString()
+	case isJSON5IdentifierStart(c):
+		return iter.readJSON5Identifier(c)
+	}
+	iter.reportUnexpected("readJSON5Key", `" or ' or identifier`, c)
+	return ""
+}
+
+// isJSON5IdentifierStart tells if c starts an identifier, bytes of UTF-8 encoded runes are all allowed
+func isJSON5IdentifierStart(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_' || c == '$' || c >= 0x80
+}
+
+func (iter *Iterator) readJSON5Identifier(c byte) string {
+	ident := []byte{c}
+	for {
+		for i := iter.head; i < iter.tail; i++ {
+			c := iter.buf[i]
+			if !isJSON5IdentifierStart(c) && (c < '0' || c > '9') {
+				ident = append(ident, iter.buf[iter.head:i]...)
+				iter.head = i
+				iter.checkStringLength(len(ident))
+				return string(ident)
+			}
+		}
+		ident = append(ident, iter.buf[iter.head:iter.tail]...)
+		if !iter.checkStringLength(len(ident)) || !iter.loadMore() {
+			return string(ident)
+		}
+	}
+}
+
+// readJSON5Number is readNumberAsString of AllowJSON5, the number is returned in JSON notation,
+// except Infinity and NaN which JSON has no notation for
+func (iter *Iterator) readJSON5Number() string {
+	var str []byte
+load_loop:
+	for {
+		for i := iter.head; i < iter.tail; i++ {
+			c := iter.buf[i]
+			if c != '+' && c != '-' && c != '.' && (c < '0' || c > '9') && (c|0x20 < 'a' || c|0x20 > 'z') {
+				str = append(str, iter.buf[iter.head:i]...)
+				iter.head = i
+				break load_loop
+			}
+		}
+		str = append(str, iter.buf[iter.head:iter.tail]...)
+		if !iter.checkNumberLength(len(str)) {
+			return ""
+		}
+		if !iter.loadMore() {
+			break
+		}
+	}
+	if !iter.checkNumberLength(len(str)) {
+		return ""
+	}
+	if iter.Error != nil && iter.Error != io.EOF {
+		return ""
+	}
+	number, ok := json5NumberAsJSON(string(str))
+	if !ok {
+		iter.ReportError("readNumberAsString", "invalid number")
+	}
+	return number
+}
+
+// json5NumberAsJSON rewrites the JSON5 number in JSON notation, false if it is not a number
+func json5NumberAsJSON(str string) (string, bool) {
+	sign := ""
+	if str != "" && (str[0] == '+' || str[0] == '-') {
+		if str[0] == '-' {
+			sign = "-"
+		}
+		str = str[1:]
+	}
+	switch {
+	case str == "Infinity":
+		return sign + str, true
+	case str == "NaN":
+		return str, true // strconv.ParseFloat does not take signed NaN
+	case len(str) > 2 && str[0] == '0' && (str[1] == 'x' || str[1] == 'X'):
+		for i := 2; i < len(str); i++ {
+			if hexDigits[str[i]] == 255 {
+				return "", false
+			}
+		}
+		if val, err := strconv.ParseUint(str[2:], 16, 64); err == nil {
+			return sign + strconv.FormatUint(val, 10), true
+		}
+		val, _ := new(big.Int).SetString(str[2:], 16)
+		return sign + val.String(), true
+	}
+	// the integer or the fraction may be omitted around the dot
+	digitsEnd := func(from int) int {
+		for from < len(str) && '0' <= str[from] && str[from] <= '9' {
+			from++
+		}
+		return from
+	}
+	intEnd := digitsEnd(0)
+	integer, fraction, exponent := str[:intEnd], "", str[intEnd:]
+	if exponent != "" && exponent[0] == '.' {
+		fracEnd := digitsEnd(intEnd + 1)
+		fraction, exponent = str[intEnd+1:fracEnd], str[fracEnd:]
+	}
+	if integer == "" && fraction == "" || len(integer) > 1 && integer[0] == '0' {
+		return "", false
+	}
+	if exponent != "" {
+		if exponent[0] != 'e' && exponent[0] != 'E' {
+			return "", false
+		}
+		expStart := len(str) - len(exponent) + 1
+		if expStart < len(str) && (str[expStart] == '+' || str[expStart] == '-') {
+			expStart++
+		}
+		if expStart == len(str) || digitsEnd(expStart) != len(str) {
+			return "", false
+		}
+	}
+	if integer == "" {
+		integer = "0"
+	}
+	if fraction != "" {
+		return sign + integer + "." + fraction + exponent, true
+	}
+	return sign + integer + exponent, true
+}
+
+// readJSON5Float reads the float of AllowJSON5, which may be hex, Infinity or NaN
+func (iter *Iterator) readJSON5Float(bitSize int) float64 {
+	str := iter.readNumberAsString()
+	if iter.Error != nil && iter.Error != io.EOF {
+		return 0
+	}
+	val, err := strconv.ParseFloat(str, bitSize)
+	if err != nil {
+		iter.Error = err
+		return 0
+	}
+	return val
+}
+
+// readJSON5Hex reads the hex number after the consumed 0, if x or X is next, up to max
+func (iter *Iterator) readJSON5Hex(max uint64) (uint64, bool) {
+	if iter.head == iter.tail && (iter.reader == nil || !iter.loadMore()) {
+		return 0, false
+	}
+	if c := iter.buf[iter.head]; c != 'x' && c != 'X' {
+		return 0, false
+	}
+	iter.head++
+	var value uint64
+	digits := 0
+	for {
+		for i := iter.head; i < iter.tail; i++ {
+			ind := hexDigits[iter.buf[i]]
+			if ind == 255 {
+				iter.head = i
+				if digits == 0 {
+					iter.ReportError("readJSON5Hex", "missing hex digit after 0x")
+				}
+				return value, true
+			}
+			if value > (max-uint64(ind))/16 {
+				iter.ReportError("readJSON5Hex", "overflow")
+				return 0, true
+			}
+			value = value*16 + uint64(ind)
+			digits++
+		}
+		if !iter.loadMore() {
+			if digits == 0 {
+				iter.ReportError("readJSON5Hex", "missing hex digit after 0x")
+			}
+			return value, true
+		}
+	}
+}
+
+// appendJSON5AsJSON skips the next value and appends it to buf rewritten as standard JSON:
+// without comments and trailing commas, with double quoted keys and strings and with decimal numbers.
+// Infinity and NaN are kept, as JSON has no notation for them.
+func (iter *Iterator) appendJSON5AsJSON(buf []byte) []byte {
+	stream := NewStream(iter.cfg, nil, 0)
+	stream.buf = buf
+	iter.writeJSON5AsJSON(stream)
+	return stream.buf
+}
+
+func (iter *Iterator) writeJSON5AsJSON(stream *Stream) {
+	c := iter.nextToken()
+	switch json5ValueType(c) {
+	case ObjectValue:
+		iter.unreadByte()
+		stream.writeByte('{')
+		more := false
+		iter.ReadMapCB(func(iter *Iterator, key string) bool {
+			if more {
+				stream.writeByte(',')
+			}
+			more = true
+			stream.WriteString(key)
+			stream.writeByte(':')
+			iter.writeJSON5AsJSON(stream)
+			return true
+		})
+		stream.writeByte('}')
+	case ArrayValue:
+		iter.unreadByte()
+		stream.writeByte('[')
+		more := false
+		iter.ReadArrayCB(func(iter *Iterator) bool {
+			if more {
+				stream.writeByte(',')
+			}
+			more = true
+			iter.writeJSON5AsJSON(stream)
+			return true
+		})
+		stream.writeByte(']')
+	case StringValue:
+		iter.unreadByte()
+		stream.WriteString(iter.ReadString())
+	case NumberValue:
+		iter.unreadByte()
+		stream.WriteRaw(iter.readNumberAsString())
+	case NilValue:
+		iter.skipThreeBytes('u', 'l', 'l')
+		stream.WriteNil()
+	case BoolValue:
+		iter.unreadByte()
+		stream.WriteBool(iter.ReadBool())
+	default:
+		iter.ReportError("Skip", fmt.Sprintf("do not know how to skip: %v", c))
+	}
+}
+
+// readJSON5Any reads the value starting with c as standard JSON, which the lazy Any keeps and writes,
+// returns nil for the values read the same as JSON
+func (iter *Iterator) readJSON5Any(c byte) Any {
+	switch json5ValueType(c) {
+	case StringValue:
+		iter.unreadByte()
+		return &stringAny{baseAny{}, iter.ReadString()}
+	case NumberValue:
+		iter.unreadByte()
+		return &numberLazyAny{baseAny{}, iter.cfg, []byte(iter.readNumberAsString()), nil}
+	case ArrayValue:
+		iter.unreadByte()
+		return &arrayLazyAny{baseAny{}, iter.cfg, iter.SkipAndReturnBytes(), nil}
+	case ObjectValue:
+		iter.unreadByte()
+		return &objectLazyAny{baseAny{}, iter.cfg, iter.SkipAndReturnBytes(), nil}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package jsoniter
+
+import (
+	"context"
+	"fmt"
+)
+
+// defaultMaxDepth is the nesting limit if Config.MaxDepth is not set
+const defaultMaxDepth = 10000
+
+// LimitError is reported by Iterator when the input exceeds a limit set by Config.
+// It is kept as the error of the iterator, so the input is not read further.
+type LimitError struct {
+	Limit  string // the Config field of the limit, for example MaxDepth
+	Max    int64  // value of the limit
+	Offset int64  // offset of the input where the limit is exceeded
+	Field  string // full path of the field from the root value, for example Items[3].Name
+}
+
+var limitDescriptions = map[string]string{
+	"MaxDepth":        "max depth",
+	"MaxBytes":        "max bytes",
+	"MaxStringLength": "max string length",
+	"MaxElements":     "max elements",
+	"MaxNumberLength": "max number length",
+}
+
+func (err *LimitError) Error() string {
+	prefix := ""
+	if err.Field != "" {
+		prefix = err.Field + ": "
+	}
+	return fmt.Sprintf("%sexceeded %s %d at offset %d", prefix, limitDescriptions[err.Limit], err.Max, err.Offset)
+}
+
+// reportLimit reports the limit exceeded, unless the iterator already failed
+func (iter *Iterator) reportLimit(limit string, max int64) {
+	if iter.Error != nil && !iter.isEOF() {
+		return
+	}
+	iter.Error = &LimitError{Limit: limit, Max: max, Offset: iter.InputOffset()}
+}
+
+// checkElements reports MaxElements if count is above it
+func (iter *Iterator) checkElements(count int) bool {
+	if max := iter.cfg.maxElements; max > 0 && count > max {
+		iter.reportLimit("MaxElements", int64(max))
+		return false
+	}
+	return true
+}
+
+// checkStringLength reports MaxStringLength if length is above it
+func (iter *Iterator) checkStringLength(length int) bool {
+	if max := iter.cfg.maxStringLength; max > 0 && length > max {
+		iter.reportLimit("MaxStringLength", int64(max))
+		return false
+	}
+	return true
+}
+
+// checkNumberLength reports MaxNumberLength if length is above it
+func (iter *Iterator) checkNumberLength(length int) bool {
+	if max := iter.cfg.maxNumberLength; max > 0 && length > max {
+		iter.reportLimit("MaxNumberLength", int64(max))
+		return false
+	}
+	return true
+}
+
+// checkContext reports the error of the context, if it is done
+func (iter *Iterator) checkContext() bool {
+	if iter.ctx == nil {
+		return true
+	}
+	select {
+	case <-iter.ctx.Done():
+		if iter.Error == nil || iter.isEOF() {
+			iter.Error = iter.ctx.Err()
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// isAbortError tells if the error stops the decoding as is, without the path of the value failed
+func isAbortError(err error) bool {
+	switch err.(type) {
+	case *LimitError:
+		return true
+	}
+	return err == context.Canceled || err == context.DeadlineExceeded
+}
@@ -16,25 +16,24 @@ func (iter *Iterator) ReadObject() (ret string) {
 		return "" // null
 	case '{':
 		c = iter.nextToken()
-		if c == '"' {
-			iter.unreadByte()
-			field := iter.ReadString()
+		if c == '"' || (c != '}' && iter.cfg.allowJSON5) {
+			field := iter.readKeyAfter(c)
 			c = iter.nextToken()
 			if c != ':' {
-				iter.ReportError("ReadObject", "expect : after object field, but found "+string([]byte{c}))
+				iter.reportUnexpected("ReadObject", ":", c)
 			}
 			return field
 		}
 		if c == '}' {
 			return "" // end of object
 		}
-		iter.ReportError("ReadObject", `expect " after {, but found `+string([]byte{c}))
+		iter.reportUnexpected("ReadObject", `" or }`, c)
 		return
 	case ',':
-		field := iter.ReadString()
+		field := iter.readKeyAfter(iter.nextToken())
 		c = iter.nextToken()
 		if c != ':' {
-			iter.ReportError("ReadObject", "expect : after object field, but found "+string([]byte{c}))
+			iter.reportUnexpected("ReadObject", ":", c)
 		}
 		return field
 	case '}':
@@ -50,7 +49,10 @@ func (iter *Iterator) readFieldHash() int64 {
 	hash := int64(0x811c9dc5)
 	c := iter.nextToken()
 	if c != '"' {
-		iter.ReportError("readFieldHash", `expect ", but found `+string([]byte{c}))
+		if iter.cfg.allowJSON5 {
+			return iter.readJSON5FieldHash(c)
+		}
+		iter.reportUnexpected("readFieldHash", `"`, c)
 		return 0
 	}
 	for {
@@ -68,7 +70,7 @@ func (iter *Iterator) readFieldHash() int64 {
 				}
 				c = iter.nextToken()
 				if c != ':' {
-					iter.ReportError("readFieldHash", `expect :, but found `+string([]byte{c}))
+					iter.reportUnexpected("readFieldHash", ":", c)
 					return 0
 				}
 				return hash
@@ -77,7 +79,7 @@ func (iter *Iterator) readFieldHash() int64 {
 				iter.head = i + 1
 				c = iter.nextToken()
 				if c != ':' {
-					iter.ReportError("readFieldHash", `expect :, but found `+string([]byte{c}))
+					iter.reportUnexpected("readFieldHash", ":", c)
 					return 0
 				}
 				return hash
@@ -95,6 +97,33 @@ func (iter *Iterator) readFieldHash() int64 {
 	}
 }
 
+// readJSON5FieldHash is readFieldHash of the key starting with c, which is not double quoted
+func (iter *Iterator) readJSON5FieldHash(c byte) int64 {
+	hash := calcHash(iter.readJSON5Key(c), iter.cfg.caseSensitive)
+	c = iter.nextToken()
+	if c != ':' {
+		iter.reportUnexpected("readFieldHash", ":", c)
+		return 0
+	}
+	return hash
+}
+
+// readFieldNameAndHash is readFieldHash also returning the field name, for the struct decoders capturing the unknown fields
+func (iter *Iterator) readFieldNameAndHash() (string, int64) {
+	c := iter.nextToken()
+	if c != '"' && !iter.cfg.allowJSON5 {
+		iter.reportUnexpected("readFieldHash", `"`, c)
+		return "", 0
+	}
+	field := iter.readJSON5Key(c)
+	c = iter.nextToken()
+	if c != ':' {
+		iter.reportUnexpected("readFieldHash", ":", c)
+		return "", 0
+	}
+	return field, calcHash(field, iter.cfg.caseSensitive)
+}
+
 func calcHash(str string, caseSensitive bool) int64 {
 	if !caseSensitive {
 		str = strings.ToLower(str)
@@ -109,30 +138,43 @@ func calcHash(str string, caseSensitive bool) int64 {
 
 // ReadObjectCB read object with callback, the key is ascii only and field name not copied
 func (iter *Iterator) ReadObjectCB(callback func(*Iterator, string) bool) bool {
+	return iter.readObjectFieldsCB(func(iter *Iterator, field []byte) bool {
+		return callback(iter, string(field))
+	})
+}
+
+// readObjectFieldsCB is ReadObjectCB passing the field as slice, which is only valid during the callback.
+// Field names without escape are located with the structural index and not copied.
+func (iter *Iterator) readObjectFieldsCB(callback func(*Iterator, []byte) bool) bool {
 	c := iter.nextToken()
-	var field string
+	var field []byte
 	if c == '{' {
 		if !iter.incrementDepth() {
 			return false
 		}
 		c = iter.nextToken()
-		if c == '"' {
-			iter.unreadByte()
-			field = iter.ReadString()
+		if c == '"' || (c != '}' && iter.cfg.allowJSON5) {
+			field = iter.readFieldAfter(c)
 			c = iter.nextToken()
 			if c != ':' {
-				iter.ReportError("ReadObject", "expect : after object field, but found "+string([]byte{c}))
+				iter.reportUnexpected("ReadObject", ":", c)
 			}
 			if !callback(iter, field) {
 				iter.decrementDepth()
 				return false
 			}
 			c = iter.nextToken()
+			count := 1
 			for c == ',' {
-				field = iter.ReadString()
+				count++
+				if !iter.checkElements(count) {
+					iter.decrementDepth()
+					return false
+				}
+				field = iter.readField()
 				c = iter.nextToken()
 				if c != ':' {
-					iter.ReportError("ReadObject", "expect : after object field, but found "+string([]byte{c}))
+					iter.reportUnexpected("ReadObject", ":", c)
 				}
 				if !callback(iter, field) {
 					iter.decrementDepth()
@@ -150,7 +192,7 @@ func (iter *Iterator) ReadObjectCB(callback func(*Iterator, string) bool) bool {
 		if c == '}' {
 			return iter.decrementDepth()
 		}
-		iter.ReportError("ReadObjectCB", `expect " after {, but found `+string([]byte{c}))
+		iter.reportUnexpected("ReadObjectCB", `" or }`, c)
 		iter.decrementDepth()
 		return false
 	}
@@ -158,10 +200,51 @@ func (iter *Iterator) ReadObjectCB(callback func(*Iterator, string) bool) bool {
 		iter.skipThreeBytes('u', 'l', 'l')
 		return true // null
 	}
-	iter.ReportError("ReadObjectCB", `expect { or n, but found `+string([]byte{c}))
+	iter.reportUnexpectedValue("ReadObjectCB", "{ or n", c, nil)
 	return false
 }
 
+// readField reads the field name, see readObjectFieldsCB
+func (iter *Iterator) readField() []byte {
+	return iter.readFieldAfter(iter.nextToken())
+}
+
+// readFieldAfter reads the field name starting with c
+func (iter *Iterator) readFieldAfter(c byte) []byte {
+	if c == '"' {
+		return iter.readFieldAfterQuote()
+	}
+	return []byte(iter.readKeyAfter(c))
+}
+
+// readKeyAfter reads the key starting with c, which is JSON5 key with AllowJSON5
+func (iter *Iterator) readKeyAfter(c byte) string {
+	if iter.cfg.allowJSON5 {
+		return iter.readJSON5Key(c)
+	}
+	iter.unreadByte()
+	return iter.ReadString()
+}
+
+// readFieldAfterQuote shares the buffer only if the iterator has no reader,
+// as reading the following : may refill the buffer before the callback sees the field
+func (iter *Iterator) readFieldAfterQuote() []byte {
+	if iter.reader != nil {
+		iter.unreadByte()
+		return []byte(iter.ReadString())
+	}
+	if i := indexStringStop(iter.buf[iter.head:iter.tail]); i != -1 && iter.buf[iter.head+i] == '"' {
+		if !iter.checkStringLength(i) {
+			return nil
+		}
+		field := iter.buf[iter.head : iter.head+i]
+		iter.head += i + 1
+		return field
+	}
+	iter.unreadByte()
+	return []byte(iter.ReadString())
+}
+
 // ReadMapCB read map with callback, the key can be any string
 func (iter *Iterator) ReadMapCB(callback func(*Iterator, string) bool) bool {
 	c := iter.nextToken()
@@ -170,11 +253,10 @@ func (iter *Iterator) ReadMapCB(callback func(*Iterator, string) bool) bool {
 			return false
 		}
 		c = iter.nextToken()
-		if c == '"' {
-			iter.unreadByte()
-			field := iter.ReadString()
-			if iter.nextToken() != ':' {
-				iter.ReportError("ReadMapCB", "expect : after object field, but found "+string([]byte{c}))
+		if c == '"' || (c != '}' && iter.cfg.allowJSON5) {
+			field := iter.readKeyAfter(c)
+			if c = iter.nextToken(); c != ':' {
+				iter.reportUnexpected("ReadMapCB", ":", c)
 				iter.decrementDepth()
 				return false
 			}
@@ -183,10 +265,16 @@ func (iter *Iterator) ReadMapCB(callback func(*Iterator, string) bool) bool {
 				return false
 			}
 			c = iter.nextToken()
+			count := 1
 			for c == ',' {
-				field = iter.ReadString()
-				if iter.nextToken() != ':' {
-					iter.ReportError("ReadMapCB", "expect : after object field, but found "+string([]byte{c}))
+				count++
+				if !iter.checkElements(count) {
+					iter.decrementDepth()
+					return false
+				}
+				field = iter.readKeyAfter(iter.nextToken())
+				if c = iter.nextToken(); c != ':' {
+					iter.reportUnexpected("ReadMapCB", ":", c)
 					iter.decrementDepth()
 					return false
 				}
@@ -206,7 +294,7 @@ func (iter *Iterator) ReadMapCB(callback func(*Iterator, string) bool) bool {
 		if c == '}' {
 			return iter.decrementDepth()
 		}
-		iter.ReportError("ReadMapCB", `expect " after {, but found `+string([]byte{c}))
+		iter.reportUnexpected("ReadMapCB", `" or }`, c)
 		iter.decrementDepth()
 		return false
 	}
@@ -214,7 +302,7 @@ func (iter *Iterator) ReadMapCB(callback func(*Iterator, string) bool) bool {
 		iter.skipThreeBytes('u', 'l', 'l')
 		return true // null
 	}
-	iter.ReportError("ReadMapCB", `expect { or n, but found `+string([]byte{c}))
+	iter.reportUnexpectedValue("ReadMapCB", "{ or n", c, nil)
 	return false
 }
 
@@ -231,7 +319,7 @@ func (iter *Iterator) readObjectStart() bool {
 		iter.skipThreeBytes('u', 'l', 'l')
 		return false
 	}
-	iter.ReportError("readObjectStart", "expect { or n, but found "+string([]byte{c}))
+	iter.reportUnexpectedValue("readObjectStart", "{ or n", c, nil)
 	return false
 }
 
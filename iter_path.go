@@ -0,0 +1,875 @@
+package jsoniter
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Path is a compiled JSONPath expression, such as $.store.book[?(@.price < 10)].title
+// It supports member names, wildcards, indices, slices, unions, filters and recursive descent.
+// Values not selected by the path are skipped, not decoded.
+type Path struct {
+	expr      string
+	segments  []pathSegment
+	needsRoot bool
+}
+
+type pathSegment struct {
+	descendant bool
+	selectors  []pathSelector
+}
+
+type pathSelectorKind int
+
+const (
+	pathName pathSelectorKind = iota
+	pathWildcard
+	pathIndex
+	pathSlice
+	pathFilter
+)
+
+type pathSelector struct {
+	kind     pathSelectorKind
+	name     string
+	index    int
+	start    int
+	end      int
+	step     int
+	hasStart bool
+	hasEnd   bool
+	filter   *pathFilterExpr
+}
+
+// pathFilterExpr is a node of filter expression.
+// op is "||", "&&" or "!" for logical expression on left and right,
+// a comparison operator on lhs and rhs, or "" for the existence test of lhs.
+type pathFilterExpr struct {
+	op    string
+	left  *pathFilterExpr
+	right *pathFilterExpr
+	lhs   *pathOperand
+	rhs   *pathOperand
+}
+
+type pathOperand struct {
+	literal  Any
+	root     bool
+	segments []pathSegment
+}
+
+// CompilePath parses a JSONPath expression so that it can be evaluated many times.
+func CompilePath(expr string) (*Path, error) {
+	parser := &pathParser{expr: expr}
+	if !parser.consume('$') {
+		return nil, parser.errorf("expect $")
+	}
+	segments, err := parser.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	if parser.pos != len(expr) {
+		return nil, parser.errorf("unexpected %q", expr[parser.pos])
+	}
+	return &Path{expr: expr, segments: segments, needsRoot: parser.needsRoot}, nil
+}
+
+// MustCompilePath is like CompilePath but panics if the expression can not be parsed.
+func MustCompilePath(expr string) *Path {
+	path, err := CompilePath(expr)
+	if err != nil {
+		panic(err)
+	}
+	return path
+}
+
+// String returns the source expression of the path.
+func (path *Path) String() string {
+	return path.expr
+}
+
+// Query evaluates the path against data, and returns the matched values as an array of lazy Any.
+func (path *Path) Query(data []byte) Any {
+	return path.query(ConfigDefault.(*frozenConfig), data)
+}
+
+// QueryAny evaluates the path against any, and returns the matched values as an array of lazy Any.
+func (path *Path) QueryAny(any Any) Any {
+	switch lazy := any.(type) {
+	case *objectLazyAny:
+		return path.query(lazy.cfg, lazy.buf)
+	case *arrayLazyAny:
+		return path.query(lazy.cfg, lazy.buf)
+	}
+	if any.ValueType() == InvalidValue {
+		return any
+	}
+	data, err := ConfigDefault.Marshal(any)
+	if err != nil {
+		return &invalidAny{baseAny{}, err}
+	}
+	return path.query(ConfigDefault.(*frozenConfig), data)
+}
+
+// QueryIterator evaluates the path against the next value of iter, which is consumed entirely.
+// The value is streamed, only the matched values and filter candidates are captured.
+func (path *Path) QueryIterator(iter *Iterator) Any {
+	if path.needsRoot {
+		data := iter.SkipAndReturnBytes()
+		if iter.Error != nil && iter.Error != io.EOF {
+			return &invalidAny{baseAny{}, iter.Error}
+		}
+		return path.query(iter.cfg, data)
+	}
+	ctx := &pathContext{cfg: iter.cfg}
+	found := ctx.eval(iter, path.segments, []Any{})
+	ctx.checkError(iter)
+	return ctx.result(found)
+}
+
+func (path *Path) query(cfg *frozenConfig, data []byte) Any {
+	ctx := &pathContext{cfg: cfg, root: data}
+	return ctx.result(ctx.evalBytes(data, path.segments, []Any{}))
+}
+
+type pathContext struct {
+	cfg  *frozenConfig
+	root []byte
+	err  error
+}
+
+func (ctx *pathContext) result(found []Any) Any {
+	if ctx.err != nil {
+		return &invalidAny{baseAny{}, ctx.err}
+	}
+	return wrapArray(found)
+}
+
+func (ctx *pathContext) checkError(iter *Iterator) {
+	if ctx.err == nil && iter.Error != nil && iter.Error != io.EOF {
+		ctx.err = iter.Error
+	}
+}
+
+func (ctx *pathContext) evalBytes(data []byte, segments []pathSegment, found []Any) []Any {
+	iter := ctx.cfg.BorrowIterator(data)
+	defer ctx.cfg.ReturnIterator(iter)
+	found = ctx.eval(iter, segments, found)
+	ctx.checkError(iter)
+	return found
+}
+
+func (ctx *pathContext) eval(iter *Iterator, segments []pathSegment, found []Any) []Any {
+	if len(segments) == 0 {
+		return append(found, iter.readAny())
+	}
+	if segments[0].descendant {
+		return ctx.evalDescendant(iter.SkipAndReturnBytes(), segments[0], segments[1:], found)
+	}
+	return ctx.evalSegment(iter, segments[0], segments[1:], found)
+}
+
+// evalDescendant applies segment to value and then to every descendant of value, in document order
+func (ctx *pathContext) evalDescendant(value []byte, segment pathSegment, rest []pathSegment, found []Any) []Any {
+	iter := ctx.cfg.BorrowIterator(value)
+	found = ctx.evalSegment(iter, segment, rest, found)
+	ctx.checkError(iter)
+	ctx.cfg.ReturnIterator(iter)
+	iter = ctx.cfg.BorrowIterator(value)
+	defer ctx.cfg.ReturnIterator(iter)
+	descend := func(iter *Iterator) bool {
+		switch iter.WhatIsNext() {
+		case ObjectValue, ArrayValue:
+			found = ctx.evalDescendant(iter.SkipAndReturnBytes(), segment, rest, found)
+		default:
+			iter.Skip()
+		}
+		return true
+	}
+	switch iter.WhatIsNext() {
+	case ObjectValue:
+		iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+			return descend(iter)
+		})
+	case ArrayValue:
+		iter.ReadArrayCB(descend)
+	}
+	ctx.checkError(iter)
+	return found
+}
+
+func (ctx *pathContext) evalSegment(iter *Iterator, segment pathSegment, rest []pathSegment, found []Any) []Any {
+	switch iter.WhatIsNext() {
+	case ObjectValue:
+		if len(segment.selectors) == 1 {
+			return ctx.streamObject(iter, segment.selectors[0], rest, found)
+		}
+		return ctx.bufferObject(iter, segment.selectors, rest, found)
+	case ArrayValue:
+		if len(segment.selectors) == 1 && segment.selectors[0].streamable() {
+			return ctx.streamArray(iter, segment.selectors[0], rest, found)
+		}
+		return ctx.bufferArray(iter, segment.selectors, rest, found)
+	}
+	iter.Skip()
+	return found
+}
+
+func (ctx *pathContext) streamObject(iter *Iterator, selector pathSelector, rest []pathSegment, found []Any) []Any {
+	iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+		switch {
+		case selector.kind == pathName && selector.name == field, selector.kind == pathWildcard:
+			found = ctx.eval(iter, rest, found)
+		case selector.kind == pathFilter:
+			found = ctx.filter(iter.SkipAndReturnBytes(), selector.filter, rest, found)
+		default:
+			iter.Skip()
+		}
+		return true
+	})
+	return found
+}
+
+func (ctx *pathContext) bufferObject(iter *Iterator, selectors []pathSelector, rest []pathSegment, found []Any) []Any {
+	var fields []string
+	var values [][]byte
+	iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+		fields = append(fields, field)
+		values = append(values, iter.SkipAndReturnBytes())
+		return true
+	})
+	for _, selector := range selectors {
+		for i, value := range values {
+			switch {
+			case selector.kind == pathName && selector.name == fields[i], selector.kind == pathWildcard:
+				found = ctx.evalBytes(value, rest, found)
+			case selector.kind == pathFilter:
+				found = ctx.filter(value, selector.filter, rest, found)
+			}
+		}
+	}
+	return found
+}
+
+func (ctx *pathContext) streamArray(iter *Iterator, selector pathSelector, rest []pathSegment, found []Any) []Any {
+	index := 0
+	iter.ReadArrayCB(func(iter *Iterator) bool {
+		switch {
+		case selector.kind == pathWildcard,
+			selector.kind == pathIndex && selector.index == index,
+			selector.kind == pathSlice && selector.contains(index):
+			found = ctx.eval(iter, rest, found)
+		case selector.kind == pathFilter:
+			found = ctx.filter(iter.SkipAndReturnBytes(), selector.filter, rest, found)
+		default:
+			iter.Skip()
+		}
+		index++
+		return true
+	})
+	return found
+}
+
+func (ctx *pathContext) bufferArray(iter *Iterator, selectors []pathSelector, rest []pathSegment, found []Any) []Any {
+	var elements [][]byte
+	iter.ReadArrayCB(func(iter *Iterator) bool {
+		elements = append(elements, iter.SkipAndReturnBytes())
+		return true
+	})
+	for _, selector := range selectors {
+		switch selector.kind {
+		case pathWildcard:
+			for _, element := range elements {
+				found = ctx.evalBytes(element, rest, found)
+			}
+		case pathIndex:
+			index := selector.index
+			if index < 0 {
+				index += len(elements)
+			}
+			if index >= 0 && index < len(elements) {
+				found = ctx.evalBytes(elements[index], rest, found)
+			}
+		case pathSlice:
+			for _, index := range selector.indices(len(elements)) {
+				found = ctx.evalBytes(elements[index], rest, found)
+			}
+		case pathFilter:
+			for _, element := range elements {
+				found = ctx.filter(element, selector.filter, rest, found)
+			}
+		}
+	}
+	return found
+}
+
+func (ctx *pathContext) filter(value []byte, expr *pathFilterExpr, rest []pathSegment, found []Any) []Any {
+	if !ctx.test(expr, value) {
+		return found
+	}
+	return ctx.evalBytes(value, rest, found)
+}
+
+func (ctx *pathContext) test(expr *pathFilterExpr, current []byte) bool {
+	switch expr.op {
+	case "||":
+		return ctx.test(expr.left, current) || ctx.test(expr.right, current)
+	case "&&":
+		return ctx.test(expr.left, current) && ctx.test(expr.right, current)
+	case "!":
+		return !ctx.test(expr.left, current)
+	case "":
+		return len(ctx.query(expr.lhs, current)) != 0
+	}
+	left := ctx.operand(expr.lhs, current)
+	right := ctx.operand(expr.rhs, current)
+	switch expr.op {
+	case "==":
+		return pathValuesEqual(left, right)
+	case "!=":
+		return !pathValuesEqual(left, right)
+	case "<":
+		return pathValueLess(left, right)
+	case "<=":
+		return pathValueLess(left, right) || pathValuesEqual(left, right)
+	case ">":
+		return pathValueLess(right, left)
+	case ">=":
+		return pathValueLess(right, left) || pathValuesEqual(left, right)
+	}
+	return false
+}
+
+func (ctx *pathContext) query(operand *pathOperand, current []byte) []Any {
+	if operand.root {
+		return ctx.evalBytes(ctx.root, operand.segments, nil)
+	}
+	return ctx.evalBytes(current, operand.segments, nil)
+}
+
+// operand returns nil if the singular query selects nothing
+func (ctx *pathContext) operand(operand *pathOperand, current []byte) Any {
+	if operand.literal != nil {
+		return operand.literal
+	}
+	found := ctx.query(operand, current)
+	if len(found) != 1 {
+		return nil
+	}
+	return found[0]
+}
+
+func pathValuesEqual(left Any, right Any) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	if left.ValueType() != right.ValueType() {
+		return false
+	}
+	switch left.ValueType() {
+	case NumberValue:
+		return left.ToFloat64() == right.ToFloat64()
+	case StringValue:
+		return left.ToString() == right.ToString()
+	case BoolValue:
+		return left.ToBool() == right.ToBool()
+	case NilValue:
+		return true
+	}
+	return reflect.DeepEqual(left.GetInterface(), right.GetInterface())
+}
+
+func pathValueLess(left Any, right Any) bool {
+	if left == nil || right == nil || left.ValueType() != right.ValueType() {
+		return false
+	}
+	switch left.ValueType() {
+	case NumberValue:
+		return left.ToFloat64() < right.ToFloat64()
+	case StringValue:
+		return left.ToString() < right.ToString()
+	}
+	return false
+}
+
+// streamable tells if the selector can be applied to array without knowing its length
+func (selector *pathSelector) streamable() bool {
+	switch selector.kind {
+	case pathIndex:
+		return selector.index >= 0
+	case pathSlice:
+		return selector.step > 0 && selector.start >= 0 && (!selector.hasEnd || selector.end >= 0)
+	}
+	return true
+}
+
+// contains is only used for streamable slice
+func (selector *pathSelector) contains(index int) bool {
+	if index < selector.start || selector.hasEnd && index >= selector.end {
+		return false
+	}
+	return (index-selector.start)%selector.step == 0
+}
+
+// indices returns the selected indices of array with the given length, in selection order
+func (selector *pathSelector) indices(length int) []int {
+	if selector.step == 0 {
+		return nil
+	}
+	normalize := func(index int) int {
+		if index < 0 {
+			return index + length
+		}
+		return index
+	}
+	clamp := func(index int, min int, max int) int {
+		if index < min {
+			return min
+		}
+		if index > max {
+			return max
+		}
+		return index
+	}
+	var indices []int
+	if selector.step > 0 {
+		lower, upper := 0, length
+		if selector.hasStart {
+			lower = clamp(normalize(selector.start), 0, length)
+		}
+		if selector.hasEnd {
+			upper = clamp(normalize(selector.end), 0, length)
+		}
+		for i := lower; i < upper; i += selector.step {
+			indices = append(indices, i)
+		}
+		return indices
+	}
+	upper, lower := length-1, -1
+	if selector.hasStart {
+		upper = clamp(normalize(selector.start), -1, length-1)
+	}
+	if selector.hasEnd {
+		lower = clamp(normalize(selector.end), -1, length-1)
+	}
+	for i := upper; i > lower; i += selector.step {
+		indices = append(indices, i)
+	}
+	return indices
+}
+
+type pathParser struct {
+	expr      string
+	pos       int
+	needsRoot bool
+}
+
+func (parser *pathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("jsonpath %q: %s at offset %d", parser.expr, fmt.Sprintf(format, args...), parser.pos)
+}
+
+func (parser *pathParser) eof() bool {
+	return parser.pos >= len(parser.expr)
+}
+
+func (parser *pathParser) peek() byte {
+	if parser.eof() {
+		return 0
+	}
+	return parser.expr[parser.pos]
+}
+
+func (parser *pathParser) consume(c byte) bool {
+	if parser.peek() != c {
+		return false
+	}
+	parser.pos++
+	return true
+}
+
+func (parser *pathParser) consumeString(s string) bool {
+	if !strings.HasPrefix(parser.expr[parser.pos:], s) {
+		return false
+	}
+	parser.pos += len(s)
+	return true
+}
+
+func (parser *pathParser) skipWhitespace() {
+	for !parser.eof() {
+		switch parser.expr[parser.pos] {
+		case ' ', '\t', '\n', '\r':
+			parser.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (parser *pathParser) parseSegments() ([]pathSegment, error) {
+	var segments []pathSegment
+	for {
+		start := parser.pos
+		parser.skipWhitespace()
+		var segment pathSegment
+		var err error
+		switch {
+		case parser.consumeString(".."):
+			if parser.peek() == '[' {
+				segment, err = parser.parseBracket()
+			} else {
+				segment, err = parser.parseShorthand()
+			}
+			segment.descendant = true
+		case parser.consume('.'):
+			segment, err = parser.parseShorthand()
+		case parser.peek() == '[':
+			segment, err = parser.parseBracket()
+		default:
+			parser.pos = start
+			return segments, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment)
+	}
+}
+
+func (parser *pathParser) parseShorthand() (pathSegment, error) {
+	if parser.consume('*') {
+		return pathSegment{selectors: []pathSelector{{kind: pathWildcard}}}, nil
+	}
+	start := parser.pos
+	for !parser.eof() {
+		c := parser.expr[parser.pos]
+		if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80 ||
+			parser.pos > start && c >= '0' && c <= '9' {
+			parser.pos++
+			continue
+		}
+		break
+	}
+	if parser.pos == start {
+		return pathSegment{}, parser.errorf("expect member name or *")
+	}
+	name := parser.expr[start:parser.pos]
+	return pathSegment{selectors: []pathSelector{{kind: pathName, name: name}}}, nil
+}
+
+func (parser *pathParser) parseBracket() (pathSegment, error) {
+	parser.pos++ // [
+	var segment pathSegment
+	for {
+		parser.skipWhitespace()
+		selector, err := parser.parseSelector()
+		if err != nil {
+			return pathSegment{}, err
+		}
+		segment.selectors = append(segment.selectors, selector)
+		parser.skipWhitespace()
+		if parser.consume(']') {
+			return segment, nil
+		}
+		if !parser.consume(',') {
+			return pathSegment{}, parser.errorf("expect , or ]")
+		}
+	}
+}
+
+func (parser *pathParser) parseSelector() (pathSelector, error) {
+	switch parser.peek() {
+	case '\'', '"':
+		name, err := parser.parseString()
+		return pathSelector{kind: pathName, name: name}, err
+	case '*':
+		parser.pos++
+		return pathSelector{kind: pathWildcard}, nil
+	case '?':
+		parser.pos++
+		parser.skipWhitespace()
+		expr, err := parser.parseLogicalOr()
+		return pathSelector{kind: pathFilter, filter: expr}, err
+	}
+	return parser.parseIndexOrSlice()
+}
+
+func (parser *pathParser) parseIndexOrSlice() (pathSelector, error) {
+	selector := pathSelector{kind: pathIndex, step: 1}
+	if parser.peek() != ':' {
+		index, err := parser.parseInt()
+		if err != nil {
+			return selector, err
+		}
+		selector.index = index
+		selector.start = index
+		selector.hasStart = true
+		parser.skipWhitespace()
+		if parser.peek() != ':' {
+			return selector, nil
+		}
+	}
+	parser.pos++ // :
+	selector.kind = pathSlice
+	parser.skipWhitespace()
+	if c := parser.peek(); c == '-' || c >= '0' && c <= '9' {
+		end, err := parser.parseInt()
+		if err != nil {
+			return selector, err
+		}
+		selector.end = end
+		selector.hasEnd = true
+		parser.skipWhitespace()
+	}
+	if parser.consume(':') {
+		parser.skipWhitespace()
+		if c := parser.peek(); c == '-' || c >= '0' && c <= '9' {
+			step, err := parser.parseInt()
+			if err != nil {
+				return selector, err
+			}
+			selector.step = step
+		}
+	}
+	return selector, nil
+}
+
+func (parser *pathParser) parseInt() (int, error) {
+	start := parser.pos
+	parser.consume('-')
+	for !parser.eof() && parser.expr[parser.pos] >= '0' && parser.expr[parser.pos] <= '9' {
+		parser.pos++
+	}
+	index, err := strconv.Atoi(parser.expr[start:parser.pos])
+	if err != nil {
+		parser.pos = start
+		return 0, parser.errorf("expect selector")
+	}
+	return index, nil
+}
+
+func (parser *pathParser) parseString() (string, error) {
+	quote := parser.expr[parser.pos]
+	parser.pos++
+	var str []byte
+	for !parser.eof() {
+		c := parser.expr[parser.pos]
+		parser.pos++
+		if c == quote {
+			return string(str), nil
+		}
+		if c != '\\' {
+			str = append(str, c)
+			continue
+		}
+		c = parser.peek()
+		parser.pos++
+		switch c {
+		case '\\', '/', '\'', '"':
+			str = append(str, c)
+		case 'b':
+			str = append(str, '\b')
+		case 'f':
+			str = append(str, '\f')
+		case 'n':
+			str = append(str, '\n')
+		case 'r':
+			str = append(str, '\r')
+		case 't':
+			str = append(str, '\t')
+		case 'u':
+			r, err := parser.parseHexRune()
+			if err != nil {
+				return "", err
+			}
+			if utf16.IsSurrogate(r) && parser.consumeString(`\u`) {
+				low, err := parser.parseHexRune()
+				if err != nil {
+					return "", err
+				}
+				r = utf16.DecodeRune(r, low)
+			}
+			str = append(str, string(r)...)
+		default:
+			return "", parser.errorf("invalid escape in string")
+		}
+	}
+	return "", parser.errorf("unterminated string")
+}
+
+func (parser *pathParser) parseHexRune() (rune, error) {
+	if parser.pos+4 > len(parser.expr) {
+		return 0, parser.errorf("invalid \\u escape in string")
+	}
+	r, err := strconv.ParseUint(parser.expr[parser.pos:parser.pos+4], 16, 32)
+	if err != nil {
+		return 0, parser.errorf("invalid \\u escape in string")
+	}
+	parser.pos += 4
+	return rune(r), nil
+}
+
+func (parser *pathParser) parseLogicalOr() (*pathFilterExpr, error) {
+	left, err := parser.parseLogicalAnd()
+	for err == nil {
+		parser.skipWhitespace()
+		if !parser.consumeString("||") {
+			return left, nil
+		}
+		var right *pathFilterExpr
+		right, err = parser.parseLogicalAnd()
+		left = &pathFilterExpr{op: "||", left: left, right: right}
+	}
+	return nil, err
+}
+
+func (parser *pathParser) parseLogicalAnd() (*pathFilterExpr, error) {
+	left, err := parser.parseBasic()
+	for err == nil {
+		parser.skipWhitespace()
+		if !parser.consumeString("&&") {
+			return left, nil
+		}
+		var right *pathFilterExpr
+		right, err = parser.parseBasic()
+		left = &pathFilterExpr{op: "&&", left: left, right: right}
+	}
+	return nil, err
+}
+
+func (parser *pathParser) parseBasic() (*pathFilterExpr, error) {
+	parser.skipWhitespace()
+	if parser.consume('!') {
+		parser.skipWhitespace()
+		var expr *pathFilterExpr
+		var err error
+		if parser.peek() == '(' {
+			expr, err = parser.parseParen()
+		} else {
+			var operand *pathOperand
+			operand, err = parser.parseQuery()
+			expr = &pathFilterExpr{lhs: operand}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &pathFilterExpr{op: "!", left: expr}, nil
+	}
+	if parser.peek() == '(' {
+		return parser.parseParen()
+	}
+	lhs, err := parser.parseComparable()
+	if err != nil {
+		return nil, err
+	}
+	parser.skipWhitespace()
+	op := parser.parseComparisonOp()
+	if op == "" {
+		if lhs.literal != nil {
+			return nil, parser.errorf("expect comparison operator")
+		}
+		return &pathFilterExpr{lhs: lhs}, nil
+	}
+	parser.skipWhitespace()
+	rhs, err := parser.parseComparable()
+	if err != nil {
+		return nil, err
+	}
+	if !lhs.singular() || !rhs.singular() {
+		return nil, parser.errorf("comparison requires singular query")
+	}
+	return &pathFilterExpr{op: op, lhs: lhs, rhs: rhs}, nil
+}
+
+func (parser *pathParser) parseParen() (*pathFilterExpr, error) {
+	parser.pos++ // (
+	expr, err := parser.parseLogicalOr()
+	if err != nil {
+		return nil, err
+	}
+	parser.skipWhitespace()
+	if !parser.consume(')') {
+		return nil, parser.errorf("expect )")
+	}
+	return expr, nil
+}
+
+func (parser *pathParser) parseComparisonOp() string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if parser.consumeString(op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func (parser *pathParser) parseComparable() (*pathOperand, error) {
+	c := parser.peek()
+	switch {
+	case c == '@' || c == '$':
+		return parser.parseQuery()
+	case c == '\'' || c == '"':
+		str, err := parser.parseString()
+		return &pathOperand{literal: WrapString(str)}, err
+	case c == '-' || c >= '0' && c <= '9':
+		return parser.parseNumber()
+	case parser.consumeString("true"):
+		return &pathOperand{literal: &trueAny{}}, nil
+	case parser.consumeString("false"):
+		return &pathOperand{literal: &falseAny{}}, nil
+	case parser.consumeString("null"):
+		return &pathOperand{literal: &nilAny{}}, nil
+	}
+	return nil, parser.errorf("expect query or literal")
+}
+
+func (parser *pathParser) parseQuery() (*pathOperand, error) {
+	operand := &pathOperand{}
+	switch {
+	case parser.consume('$'):
+		operand.root = true
+		parser.needsRoot = true
+	case !parser.consume('@'):
+		return nil, parser.errorf("expect @ or $")
+	}
+	segments, err := parser.parseSegments()
+	operand.segments = segments
+	return operand, err
+}
+
+func (parser *pathParser) parseNumber() (*pathOperand, error) {
+	start := parser.pos
+	for !parser.eof() {
+		c := parser.expr[parser.pos]
+		if c >= '0' && c <= '9' || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E' {
+			parser.pos++
+			continue
+		}
+		break
+	}
+	val, err := strconv.ParseFloat(parser.expr[start:parser.pos], 64)
+	if err != nil {
+		parser.pos = start
+		return nil, parser.errorf("invalid number")
+	}
+	return &pathOperand{literal: WrapFloat64(val)}, nil
+}
+
+// singular tells if the operand selects at most one value, literal is always singular
+func (operand *pathOperand) singular() bool {
+	for _, segment := range operand.segments {
+		if segment.descendant || len(segment.selectors) != 1 {
+			return false
+		}
+		switch segment.selectors[0].kind {
+		case pathName, pathIndex:
+		default:
+			return false
+		}
+	}
+	return true
+}
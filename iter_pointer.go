@@ -0,0 +1,138 @@
+package jsoniter
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// ParsePointer splits a RFC 6901 JSON Pointer into its unescaped reference tokens.
+// The empty pointer "" references the whole document and has no token.
+func ParsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("json pointer %q must start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		if strings.IndexByte(token, '~') == -1 {
+			continue
+		}
+		unescaped := make([]byte, 0, len(token))
+		for j := 0; j < len(token); j++ {
+			c := token[j]
+			if c != '~' {
+				unescaped = append(unescaped, c)
+				continue
+			}
+			if j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1') {
+				return nil, fmt.Errorf("json pointer %q has invalid escape in %q", pointer, token)
+			}
+			j++
+			if token[j] == '0' {
+				unescaped = append(unescaped, '~')
+			} else {
+				unescaped = append(unescaped, '/')
+			}
+		}
+		tokens[i] = string(unescaped)
+	}
+	return tokens, nil
+}
+
+// pointerIndex converts reference token to array index.
+// "-" references the element after the last one, and is reported as -1.
+func pointerIndex(token string) (int, bool) {
+	if token == "-" {
+		return -1, true
+	}
+	if token == "" || len(token) > 1 && token[0] == '0' {
+		return 0, false
+	}
+	index := 0
+	for i := 0; i < len(token); i++ {
+		c := token[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		index = index*10 + int(c-'0')
+		if index > math.MaxInt32 {
+			return 0, false
+		}
+	}
+	return index, true
+}
+
+// SeekPointer positions the iterator at the value referenced by the RFC 6901 JSON Pointer,
+// so that the next read returns that value. Values not on the path are skipped, not decoded.
+// Like Get, the iterator is then reset to the bytes of that value, and the rest of the input is not read.
+// Returns false if the value does not exist, the iterator is then left somewhere inside the document.
+func (iter *Iterator) SeekPointer(pointer string) bool {
+	tokens, err := ParsePointer(pointer)
+	if err != nil {
+		iter.ReportError("SeekPointer", err.Error())
+		return false
+	}
+	for _, token := range tokens {
+		var key interface{} = token
+		switch iter.WhatIsNext() {
+		case ObjectValue:
+		case ArrayValue:
+			index, ok := pointerIndex(token)
+			if !ok || index < 0 {
+				return false
+			}
+			key = index
+		default:
+			return false
+		}
+		if !locatePathKey(iter, key) {
+			return false
+		}
+	}
+	return iter.Error == nil || iter.Error == io.EOF
+}
+
+func getPointer(any Any, pointer string) Any {
+	tokens, err := ParsePointer(pointer)
+	if err != nil {
+		return &invalidAny{baseAny{}, err}
+	}
+	for _, token := range tokens {
+		switch any.ValueType() {
+		case ObjectValue:
+			any = any.Get(token)
+		case ArrayValue:
+			index, ok := pointerIndex(token)
+			if !ok || index < 0 {
+				return &invalidAny{baseAny{}, fmt.Errorf("%s not found", pointer)}
+			}
+			any = any.Get(index)
+		default:
+			return &invalidAny{baseAny{}, fmt.Errorf("%s not found", pointer)}
+		}
+		if any.ValueType() == InvalidValue {
+			return &invalidAny{baseAny{}, fmt.Errorf("%s not found", pointer)}
+		}
+	}
+	return any
+}
+
+// escapePointerToken escapes ~ and / so that the token can be used in JSON Pointer
+func escapePointerToken(token string) string {
+	if strings.IndexAny(token, "~/") == -1 {
+		return token
+	}
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+func pointerOf(tokens []string) string {
+	pointer := ""
+	for _, token := range tokens {
+		pointer += "/" + escapePointerToken(token)
+	}
+	return pointer
+}
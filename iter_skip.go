@@ -25,13 +25,17 @@ func (iter *Iterator) ReadBool() (ret bool) {
 		iter.skipFourBytes('a', 'l', 's', 'e')
 		return false
 	}
-	iter.ReportError("ReadBool", "expect t or f, but found "+string([]byte{c}))
+	iter.reportUnexpectedValue("ReadBool", "t or f", c, boolType)
 	return
 }
 
 // SkipAndReturnBytes skip next JSON element, and return its content as []byte.
 // The []byte can be kept, it is a copy of data.
+// With Config.AllowJSON5, the content is rewritten as standard JSON.
 func (iter *Iterator) SkipAndReturnBytes() []byte {
+	if iter.cfg.allowJSON5 {
+		return iter.appendJSON5AsJSON(make([]byte, 0, 32))
+	}
 	iter.startCapture(iter.head)
 	iter.Skip()
 	return iter.stopCapture()
@@ -40,6 +44,9 @@ func (iter *Iterator) SkipAndReturnBytes() []byte {
 // SkipAndAppendBytes skips next JSON element and appends its content to
 // buffer, returning the result.
 func (iter *Iterator) SkipAndAppendBytes(buf []byte) []byte {
+	if iter.cfg.allowJSON5 {
+		return iter.appendJSON5AsJSON(buf)
+	}
 	iter.startCaptureTo(buf, iter.head)
 	iter.Skip()
 	return iter.stopCapture()
@@ -90,6 +97,9 @@ func (iter *Iterator) Skip() {
 	case '{':
 		iter.skipObject()
 	default:
+		if iter.cfg.allowJSON5 && iter.skipJSON5(c) {
+			return
+		}
 		iter.ReportError("Skip", fmt.Sprintf("do not know how to skip: %v", c))
 		return
 	}
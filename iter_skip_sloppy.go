@@ -2,103 +2,110 @@
 
 package jsoniter
 
+import (
+	"bytes"
+	"io"
+)
+
 // sloppy but faster implementation, do not validate the input json
 
 func (iter *Iterator) skipNumber() {
+	length := 1 // the first char has been consumed
 	for {
 		for i := iter.head; i < iter.tail; i++ {
 			c := iter.buf[i]
 			switch c {
-			case ' ', '\n', '\r', '\t', ',', '}', ']':
+			case ' ', '\n', '\r', '\t', ',', '}', ']', '/':
+				iter.checkNumberLength(length + i - iter.head)
 				iter.head = i
 				return
 			}
 		}
-		if !iter.loadMore() {
+		length += iter.tail - iter.head
+		if !iter.checkNumberLength(length) || !iter.loadMore() {
 			return
 		}
 	}
 }
 
+// skipByValues tells if the containers are skipped value by value instead of with skipContainer,
+// as the structural index does not know comments and single quoted strings, and does not check the limits of the values
+func (iter *Iterator) skipByValues() bool {
+	cfg := iter.cfg
+	return cfg.allowJSON5 || cfg.maxElements > 0 || cfg.maxStringLength > 0 || cfg.maxNumberLength > 0
+}
+
 func (iter *Iterator) skipArray() {
-	level := 1
-	if !iter.incrementDepth() {
+	if iter.skipByValues() {
+		iter.unreadByte()
+		iter.ReadArrayCB(func(iter *Iterator) bool {
+			iter.Skip()
+			return true
+		})
 		return
 	}
-	for {
-		for i := iter.head; i < iter.tail; i++ {
-			switch iter.buf[i] {
-			case '"': // If inside string, skip it
-				iter.head = i + 1
-				iter.skipString()
-				i = iter.head - 1 // it will be i++ soon
-			case '[': // If open symbol, increase level
-				level++
-				if !iter.incrementDepth() {
-					return
-				}
-			case ']': // If close symbol, increase level
-				level--
-				if !iter.decrementDepth() {
-					return
-				}
+	iter.skipContainer("skipObject", "incomplete array")
+}
 
-				// If we have returned to the original level, we're done
-				if level == 0 {
-					iter.head = i + 1
-					return
-				}
-			}
-		}
-		if !iter.loadMore() {
-			iter.ReportError("skipObject", "incomplete array")
-			return
-		}
+func (iter *Iterator) skipObject() {
+	if iter.skipByValues() {
+		iter.unreadByte()
+		iter.readObjectFieldsCB(func(iter *Iterator, field []byte) bool {
+			iter.Skip()
+			return true
+		})
+		return
 	}
+	iter.skipContainer("skipObject", "incomplete object")
 }
 
-func (iter *Iterator) skipObject() {
+// skipContainer skips to the bracket closing the already consumed { or [,
+// jumping from bracket to bracket outside strings with the structural index,
+// the content is not read, so only MaxDepth is checked
+func (iter *Iterator) skipContainer(operation string, msg string) {
 	level := 1
 	if !iter.incrementDepth() {
 		return
 	}
-
+	scanner := structuralScanner{}
 	for {
-		for i := iter.head; i < iter.tail; i++ {
-			switch iter.buf[i] {
-			case '"': // If inside string, skip it
-				iter.head = i + 1
-				iter.skipString()
-				i = iter.head - 1 // it will be i++ soon
-			case '{': // If open symbol, increase level
+		head := iter.head
+		scanner.indexBrackets(iter.buf[head:iter.tail], func(i int, open bool) bool {
+			if open {
 				level++
-				if !iter.incrementDepth() {
-					return
-				}
-			case '}': // If close symbol, increase level
-				level--
-				if !iter.decrementDepth() {
-					return
-				}
-
-				// If we have returned to the original level, we're done
-				if level == 0 {
-					iter.head = i + 1
-					return
-				}
+				return iter.incrementDepth()
+			}
+			level--
+			if !iter.decrementDepth() {
+				return false
 			}
+			// If we have returned to the original level, we're done
+			if level == 0 {
+				iter.head = head + i + 1
+				return false
+			}
+			return true
+		})
+		if level == 0 || (iter.Error != nil && iter.Error != io.EOF) {
+			return
 		}
+		iter.head = iter.tail
 		if !iter.loadMore() {
-			iter.ReportError("skipObject", "incomplete object")
+			iter.ReportError(operation, msg)
 			return
 		}
 	}
 }
 
 func (iter *Iterator) skipString() {
+	length := 0
 	for {
 		end, escaped := iter.findStringEnd()
 		if end == -1 {
+			length += iter.tail - iter.head
+			if !iter.checkStringLength(length) {
+				return
+			}
 			if !iter.loadMore() {
 				iter.ReportError("skipString", "incomplete string")
 				return
@@ -107,57 +114,24 @@ func (iter *Iterator) skipString() {
 				iter.head = 1 // skip the first char as last char read is \
 			}
 		} else {
+			// escape sequences are counted as is, the length is an upper bound of the string read
+			iter.checkStringLength(length + end - iter.head - 1)
 			iter.head = end
 			return
 		}
 	}
 }
 
-// adapted from: https://github.com/buger/jsonparser/blob/master/parser.go
-// Tries to find the end of string
+// Tries to find the end of string with the structural index.
 // Support if string contains escaped quote symbols.
+// Returns the index after the closing quote, and whether there is a backslash before it,
+// or -1, and whether the buffer ends with an unpaired backslash.
 func (iter *Iterator) findStringEnd() (int, bool) {
-	escaped := false
-	for i := iter.head; i < iter.tail; i++ {
-		c := iter.buf[i]
-		if c == '"' {
-			if !escaped {
-				return i + 1, false
-			}
-			j := i - 1
-			for {
-				if j < iter.head || iter.buf[j] != '\\' {
-					// even number of backslashes
-					// either end of buffer, or " found
-					return i + 1, true
-				}
-				j--
-				if j < iter.head || iter.buf[j] != '\\' {
-					// odd number of backslashes
-					// it is \" or \\\"
-					break
-				}
-				j--
-			}
-		} else if c == '\\' {
-			escaped = true
-		}
-	}
-	j := iter.tail - 1
-	for {
-		if j < iter.head || iter.buf[j] != '\\' {
-			// even number of backslashes
-			// either end of buffer, or " found
-			return -1, false // do not end with \
-		}
-		j--
-		if j < iter.head || iter.buf[j] != '\\' {
-			// odd number of backslashes
-			// it is \" or \\\"
-			break
-		}
-		j--
-
+	scanner := structuralScanner{}
+	end := scanner.indexStringEnd(iter.buf[iter.head:iter.tail])
+	if end == -1 {
+		return -1, scanner.escapeCarry == 1
 	}
-	return -1, true // end with \
+	end += iter.head
+	return end + 1, bytes.IndexByte(iter.buf[iter.head:end], '\\') != -1
 }
@@ -5,6 +5,7 @@ package jsoniter
 import (
 	"fmt"
 	"io"
+	"strconv"
 )
 
 func (iter *Iterator) skipNumber() {
@@ -14,9 +15,9 @@ func (iter *Iterator) skipNumber() {
 			return
 		}
 		iter.ReadFloat64()
-		if iter.Error != nil && iter.Error != io.EOF {
+		if numErr, ok := iter.Error.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			// valid number out of float64 range, such as 1e400
 			iter.Error = nil
-			iter.ReadBigFloat()
 		}
 	}
 }
@@ -49,6 +50,9 @@ func (iter *Iterator) trySkipNumber() bool {
 				if iter.head == i {
 					return false // if - without following digits
 				}
+				if !iter.checkNumberLength(i - iter.head + 1) {
+					return true // already failed
+				}
 				iter.head = i
 				return true // must be valid
 			}
@@ -66,25 +70,28 @@ func (iter *Iterator) skipString() {
 }
 
 func (iter *Iterator) trySkipString() bool {
-	for i := iter.head; i < iter.tail; i++ {
-		c := iter.buf[i]
-		if c == '"' {
-			iter.head = i + 1
-			return true // valid
-		} else if c == '\\' {
-			return false
-		} else if c < ' ' {
-			iter.ReportError("trySkipString",
-				fmt.Sprintf(`invalid control character found: %d`, c))
+	i := indexStringStop(iter.buf[iter.head:iter.tail])
+	if i == -1 {
+		return false
+	}
+	c := iter.buf[iter.head+i]
+	if c == '"' {
+		if !iter.checkStringLength(i) {
 			return true // already failed
 		}
+		iter.head += i + 1
+		return true // valid
+	} else if c == '\\' {
+		return false
 	}
-	return false
+	iter.ReportError("trySkipString",
+		fmt.Sprintf(`invalid control character found: %d`, c))
+	return true // already failed
 }
 
 func (iter *Iterator) skipObject() {
 	iter.unreadByte()
-	iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+	iter.readObjectFieldsCB(func(iter *Iterator, field []byte) bool {
 		iter.Skip()
 		return true
 	})
@@ -12,6 +12,9 @@ func (iter *Iterator) ReadString() (ret string) {
 		for i := iter.head; i < iter.tail; i++ {
 			c := iter.buf[i]
 			if c == '"' {
+				if !iter.checkStringLength(i - iter.head) {
+					return
+				}
 				ret = string(iter.buf[iter.head:i])
 				iter.head = i + 1
 				return ret
@@ -27,8 +30,10 @@ func (iter *Iterator) ReadString() (ret string) {
 	} else if c == 'n' {
 		iter.skipThreeBytes('u', 'l', 'l')
 		return ""
+	} else if c == '\'' && iter.cfg.allowJSON5 {
+		return iter.readSingleQuotedString()
 	}
-	iter.ReportError("ReadString", `expects " or n, but found `+string([]byte{c}))
+	iter.reportUnexpectedValue("ReadString", `" or n`, c, stringType)
 	return
 }
 
@@ -46,6 +51,9 @@ func (iter *Iterator) readStringSlowPath() (ret string) {
 		} else {
 			str = append(str, c)
 		}
+		if !iter.checkStringLength(len(str)) {
+			return
+		}
 	}
 	iter.ReportError("readStringSlowPath", "unexpected end of input")
 	return
@@ -104,6 +112,10 @@ func (iter *Iterator) readEscapedChar(c byte, str []byte) []byte {
 	case 't':
 		str = append(str, '\t')
 	default:
+		if c == '\'' && iter.cfg.allowJSON5 {
+			str = append(str, '\'')
+			break
+		}
 		iter.ReportError("readEscapedChar",
 			`invalid escape char after \`)
 		return nil
@@ -116,15 +128,16 @@ func (iter *Iterator) readEscapedChar(c byte, str []byte) []byte {
 func (iter *Iterator) ReadStringAsSlice() (ret []byte) {
 	c := iter.nextToken()
 	if c == '"' {
-		for i := iter.head; i < iter.tail; i++ {
-			// require ascii string and no escape
-			// for: field name, base64, number
-			if iter.buf[i] == '"' {
-				// fast path: reuse the underlying buffer
-				ret = iter.buf[iter.head:i]
-				iter.head = i + 1
-				return ret
+		// require ascii string and no escape
+		// for: field name, base64, number
+		if i := indexQuote(iter.buf[iter.head:iter.tail]); i != -1 {
+			if !iter.checkStringLength(i) {
+				return
 			}
+			// fast path: reuse the underlying buffer
+			ret = iter.buf[iter.head : iter.head+i]
+			iter.head += i + 1
+			return ret
 		}
 		readLen := iter.tail - iter.head
 		copied := make([]byte, readLen, readLen*2)
@@ -136,10 +149,16 @@ func (iter *Iterator) ReadStringAsSlice() (ret []byte) {
 				return copied
 			}
 			copied = append(copied, c)
+			if !iter.checkStringLength(len(copied)) {
+				return
+			}
 		}
 		return copied
 	}
-	iter.ReportError("ReadStringAsSlice", `expects " or n, but found `+string([]byte{c}))
+	if c == '\'' && iter.cfg.allowJSON5 {
+		return []byte(iter.readSingleQuotedString())
+	}
+	iter.reportUnexpectedValue("ReadStringAsSlice", `" or n`, c, stringType)
 	return
 }
 
@@ -156,7 +175,7 @@ func (iter *Iterator) readU4() (ret rune) {
 		} else if c >= 'A' && c <= 'F' {
 			ret = ret*16 + rune(c-'A'+10)
 		} else {
-			iter.ReportError("readU4", "expects 0~9 or a~f, but found "+string([]byte{c}))
+			iter.reportUnexpected("readU4", "0~9 or a~f", c)
 			return
 		}
 	}
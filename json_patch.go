@@ -0,0 +1,478 @@
+package jsoniter
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// PatchError is returned by ApplyPatch when an operation of the RFC 6902 JSON Patch can not be applied
+type PatchError struct {
+	Index int    // index of the operation in the patch
+	Op    string // add, remove, replace, move, copy or test
+	Path  string
+	Msg   string
+}
+
+func (err *PatchError) Error() string {
+	return fmt.Sprintf("json patch operation %d (%s %q): %s", err.Index, err.Op, err.Path, err.Msg)
+}
+
+// ApplyPatch applies the RFC 6902 JSON Patch ops to doc, and returns the patched document.
+// Values not touched by the patch are copied byte for byte, numbers are never converted to float64.
+// The operations are applied all or nothing, the first failing one is reported as *PatchError.
+func ApplyPatch(doc, ops []byte) ([]byte, error) {
+	cfg := ConfigDefault.(*frozenConfig)
+	if err := cfg.validateDocument("ApplyPatch", doc); err != nil {
+		return nil, err
+	}
+	operations, err := cfg.readPatchOperations(ops)
+	if err != nil {
+		return nil, err
+	}
+	root := &patchNode{raw: trimSpace(doc)}
+	for i, operation := range operations {
+		if msg := cfg.applyPatchOperation(root, operation); msg != "" {
+			return nil, &PatchError{Index: i, Op: operation.op, Path: operation.path, Msg: msg}
+		}
+	}
+	stream := cfg.BorrowStream(nil)
+	defer cfg.ReturnStream(stream)
+	root.writeTo(stream)
+	return append([]byte(nil), stream.Buffer()...), nil
+}
+
+// DiffPatch returns the RFC 6902 JSON Patch turning a into b.
+// Objects are compared member by member, arrays element by element.
+func DiffPatch(a, b []byte) ([]byte, error) {
+	cfg := ConfigDefault.(*frozenConfig)
+	if err := cfg.validateDocument("DiffPatch", a); err != nil {
+		return nil, err
+	}
+	if err := cfg.validateDocument("DiffPatch", b); err != nil {
+		return nil, err
+	}
+	stream := cfg.BorrowStream(nil)
+	defer cfg.ReturnStream(stream)
+	diff := &patchDiff{cfg: cfg, stream: stream}
+	stream.WriteArrayStart()
+	diff.diff("", trimSpace(a), trimSpace(b))
+	stream.WriteArrayEnd()
+	return append([]byte(nil), stream.Buffer()...), nil
+}
+
+type patchOperation struct {
+	op    string
+	path  string
+	from  string
+	value []byte
+}
+
+func (cfg *frozenConfig) readPatchOperations(ops []byte) ([]patchOperation, error) {
+	if err := cfg.validateDocument("ApplyPatch", ops); err != nil {
+		return nil, err
+	}
+	iter := cfg.BorrowIterator(ops)
+	defer cfg.ReturnIterator(iter)
+	operations := []patchOperation{}
+	var missing string
+	iter.ReadArrayCB(func(iter *Iterator) bool {
+		operation := patchOperation{}
+		var hasPath, hasFrom bool
+		iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+			switch field {
+			case "op":
+				operation.op = iter.ReadString()
+			case "path":
+				operation.path = iter.ReadString()
+				hasPath = true
+			case "from":
+				operation.from = iter.ReadString()
+				hasFrom = true
+			case "value":
+				operation.value = trimSpace(iter.SkipAndReturnBytes())
+			default:
+				iter.Skip()
+			}
+			return true
+		})
+		switch {
+		case !hasPath:
+			missing = "path"
+		case operation.op == "add" || operation.op == "replace" || operation.op == "test":
+			if operation.value == nil {
+				missing = "value"
+			}
+		case operation.op == "move" || operation.op == "copy":
+			if !hasFrom {
+				missing = "from"
+			}
+		case operation.op != "remove":
+			iter.ReportError("ApplyPatch", fmt.Sprintf("operation %d has invalid op %q", len(operations), operation.op))
+			return false
+		}
+		if missing != "" {
+			iter.ReportError("ApplyPatch", fmt.Sprintf("operation %d has no %s", len(operations), missing))
+			return false
+		}
+		operations = append(operations, operation)
+		return true
+	})
+	if iter.Error != nil && iter.Error != io.EOF {
+		return nil, iter.Error
+	}
+	return operations, nil
+}
+
+// applyPatchOperation returns the reason if the operation can not be applied
+func (cfg *frozenConfig) applyPatchOperation(root *patchNode, operation patchOperation) string {
+	path, err := ParsePointer(operation.path)
+	if err != nil {
+		return err.Error()
+	}
+	switch operation.op {
+	case "add":
+		return cfg.addPatchNode(root, path, &patchNode{raw: operation.value})
+	case "remove":
+		_, msg := cfg.removePatchNode(root, path)
+		return msg
+	case "replace":
+		target, msg := cfg.locatePatchNode(root, path)
+		if msg != "" {
+			return msg
+		}
+		*target = patchNode{raw: operation.value}
+		return ""
+	case "test":
+		target, msg := cfg.locatePatchNode(root, path)
+		if msg != "" {
+			return msg
+		}
+		if !cfg.equalValues(target.bytes(cfg), operation.value) {
+			return "test failed, value is " + string(target.bytes(cfg))
+		}
+		return ""
+	}
+	from, err := ParsePointer(operation.from)
+	if err != nil {
+		return err.Error()
+	}
+	var value *patchNode
+	var msg string
+	if operation.op == "move" {
+		if operation.path != operation.from && strings.HasPrefix(operation.path, operation.from+"/") {
+			return "can not move " + operation.from + " into itself"
+		}
+		value, msg = cfg.removePatchNode(root, from)
+	} else {
+		value, msg = cfg.locatePatchNode(root, from)
+		if value != nil {
+			value = &patchNode{raw: value.bytes(cfg)}
+		}
+	}
+	if msg != "" {
+		return msg
+	}
+	return cfg.addPatchNode(root, path, value)
+}
+
+// patchNode is a value of the document being patched.
+// It keeps the raw bytes until it is modified, and is then expanded to members or elements.
+type patchNode struct {
+	raw      []byte
+	kind     ValueType
+	fields   []string
+	members  []*patchNode
+	index    map[string]int // index of each field in fields, for objects
+	elements []*patchNode
+}
+
+func (node *patchNode) expand(cfg *frozenConfig) ValueType {
+	if node.raw == nil {
+		return node.kind
+	}
+	kind := valueTypes[node.raw[0]]
+	if kind != ObjectValue && kind != ArrayValue {
+		return kind
+	}
+	iter := cfg.BorrowIterator(node.raw)
+	defer cfg.ReturnIterator(iter)
+	if kind == ObjectValue {
+		node.index = map[string]int{}
+		iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+			node.setMember(field, &patchNode{raw: trimSpace(iter.SkipAndReturnBytes())})
+			return true
+		})
+	} else {
+		node.elements = []*patchNode{}
+		iter.ReadArrayCB(func(iter *Iterator) bool {
+			node.elements = append(node.elements, &patchNode{raw: trimSpace(iter.SkipAndReturnBytes())})
+			return true
+		})
+	}
+	node.raw = nil
+	node.kind = kind
+	return kind
+}
+
+func (node *patchNode) fieldIndex(field string) int {
+	if i, found := node.index[field]; found {
+		return i
+	}
+	return -1
+}
+
+// setMember replaces the member of the object, or adds it after the others
+func (node *patchNode) setMember(field string, member *patchNode) {
+	if i, found := node.index[field]; found {
+		node.members[i] = member
+		return
+	}
+	node.index[field] = len(node.fields)
+	node.fields = append(node.fields, field)
+	node.members = append(node.members, member)
+}
+
+func (node *patchNode) removeMember(index int) {
+	delete(node.index, node.fields[index])
+	node.fields = append(node.fields[:index], node.fields[index+1:]...)
+	node.members = append(node.members[:index], node.members[index+1:]...)
+	for i := index; i < len(node.fields); i++ {
+		node.index[node.fields[i]] = i
+	}
+}
+
+func (node *patchNode) writeTo(stream *Stream) {
+	if node.raw != nil {
+		stream.buf = append(stream.buf, node.raw...)
+		return
+	}
+	if node.kind == ObjectValue {
+		stream.WriteObjectStart()
+		for i, member := range node.members {
+			if i != 0 {
+				stream.WriteMore()
+			}
+			stream.WriteObjectField(node.fields[i])
+			member.writeTo(stream)
+		}
+		stream.WriteObjectEnd()
+		return
+	}
+	stream.WriteArrayStart()
+	for i, element := range node.elements {
+		if i != 0 {
+			stream.WriteMore()
+		}
+		element.writeTo(stream)
+	}
+	stream.WriteArrayEnd()
+}
+
+func (node *patchNode) bytes(cfg *frozenConfig) []byte {
+	if node.raw != nil {
+		return node.raw
+	}
+	stream := cfg.BorrowStream(nil)
+	defer cfg.ReturnStream(stream)
+	node.writeTo(stream)
+	return append([]byte(nil), stream.Buffer()...)
+}
+
+func (cfg *frozenConfig) locatePatchNode(root *patchNode, path []string) (*patchNode, string) {
+	node := root
+	for i, token := range path {
+		switch node.expand(cfg) {
+		case ObjectValue:
+			index := node.fieldIndex(token)
+			if index == -1 {
+				return nil, "path " + pointerOf(path[:i+1]) + " not found"
+			}
+			node = node.members[index]
+		case ArrayValue:
+			index, ok := pointerIndex(token)
+			if !ok || index < 0 || index >= len(node.elements) {
+				return nil, "path " + pointerOf(path[:i+1]) + " not found"
+			}
+			node = node.elements[index]
+		default:
+			return nil, "path " + pointerOf(path[:i+1]) + " not found"
+		}
+	}
+	return node, ""
+}
+
+func (cfg *frozenConfig) addPatchNode(root *patchNode, path []string, value *patchNode) string {
+	if len(path) == 0 {
+		*root = *value
+		return ""
+	}
+	parent, msg := cfg.locatePatchNode(root, path[:len(path)-1])
+	if msg != "" {
+		return msg
+	}
+	token := path[len(path)-1]
+	switch parent.expand(cfg) {
+	case ObjectValue:
+		parent.setMember(token, value)
+		return ""
+	case ArrayValue:
+		index, ok := pointerIndex(token)
+		if index == -1 {
+			index = len(parent.elements)
+		}
+		if !ok || index > len(parent.elements) {
+			return "index " + token + " is out of bounds"
+		}
+		parent.elements = append(parent.elements, nil)
+		copy(parent.elements[index+1:], parent.elements[index:])
+		parent.elements[index] = value
+		return ""
+	}
+	return "path " + pointerOf(path[:len(path)-1]) + " is not a container"
+}
+
+func (cfg *frozenConfig) removePatchNode(root *patchNode, path []string) (*patchNode, string) {
+	if len(path) == 0 {
+		return nil, "can not remove the whole document"
+	}
+	target, msg := cfg.locatePatchNode(root, path)
+	if msg != "" {
+		return nil, msg
+	}
+	parent, _ := cfg.locatePatchNode(root, path[:len(path)-1])
+	token := path[len(path)-1]
+	if parent.kind == ObjectValue {
+		parent.removeMember(parent.fieldIndex(token))
+	} else {
+		index, _ := pointerIndex(token)
+		parent.elements = append(parent.elements[:index], parent.elements[index+1:]...)
+	}
+	return target, ""
+}
+
+// equalValues compares two valid JSON values by RFC 6902 rules, numbers are compared exactly
+func (cfg *frozenConfig) equalValues(a []byte, b []byte) bool {
+	kind := valueTypes[a[0]]
+	if kind != valueTypes[b[0]] {
+		return false
+	}
+	iterA := cfg.BorrowIterator(a)
+	defer cfg.ReturnIterator(iterA)
+	iterB := cfg.BorrowIterator(b)
+	defer cfg.ReturnIterator(iterB)
+	switch kind {
+	case NumberValue:
+		ratA, okA := new(big.Rat).SetString(string(iterA.ReadNumber()))
+		ratB, okB := new(big.Rat).SetString(string(iterB.ReadNumber()))
+		return okA && okB && ratA.Cmp(ratB) == 0
+	case StringValue:
+		return iterA.ReadString() == iterB.ReadString()
+	case BoolValue:
+		return iterA.ReadBool() == iterB.ReadBool()
+	case NilValue:
+		return true
+	case ArrayValue:
+		elementsA := (&patchNode{raw: a}).expandedElements(cfg)
+		elementsB := (&patchNode{raw: b}).expandedElements(cfg)
+		if len(elementsA) != len(elementsB) {
+			return false
+		}
+		for i := range elementsA {
+			if !cfg.equalValues(elementsA[i].raw, elementsB[i].raw) {
+				return false
+			}
+		}
+		return true
+	}
+	objectA := &patchNode{raw: a}
+	objectA.expand(cfg)
+	objectB := &patchNode{raw: b}
+	objectB.expand(cfg)
+	if len(objectA.fields) != len(objectB.fields) {
+		return false
+	}
+	for i, field := range objectA.fields {
+		index := objectB.fieldIndex(field)
+		if index == -1 || !cfg.equalValues(objectA.members[i].raw, objectB.members[index].raw) {
+			return false
+		}
+	}
+	return true
+}
+
+func (node *patchNode) expandedElements(cfg *frozenConfig) []*patchNode {
+	node.expand(cfg)
+	return node.elements
+}
+
+type patchDiff struct {
+	cfg    *frozenConfig
+	stream *Stream
+	count  int
+}
+
+func (diff *patchDiff) diff(path string, a []byte, b []byte) {
+	cfg := diff.cfg
+	if cfg.equalValues(a, b) {
+		return
+	}
+	nodeA := &patchNode{raw: a}
+	nodeB := &patchNode{raw: b}
+	kind := nodeA.expand(cfg)
+	if kind != nodeB.expand(cfg) || kind != ObjectValue && kind != ArrayValue {
+		diff.write("replace", path, b)
+		return
+	}
+	if kind == ObjectValue {
+		for i, field := range nodeA.fields {
+			index := nodeB.fieldIndex(field)
+			if index == -1 {
+				diff.write("remove", path+"/"+escapePointerToken(field), nil)
+				continue
+			}
+			diff.diff(path+"/"+escapePointerToken(field), nodeA.members[i].raw, nodeB.members[index].raw)
+		}
+		for i, field := range nodeB.fields {
+			if nodeA.fieldIndex(field) == -1 {
+				diff.write("add", path+"/"+escapePointerToken(field), nodeB.members[i].raw)
+			}
+		}
+		return
+	}
+	common := len(nodeA.elements)
+	if len(nodeB.elements) < common {
+		common = len(nodeB.elements)
+	}
+	for i := 0; i < common; i++ {
+		diff.diff(path+"/"+strconv.Itoa(i), nodeA.elements[i].raw, nodeB.elements[i].raw)
+	}
+	for i := len(nodeA.elements) - 1; i >= common; i-- {
+		diff.write("remove", path+"/"+strconv.Itoa(i), nil)
+	}
+	for i := common; i < len(nodeB.elements); i++ {
+		diff.write("add", path+"/"+strconv.Itoa(i), nodeB.elements[i].raw)
+	}
+}
+
+func (diff *patchDiff) write(op string, path string, value []byte) {
+	stream := diff.stream
+	if diff.count != 0 {
+		stream.WriteMore()
+	}
+	diff.count++
+	stream.WriteObjectStart()
+	stream.WriteObjectField("op")
+	stream.WriteString(op)
+	stream.WriteMore()
+	stream.WriteObjectField("path")
+	stream.WriteString(path)
+	if value != nil {
+		stream.WriteMore()
+		stream.WriteObjectField("value")
+		stream.buf = append(stream.buf, value...)
+	}
+	stream.WriteObjectEnd()
+}
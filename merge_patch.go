@@ -0,0 +1,422 @@
+package jsoniter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// MergePatch applies the RFC 7396 JSON Merge Patch to original, and returns the patched document.
+// Values not touched by the patch are copied byte for byte, and members keep their original order.
+func MergePatch(original, patch []byte) ([]byte, error) {
+	return ConfigDefault.(*frozenConfig).mergePatch(original, patch)
+}
+
+// mergePatch is MergePatch parsing the documents with cfg
+func (cfg *frozenConfig) mergePatch(original, patch []byte) ([]byte, error) {
+	if err := cfg.validateDocument("MergePatch", original); err != nil {
+		return nil, err
+	}
+	if err := cfg.validateDocument("MergePatch", patch); err != nil {
+		return nil, err
+	}
+	stream := cfg.BorrowStream(nil)
+	defer cfg.ReturnStream(stream)
+	cfg.writeMergePatch(stream, trimSpace(original), trimSpace(patch))
+	return append([]byte(nil), stream.Buffer()...), nil
+}
+
+// CreateMergePatch returns the RFC 7396 JSON Merge Patch turning original into modified.
+// As null removes a member in merge patch, null inside modified objects can not be represented.
+func CreateMergePatch(original, modified []byte) ([]byte, error) {
+	cfg := ConfigDefault.(*frozenConfig)
+	if err := cfg.validateDocument("CreateMergePatch", original); err != nil {
+		return nil, err
+	}
+	if err := cfg.validateDocument("CreateMergePatch", modified); err != nil {
+		return nil, err
+	}
+	stream := cfg.BorrowStream(nil)
+	defer cfg.ReturnStream(stream)
+	cfg.writeCreateMergePatch(stream, trimSpace(original), trimSpace(modified))
+	return append([]byte(nil), stream.Buffer()...), nil
+}
+
+type mergePatchMember struct {
+	field string
+	value []byte
+}
+
+func (cfg *frozenConfig) validateDocument(operation string, data []byte) error {
+	iter := cfg.BorrowIterator(data)
+	defer cfg.ReturnIterator(iter)
+	iter.Skip()
+	if iter.Error != nil && iter.Error != io.EOF {
+		return iter.Error
+	}
+	if c := iter.nextToken(); c != 0 {
+		iter.ReportError(operation, "there are bytes left after value")
+		return iter.Error
+	}
+	return nil
+}
+
+func (cfg *frozenConfig) readMergePatchMembers(data []byte) []mergePatchMember {
+	if valueTypes[data[0]] != ObjectValue {
+		return nil
+	}
+	members := []mergePatchMember{}
+	iter := cfg.BorrowIterator(data)
+	defer cfg.ReturnIterator(iter)
+	iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+		members = append(members, mergePatchMember{field, trimSpace(iter.SkipAndReturnBytes())})
+		return true
+	})
+	return members
+}
+
+// writeMergePatch writes the result of merging patch into target, target is nil if missing
+func (cfg *frozenConfig) writeMergePatch(stream *Stream, target []byte, patch []byte) {
+	patchMembers := cfg.readMergePatchMembers(patch)
+	if patchMembers == nil {
+		stream.buf = append(stream.buf, patch...)
+		return
+	}
+	// the last member wins if a field is duplicated
+	patchIndex := make(map[string]int, len(patchMembers))
+	for i, member := range patchMembers {
+		patchIndex[member.field] = i
+	}
+	var targetMembers []mergePatchMember
+	if target != nil {
+		targetMembers = cfg.readMergePatchMembers(target)
+	}
+	targetFields := make(map[string]bool, len(targetMembers))
+	stream.WriteObjectStart()
+	more := false
+	for _, member := range targetMembers {
+		targetFields[member.field] = true
+		value := member.value
+		i, patched := patchIndex[member.field]
+		if patched && isNullValue(patchMembers[i].value) {
+			continue
+		}
+		if more {
+			stream.WriteMore()
+		}
+		more = true
+		stream.WriteObjectField(member.field)
+		if patched {
+			cfg.writeMergePatch(stream, value, patchMembers[i].value)
+		} else {
+			stream.buf = append(stream.buf, value...)
+		}
+	}
+	for i, member := range patchMembers {
+		if patchIndex[member.field] != i || targetFields[member.field] || isNullValue(member.value) {
+			continue
+		}
+		if more {
+			stream.WriteMore()
+		}
+		more = true
+		stream.WriteObjectField(member.field)
+		cfg.writeMergePatch(stream, nil, member.value)
+	}
+	stream.WriteObjectEnd()
+}
+
+func (cfg *frozenConfig) writeCreateMergePatch(stream *Stream, original []byte, modified []byte) {
+	originalMembers := cfg.readMergePatchMembers(original)
+	modifiedMembers := cfg.readMergePatchMembers(modified)
+	if originalMembers == nil || modifiedMembers == nil {
+		stream.buf = append(stream.buf, modified...)
+		return
+	}
+	modifiedIndex := make(map[string]int, len(modifiedMembers))
+	for i, member := range modifiedMembers {
+		modifiedIndex[member.field] = i
+	}
+	originalFields := make(map[string]bool, len(originalMembers))
+	stream.WriteObjectStart()
+	more := false
+	writeField := func(field string) {
+		if more {
+			stream.WriteMore()
+		}
+		more = true
+		stream.WriteObjectField(field)
+	}
+	for _, member := range originalMembers {
+		if originalFields[member.field] {
+			continue
+		}
+		originalFields[member.field] = true
+		i, found := modifiedIndex[member.field]
+		if !found {
+			writeField(member.field)
+			stream.WriteNil()
+			continue
+		}
+		value := modifiedMembers[i].value
+		if valueTypes[member.value[0]] == ObjectValue && valueTypes[value[0]] == ObjectValue {
+			sub := cfg.BorrowStream(nil)
+			cfg.writeCreateMergePatch(sub, member.value, value)
+			if !bytes.Equal(sub.Buffer(), []byte("{}")) {
+				writeField(member.field)
+				stream.buf = append(stream.buf, sub.Buffer()...)
+			}
+			cfg.ReturnStream(sub)
+			continue
+		}
+		if !equalJSON(member.value, value) {
+			writeField(member.field)
+			stream.buf = append(stream.buf, value...)
+		}
+	}
+	for i, member := range modifiedMembers {
+		if modifiedIndex[member.field] != i || originalFields[member.field] {
+			continue
+		}
+		writeField(member.field)
+		stream.buf = append(stream.buf, member.value...)
+	}
+	stream.WriteObjectEnd()
+}
+
+func trimSpace(data []byte) []byte {
+	return bytes.Trim(data, " \t\n\r")
+}
+
+func isNullValue(data []byte) bool {
+	return len(data) == 4 && string(data) == "null"
+}
+
+// equalJSON compares two valid JSON values ignoring insignificant whitespace
+func equalJSON(a []byte, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var compactA, compactB bytes.Buffer
+	if json.Compact(&compactA, a) != nil || json.Compact(&compactB, b) != nil {
+		return false
+	}
+	return bytes.Equal(compactA.Bytes(), compactB.Bytes())
+}
+
+// MergePatchInto applies RFC 7396 JSON Merge Patch to the value v points to, in place.
+// Members of patch objects are merged into struct fields and map entries recursively,
+// null resets a struct field to its zero value and deletes a map entry, other values replace.
+func (cfg *frozenConfig) MergePatchInto(v interface{}, patch []byte) error {
+	typ := reflect2.TypeOf(v)
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		return errors.New("MergePatchInto: can only merge into pointer")
+	}
+	ptr := reflect2.PtrOf(v)
+	if ptr == nil {
+		return errors.New("MergePatchInto: can not merge into nil pointer")
+	}
+	iter := cfg.BorrowIterator(patch)
+	defer cfg.ReturnIterator(iter)
+	iter.mergePatch(typ.(*reflect2.UnsafePtrType).Elem(), ptr)
+	c := iter.nextToken()
+	if c == 0 {
+		if iter.Error == io.EOF {
+			return nil
+		}
+		return iter.Error
+	}
+	iter.ReportError("MergePatchInto", "there are bytes left after merge patch")
+	return iter.Error
+}
+
+func (cfg *frozenConfig) structFieldsOf(typ reflect2.Type) map[string]*structFieldDecoder {
+	cacheKey := typ.RType()
+	cfg.syncRegistry()
+	fields, found := cfg.structFieldsCache.Load(cacheKey)
+	if found {
+		return fields.(map[string]*structFieldDecoder)
+	}
+	ctx := &ctx{
+		frozenConfig: cfg,
+		prefix:       "",
+		decoders:     map[reflect2.Type]ValDecoder{},
+		encoders:     map[reflect2.Type]ValEncoder{},
+	}
+	fields = structFieldDecoders(ctx, typ)
+	cfg.structFieldsCache.Store(cacheKey, fields)
+	return fields.(map[string]*structFieldDecoder)
+}
+
+func (iter *Iterator) mergePatch(typ reflect2.Type, ptr unsafe.Pointer) {
+	iter.mergePatchWith(typ, ptr, nil)
+}
+
+// mergePatchWith merges next value into ptr of typ, decoder is used when the value replaces, nil for the cached one
+func (iter *Iterator) mergePatchWith(typ reflect2.Type, ptr unsafe.Pointer, decoder ValDecoder) {
+	switch iter.WhatIsNext() {
+	case NilValue:
+		iter.Skip()
+		typ.UnsafeSet(ptr, typ.UnsafeNew())
+		return
+	case ObjectValue:
+		if mergeable(typ) {
+			switch typ.Kind() {
+			case reflect.Struct:
+				iter.mergePatchStruct(typ, ptr)
+				return
+			case reflect.Map:
+				if mapDecoder, ok := iter.cfg.DecoderOf(reflect2.PtrTo(typ)).(*mapDecoder); ok {
+					iter.mergePatchMap(mapDecoder, ptr)
+					return
+				}
+			case reflect.Ptr:
+				elemType := typ.(*reflect2.UnsafePtrType).Elem()
+				if mergeable(elemType) {
+					if *(*unsafe.Pointer)(ptr) == nil {
+						*(*unsafe.Pointer)(ptr) = elemType.UnsafeNew()
+					}
+					iter.mergePatch(elemType, *(*unsafe.Pointer)(ptr))
+					return
+				}
+			case reflect.Interface:
+				iter.mergePatchInterface(typ, ptr)
+				return
+			}
+		}
+	}
+	if decoder == nil {
+		decoder = iter.cfg.DecoderOf(reflect2.PtrTo(typ))
+	}
+	typ.UnsafeSet(ptr, typ.UnsafeNew())
+	decoder.Decode(ptr, iter)
+}
+
+// mergeable tells if object patch should be merged member by member into typ
+func mergeable(typ reflect2.Type) bool {
+	switch typ.Kind() {
+	case reflect.Struct, reflect.Map:
+		ptrType := reflect2.PtrTo(typ)
+		return !ptrType.Implements(unmarshalerType) && !ptrType.Implements(textUnmarshalerType)
+	case reflect.Ptr:
+		return true
+	case reflect.Interface:
+		return typ.Type1().NumMethod() == 0
+	}
+	return false
+}
+
+func (iter *Iterator) mergePatchStruct(typ reflect2.Type, ptr unsafe.Pointer) {
+	fields := iter.cfg.structFieldsOf(typ)
+	iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+		fieldDecoder := fields[field]
+		if fieldDecoder == nil && !iter.cfg.caseSensitive {
+			fieldDecoder = fields[strings.ToLower(field)]
+		}
+		if fieldDecoder == nil {
+			if iter.cfg.disallowUnknownFields {
+				iter.ReportError("MergePatchInto", "found unknown field: "+field)
+			}
+			iter.Skip()
+			return true
+		}
+		fieldPtr, fieldType, decoder := resolveStructField(fieldDecoder, ptr)
+		iter.mergePatchWith(fieldType, fieldPtr, decoder)
+		if iter.Error != nil && iter.Error != io.EOF {
+			iter.addErrorField(field, fieldType.Type1())
+			return false
+		}
+		return true
+	})
+	if iter.Error != nil && iter.Error != io.EOF {
+		iter.addErrorStruct(typ)
+	}
+}
+
+// resolveStructField locates the field through embedded structs, allocating embedded pointers if needed
+func resolveStructField(fieldDecoder *structFieldDecoder, ptr unsafe.Pointer) (unsafe.Pointer, reflect2.Type, ValDecoder) {
+	for {
+		ptr = fieldDecoder.field.UnsafeGet(ptr)
+		switch decoder := fieldDecoder.fieldDecoder.(type) {
+		case *structFieldDecoder:
+			fieldDecoder = decoder
+			continue
+		case *dereferenceDecoder:
+			if embedded, ok := decoder.valueDecoder.(*structFieldDecoder); ok {
+				if *(*unsafe.Pointer)(ptr) == nil {
+					*(*unsafe.Pointer)(ptr) = decoder.valueType.UnsafeNew()
+				}
+				ptr = *(*unsafe.Pointer)(ptr)
+				fieldDecoder = embedded
+				continue
+			}
+		}
+		return ptr, fieldDecoder.field.Type(), fieldDecoder.fieldDecoder
+	}
+}
+
+func (iter *Iterator) mergePatchMap(decoder *mapDecoder, ptr unsafe.Pointer) {
+	mapType := decoder.mapType
+	if mapType.UnsafeIsNil(ptr) {
+		mapType.UnsafeSet(ptr, mapType.UnsafeMakeMap(0))
+	}
+	iter.ReadMapCB(func(iter *Iterator, field string) bool {
+		key := decoder.keyType.UnsafeNew()
+		keyIter := iter.cfg.BorrowIterator(nil)
+		stream := iter.cfg.BorrowStream(nil)
+		stream.WriteString(field)
+		keyIter.ResetBytes(stream.Buffer())
+		decoder.keyDecoder.Decode(key, keyIter)
+		err := keyIter.Error
+		iter.cfg.ReturnStream(stream)
+		iter.cfg.ReturnIterator(keyIter)
+		if err != nil && err != io.EOF {
+			iter.Error = err
+			return false
+		}
+		if iter.WhatIsNext() == NilValue {
+			iter.Skip()
+			mapValue := reflect.ValueOf(mapType.UnsafeIndirect(ptr))
+			mapValue.SetMapIndex(reflect.ValueOf(decoder.keyType.UnsafeIndirect(key)), reflect.Value{})
+			return true
+		}
+		elem := decoder.elemType.UnsafeNew()
+		if existing := mapType.UnsafeGetIndex(ptr, key); existing != nil {
+			decoder.elemType.UnsafeSet(elem, existing)
+		}
+		iter.mergePatchWith(decoder.elemType, elem, decoder.elemDecoder)
+		mapType.UnsafeSetIndex(ptr, key, elem)
+		return iter.Error == nil || iter.Error == io.EOF
+	})
+}
+
+// mergePatchInterface merges into the dynamic value by its JSON representation
+func (iter *Iterator) mergePatchInterface(typ reflect2.Type, ptr unsafe.Pointer) {
+	patch := iter.SkipAndReturnBytes()
+	if iter.Error != nil && iter.Error != io.EOF {
+		return
+	}
+	original, err := iter.cfg.Marshal(typ.UnsafeIndirect(ptr))
+	if err != nil {
+		iter.Error = err
+		return
+	}
+	merged, err := iter.cfg.mergePatch(original, patch)
+	if err != nil {
+		iter.Error = err
+		return
+	}
+	typ.UnsafeSet(ptr, typ.UnsafeNew())
+	mergedIter := iter.cfg.BorrowIterator(merged)
+	defer iter.cfg.ReturnIterator(mergedIter)
+	iter.cfg.DecoderOf(reflect2.PtrTo(typ)).Decode(ptr, mergedIter)
+	if mergedIter.Error != nil && mergedIter.Error != io.EOF {
+		iter.Error = mergedIter.Error
+	}
+}
@@ -0,0 +1,96 @@
+package misc_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type errorTestItem struct {
+	Price float64
+}
+
+type errorTestOrder struct {
+	Items []errorTestItem
+}
+
+func Test_syntax_error_position(t *testing.T) {
+	should := require.New(t)
+	var val interface{}
+	err := jsoniter.UnmarshalFromString("{\n  \"a\": 1,\n  \"b\" 2\n}", &val)
+	var syntaxErr *jsoniter.SyntaxError
+	should.True(errors.As(err, &syntaxErr))
+	should.Equal(":", syntaxErr.Expected)
+	should.Equal(int64(19), syntaxErr.Offset)
+	should.Equal(3, syntaxErr.Line)
+	should.Equal(8, syntaxErr.Column)
+
+	for _, input := range []string{`{"a" 1}`, `{"a":1,"b" 1}`} {
+		iter := jsoniter.ParseString(jsoniter.ConfigDefault, input)
+		iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+			iter.Skip()
+			return true
+		})
+		should.True(errors.As(iter.Error, &syntaxErr), input)
+		should.Equal("expect :, but found 1", syntaxErr.Msg[:len("expect :, but found 1")], input)
+	}
+}
+
+func Test_syntax_error_offset_across_reader_refills(t *testing.T) {
+	should := require.New(t)
+	input := "[" + string(bytes.Repeat([]byte("1,\n"), 100)) + "x]"
+	iter := jsoniter.Parse(jsoniter.ConfigDefault, bytes.NewBufferString(input), 16)
+	iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+		iter.Skip()
+		return true
+	})
+	var syntaxErr *jsoniter.SyntaxError
+	should.True(errors.As(iter.Error, &syntaxErr))
+	should.Equal(int64(302), syntaxErr.Offset)
+	should.Equal(101, syntaxErr.Line)
+	should.Equal(2, syntaxErr.Column)
+}
+
+func Test_unmarshal_type_error_field_path(t *testing.T) {
+	should := require.New(t)
+	var order errorTestOrder
+	err := jsoniter.UnmarshalFromString(`{"Items":[{"Price":1},{"Price":2},{"Price":3},{"Price":"4"}]}`, &order)
+	var typeErr *jsoniter.UnmarshalTypeError
+	should.True(errors.As(err, &typeErr))
+	should.Equal("string", typeErr.Value)
+	should.Equal(reflect.TypeOf(float64(0)), typeErr.Type)
+	should.Equal("errorTestItem", typeErr.Struct)
+	should.Equal("Items[3].Price", typeErr.Field)
+	should.Equal(int64(55), typeErr.Offset)
+	should.Contains(err.Error(), "misc_tests.errorTestOrder.Items[3].Price")
+}
+
+func Test_errors_compatible_with_standard_library(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.ConfigCompatibleWithStandardLibrary
+	var order errorTestOrder
+	err := api.UnmarshalFromString(`{"Items":[{"Price":true}]}`, &order)
+	var typeErr *json.UnmarshalTypeError
+	should.True(errors.As(err, &typeErr))
+	should.Equal("bool", typeErr.Value)
+	should.Equal(reflect.TypeOf(float64(0)), typeErr.Type)
+	should.Equal("Items[0].Price", typeErr.Field)
+	err = api.UnmarshalFromString(`{"Items":[}`, &order)
+	var syntaxErr *json.SyntaxError
+	should.True(errors.As(err, &syntaxErr))
+	should.Equal(int64(11), syntaxErr.Offset)
+	should.NotEmpty(syntaxErr.Error())
+	var iterErr *jsoniter.SyntaxError
+	should.True(errors.As(err, &iterErr))
+	should.NotEmpty(iterErr.Msg)
+	// the input read from a reader is not kept
+	err = api.NewDecoder(strings.NewReader(`{"Items":[}`)).Decode(&order)
+	should.True(errors.As(err, &iterErr))
+	should.False(errors.As(err, &syntaxErr))
+}
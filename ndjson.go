@@ -0,0 +1,283 @@
+package jsoniter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+
+	"github.com/modern-go/reflect2"
+)
+
+// LineError is returned by LineReader for the line failed to decode
+type LineError struct {
+	Line int    // line number, starting from 1
+	Raw  []byte // the line, without line break
+	Err  error
+}
+
+func (err *LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", err.Line, err.Err.Error())
+}
+
+// Unwrap returns the decode error
+func (err *LineError) Unwrap() error {
+	return err.Err
+}
+
+// LineReader reads newline delimited JSON (NDJSON, JSON Lines), one value per line.
+// Blank lines are skipped.
+type LineReader struct {
+	api     API
+	reader  *bufio.Reader
+	buf     []byte
+	raw     []byte
+	line    int
+	skip    bool
+	onError func(err *LineError)
+	pending []pendingLine
+}
+
+// pendingLine is a line read by ReadBatch but not returned yet, after a bad line
+type pendingLine struct {
+	line int
+	raw  []byte
+}
+
+// NewLineReader creates a LineReader decoding the lines of reader with api.
+func NewLineReader(api API, reader io.Reader) *LineReader {
+	return &LineReader{api: api, reader: bufio.NewReaderSize(reader, 64*1024)}
+}
+
+// SkipBadLines makes Next continue with the next line when a line can not be decoded,
+// onError is called with the skipped line if it is not nil.
+func (reader *LineReader) SkipBadLines(onError func(err *LineError)) *LineReader {
+	reader.skip = true
+	reader.onError = onError
+	return reader
+}
+
+// Next decodes the next line into v. It returns io.EOF when there is no more line,
+// and *LineError when the line can not be decoded.
+func (reader *LineReader) Next(v interface{}) error {
+	return reader.next(func(line []byte) error {
+		return reader.api.Unmarshal(line, v)
+	})
+}
+
+// Line returns the number of the last line read, starting from 1.
+func (reader *LineReader) Line() int {
+	return reader.line
+}
+
+// Raw returns the last line read, without line break. It is only valid until the next call to Next.
+func (reader *LineReader) Raw() []byte {
+	return reader.raw
+}
+
+func (reader *LineReader) next(decode func(line []byte) error) error {
+	for {
+		line, err := reader.readLine()
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		err = decode(line)
+		if err == nil {
+			return nil
+		}
+		lineErr := &LineError{Line: reader.line, Raw: append([]byte(nil), line...), Err: err}
+		if !reader.skip {
+			return lineErr
+		}
+		if reader.onError != nil {
+			reader.onError(lineErr)
+		}
+	}
+}
+
+// readLine reads the next line without line break, lines longer than the buffer are accumulated
+func (reader *LineReader) readLine() ([]byte, error) {
+	if len(reader.pending) > 0 {
+		pending := reader.pending[0]
+		reader.pending = reader.pending[1:]
+		reader.line = pending.line
+		reader.raw = pending.raw
+		return pending.raw, nil
+	}
+	reader.buf = reader.buf[:0]
+	for {
+		fragment, err := reader.reader.ReadSlice('\n')
+		reader.buf = append(reader.buf, fragment...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err == io.EOF && len(reader.buf) > 0 {
+			// last line without line break
+			err = nil
+		}
+		if err != nil {
+			reader.raw = nil
+			return nil, err
+		}
+		reader.line++
+		line := bytes.TrimSuffix(reader.buf, []byte{'\n'})
+		line = bytes.TrimSuffix(line, []byte{'\r'})
+		reader.raw = line
+		return line, nil
+	}
+}
+
+// ReadBatch reads up to size lines, and decodes them concurrently on workers goroutines into the slice pointed by v.
+// The slice is resized to the number of values, which are in line order. It returns io.EOF when there is no more line.
+// If a line can not be decoded, the slice keeps the values before it, *LineError is returned,
+// and the lines after it are returned by the next call. Bad lines are dropped from the slice if SkipBadLines is set.
+func (reader *LineReader) ReadBatch(v interface{}, size int, workers int) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	typ := reflect2.TypeOf(v)
+	if typ == nil || typ.Kind() != reflect.Ptr || typ.(reflect2.PtrType).Elem().Kind() != reflect.Slice || reflect2.IsNil(v) {
+		return errors.New("ReadBatch: can only read into pointer to slice")
+	}
+	sliceType := typ.(reflect2.PtrType).Elem().(*reflect2.UnsafeSliceType)
+	elemType := sliceType.Elem()
+	decoder := reader.api.DecoderOf(reflect2.PtrTo(elemType))
+	lines := []pendingLine{}
+	for len(lines) < size {
+		line, err := reader.readLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			reader.pending = append(lines, reader.pending...)
+			return err
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		lines = append(lines, pendingLine{reader.line, append([]byte(nil), line...)})
+	}
+	ptr := reflect2.PtrOf(v)
+	sliceType.UnsafeGrow(ptr, 0)
+	if len(lines) == 0 {
+		return io.EOF
+	}
+	sliceType.UnsafeGrow(ptr, len(lines))
+	zero := elemType.UnsafeNew()
+	lineErrs := make([]error, len(lines))
+	parallelFor(reader.api, len(lines), workers, func(iter *Iterator, i int) error {
+		elemPtr := sliceType.UnsafeGetIndex(ptr, i)
+		// the slice may hold the values of the previous batch
+		elemType.UnsafeSet(elemPtr, zero)
+		lineErrs[i] = decodeLine(iter, lines[i].raw, decoder, elemPtr, elemType.Type1())
+		return nil
+	})
+	length := 0
+	for i, err := range lineErrs {
+		if err == nil {
+			if length != i {
+				elemType.UnsafeSet(sliceType.UnsafeGetIndex(ptr, length), sliceType.UnsafeGetIndex(ptr, i))
+			}
+			length++
+			continue
+		}
+		lineErr := &LineError{Line: lines[i].line, Raw: lines[i].raw, Err: err}
+		if !reader.skip {
+			sliceType.UnsafeGrow(ptr, length)
+			reader.pending = append(lines[i+1:], reader.pending...)
+			reader.line, reader.raw = lineErr.Line, lineErr.Raw
+			return lineErr
+		}
+		if reader.onError != nil {
+			reader.onError(lineErr)
+		}
+	}
+	sliceType.UnsafeGrow(ptr, length)
+	last := lines[len(lines)-1]
+	reader.line, reader.raw = last.line, last.raw
+	return nil
+}
+
+const lineWriterFlushSize = 32 * 1024
+
+// LineWriter writes newline delimited JSON (NDJSON, JSON Lines), one value per line.
+// Values are always written compactly, even if the config sets indention.
+type LineWriter struct {
+	stream *Stream // encodes one value at a time, has no writer so that the value is complete in its buffer
+	lines  []byte  // lines not written to writer yet
+	writer io.Writer
+	err    error // error of writer, returned by the later calls
+}
+
+// NewLineWriter creates a LineWriter encoding values with api into writer. Close returns the pooled stream.
+func NewLineWriter(api API, writer io.Writer) *LineWriter {
+	cfg := api.(*frozenConfig)
+	if cfg.indentionStep > 0 {
+		compact := cfg.configBeforeFrozen
+		compact.IndentionStep = 0
+		compact.IndentPrefix = ""
+		compact.Indent = ""
+		compact.indentAlways = false
+		cfg = compact.frozeWithCacheReuse(cfg.extraExtensions)
+	}
+	return &LineWriter{stream: cfg.BorrowStream(nil), writer: writer}
+}
+
+// Write encodes v on one line. The lines are buffered until Flush or Close.
+func (writer *LineWriter) Write(v interface{}) error {
+	if writer.err != nil {
+		return writer.err
+	}
+	stream := writer.stream
+	stream.Reset(nil)
+	stream.Error = nil
+	stream.WriteVal(v)
+	if stream.Error != nil {
+		return stream.Error
+	}
+	value := stream.Buffer()
+	// values written as is, such as RawMessage and Marshaler results, may span lines
+	if bytes.IndexAny(value, "\r\n") != -1 {
+		compacted := bytes.NewBuffer(make([]byte, 0, len(value)))
+		if err := json.Compact(compacted, value); err != nil {
+			return err
+		}
+		value = compacted.Bytes()
+	}
+	writer.lines = append(append(writer.lines, value...), '\n')
+	if len(writer.lines) >= lineWriterFlushSize {
+		return writer.Flush()
+	}
+	return nil
+}
+
+// Flush writes the buffered lines to the underlying writer.
+func (writer *LineWriter) Flush() error {
+	if writer.err != nil || len(writer.lines) == 0 {
+		return writer.err
+	}
+	if _, err := writer.writer.Write(writer.lines); err != nil {
+		writer.err = err
+		return err
+	}
+	writer.lines = writer.lines[:0]
+	return nil
+}
+
+// Close flushes the buffered lines, and returns the stream to the pool. The LineWriter can not be used after Close.
+func (writer *LineWriter) Close() error {
+	if writer.stream == nil {
+		return nil
+	}
+	err := writer.Flush()
+	writer.stream.cfg.ReturnStream(writer.stream)
+	writer.stream = nil
+	return err
+}
@@ -0,0 +1,169 @@
+package jsoniter
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"sync"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// UnmarshalParallel decodes a top-level JSON array into the slice pointed by v, same as Unmarshal,
+// but the elements are decoded concurrently by workers goroutines, GOMAXPROCS if workers is not positive.
+// The element boundaries are found by skipping through the array first.
+// The elements keep their order, and the error is the one Unmarshal reports, for the first element failed.
+// Other values, and input that is not valid JSON, are decoded by Unmarshal on the calling goroutine.
+func (cfg *frozenConfig) UnmarshalParallel(data []byte, v interface{}, workers int) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	typ := reflect2.TypeOf(v)
+	if workers == 1 || typ == nil || typ.Kind() != reflect.Ptr || reflect2.IsNil(v) {
+		return cfg.Unmarshal(data, v)
+	}
+	decoder, isSlice := cfg.DecoderOf(typ).(*sliceDecoder)
+	if !isSlice {
+		return cfg.Unmarshal(data, v)
+	}
+	bounds := cfg.scanElements(data)
+	if bounds == nil {
+		return cfg.Unmarshal(data, v)
+	}
+	sliceType := decoder.sliceType
+	ptr := reflect2.PtrOf(v)
+	length := len(bounds) / 2
+	if length == 0 {
+		sliceType.UnsafeSet(ptr, sliceType.UnsafeMakeSlice(0, 0))
+		return nil
+	}
+	sliceType.UnsafeGrow(ptr, length)
+	elemType := sliceType.Elem().Type1()
+	_, err := parallelFor(cfg, length, workers, func(iter *Iterator, i int) error {
+		// the whole input is kept, so the error reports the same offset and context as Unmarshal
+		iter.ResetBytes(data)
+		iter.Error = nil
+		iter.head = bounds[2*i]
+		// the element is inside the top-level array, as when Unmarshal decodes it
+		iter.depth = 1
+		decoder.elemDecoder.Decode(sliceType.UnsafeGetIndex(ptr, i), iter)
+		if iter.Error == nil || iter.Error == io.EOF {
+			return nil
+		}
+		iter.addErrorIndex(i, elemType)
+		if !iter.isPathError() {
+			iter.Error = fmt.Errorf("%v: %s", sliceType, iter.Error.Error())
+		}
+		if typeErr, ok := iter.Error.(*UnmarshalTypeError); ok && typeErr.Type == nil {
+			typeErr.Type = typ.Type1().Elem()
+		}
+		return iter.Error
+	})
+	return err
+}
+
+// scanElements returns the start and end offsets of the elements of the top-level array in data,
+// or nil if data is not a valid array without trailing bytes
+func (cfg *frozenConfig) scanElements(data []byte) []int {
+	iter := cfg.BorrowIterator(data)
+	defer cfg.ReturnIterator(iter)
+	if iter.nextToken() != '[' {
+		return nil
+	}
+	bounds := []int{}
+	c := iter.nextToken()
+	if c != ']' {
+		iter.unreadByte()
+		for {
+			if iter.nextToken() == 0 || !iter.checkElements(len(bounds)/2+1) {
+				return nil
+			}
+			iter.unreadByte()
+			start := iter.head
+			iter.Skip()
+			if iter.Error != nil && iter.Error != io.EOF {
+				return nil
+			}
+			bounds = append(bounds, start, iter.head)
+			c = iter.nextToken()
+			if c != ',' {
+				break
+			}
+		}
+	}
+	if c != ']' || iter.nextToken() != 0 || (iter.Error != nil && iter.Error != io.EOF) {
+		return nil
+	}
+	return bounds
+}
+
+// parallelFor calls fn for each index below n on workers goroutines, each with an iterator borrowed from pool.
+// Indexes are handed out in ascending chunks, and no chunk is started after an error at a lower index,
+// so the returned error is the one of the lowest index failed, -1 if none.
+func parallelFor(pool IteratorPool, n int, workers int, fn func(iter *Iterator, i int) error) (int, error) {
+	chunk := n/(workers*8) + 1
+	mutex := sync.Mutex{}
+	next := 0
+	failedAt := n
+	var failure error
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			iter := pool.BorrowIterator(nil)
+			defer pool.ReturnIterator(iter)
+			for {
+				mutex.Lock()
+				start := next
+				next += chunk
+				stop := start >= n || start > failedAt
+				mutex.Unlock()
+				if stop {
+					return
+				}
+				end := start + chunk
+				if end > n {
+					end = n
+				}
+				for i := start; i < end; i++ {
+					if err := fn(iter, i); err != nil {
+						mutex.Lock()
+						if i < failedAt {
+							failedAt = i
+							failure = err
+						}
+						mutex.Unlock()
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if failure == nil {
+		return -1, nil
+	}
+	return failedAt, failure
+}
+
+// decodeLine decodes line into ptr with decoder, same as Unmarshal
+func decodeLine(iter *Iterator, line []byte, decoder ValDecoder, ptr unsafe.Pointer, typ reflect.Type) error {
+	iter.ResetBytes(line)
+	iter.Error = nil
+	decoder.Decode(ptr, iter)
+	if typeErr, ok := iter.Error.(*UnmarshalTypeError); ok && typeErr.Type == nil {
+		typeErr.Type = typ
+	}
+	c := iter.nextToken()
+	if c == 0 {
+		if iter.Error == io.EOF {
+			return nil
+		}
+		return iter.Error
+	}
+	iter.ReportError("Unmarshal", "there are bytes left after unmarshal")
+	return iter.Error
+}
@@ -26,6 +26,9 @@ func (cfg *frozenConfig) ReturnStream(stream *Stream) {
 	stream.out = nil
 	stream.Error = nil
 	stream.Attachment = nil
+	stream.indention = 0
+	stream.flushAt = 0
+	stream.outErr = nil
 	cfg.streamPool.Put(stream)
 }
 
@@ -39,6 +39,8 @@ type ctx struct {
 	prefix   string
 	encoders map[reflect2.Type]ValEncoder
 	decoders map[reflect2.Type]ValDecoder
+	// the struct decoders with defaults, which are checked once all the decoders are created
+	checkedDecoders *[]*checkedStructDecoder
 }
 
 func (b *ctx) caseSensitive() bool {
@@ -55,6 +57,8 @@ func (b *ctx) append(prefix string) *ctx {
 		prefix:       b.prefix + " " + prefix,
 		encoders:     b.encoders,
 		decoders:     b.decoders,
+
+		checkedDecoders: b.checkedDecoders,
 	}
 }
 
@@ -77,6 +81,9 @@ func (iter *Iterator) ReadVal(obj interface{}) {
 		return
 	}
 	decoder.Decode(ptr, iter)
+	if typeErr, ok := iter.Error.(*UnmarshalTypeError); ok && typeErr.Type == nil {
+		typeErr.Type = reflect2.TypeOf(obj).Type1().Elem()
+	}
 	if iter.depth != depth {
 		iter.ReportError("ReadVal", "unexpected mismatched nesting")
 		return
@@ -85,6 +92,7 @@ func (iter *Iterator) ReadVal(obj interface{}) {
 
 // WriteVal copy the go interface into underlying JSON, same as json.Marshal
 func (stream *Stream) WriteVal(val interface{}) {
+	stream.flushIfFull()
 	if nil == val {
 		stream.WriteNil()
 		return
@@ -104,14 +112,23 @@ func (cfg *frozenConfig) DecoderOf(typ reflect2.Type) ValDecoder {
 	if decoder != nil {
 		return decoder
 	}
+	checkedDecoders := []*checkedStructDecoder{}
 	ctx := &ctx{
 		frozenConfig: cfg,
 		prefix:       "",
 		decoders:     map[reflect2.Type]ValDecoder{},
 		encoders:     map[reflect2.Type]ValEncoder{},
+
+		checkedDecoders: &checkedDecoders,
 	}
 	ptrType := typ.(*reflect2.UnsafePtrType)
 	decoder = decoderOfType(ctx, ptrType.Elem())
+	for _, checked := range checkedDecoders {
+		if err := checked.checkDefaults(cfg); err != nil {
+			decoder = &lazyErrorDecoder{err: err}
+			break
+		}
+	}
 	cfg.addDecoderToCache(cacheKey, decoder)
 	return decoder
 }
@@ -122,7 +139,7 @@ func decoderOfType(ctx *ctx, typ reflect2.Type) ValDecoder {
 		return decoder
 	}
 	decoder = createDecoderOfType(ctx, typ)
-	for _, extension := range extensions {
+	for _, extension := range ctx.registry.getExtensions() {
 		decoder = extension.DecorateDecoder(typ, decoder)
 	}
 	decoder = ctx.decoderExtension.DecorateDecoder(typ, decoder)
@@ -225,7 +242,7 @@ func encoderOfType(ctx *ctx, typ reflect2.Type) ValEncoder {
 		return encoder
 	}
 	encoder = createEncoderOfType(ctx, typ)
-	for _, extension := range extensions {
+	for _, extension := range ctx.registry.getExtensions() {
 		encoder = extension.DecorateEncoder(typ, encoder)
 	}
 	encoder = ctx.encoderExtension.DecorateEncoder(typ, encoder)
@@ -47,7 +47,7 @@ func (encoder *arrayEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 		encoder.elemEncoder.Encode(elemPtr, stream)
 	}
 	stream.WriteArrayEnd()
-	if stream.Error != nil && stream.Error != io.EOF {
+	if stream.hasValueError() {
 		stream.Error = fmt.Errorf("%v: %s", encoder.arrayType, stream.Error.Error())
 	}
 }
@@ -63,7 +63,7 @@ type arrayDecoder struct {
 
 func (decoder *arrayDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
 	decoder.doDecode(ptr, iter)
-	if iter.Error != nil && iter.Error != io.EOF {
+	if iter.Error != nil && iter.Error != io.EOF && !iter.isPathError() {
 		iter.Error = fmt.Errorf("%v: %s", decoder.arrayType, iter.Error.Error())
 	}
 }
@@ -76,7 +76,7 @@ func (decoder *arrayDecoder) doDecode(ptr unsafe.Pointer, iter *Iterator) {
 		return
 	}
 	if c != '[' {
-		iter.ReportError("decode array", "expect [ or n, but found "+string([]byte{c}))
+		iter.reportUnexpectedValue("decode array", "[ or n", c, arrayType.Type1())
 		return
 	}
 	c = iter.nextToken()
@@ -84,9 +84,24 @@ func (decoder *arrayDecoder) doDecode(ptr unsafe.Pointer, iter *Iterator) {
 		return
 	}
 	iter.unreadByte()
+	if !iter.incrementDepth() {
+		return
+	}
+	decoder.decodeElements(ptr, iter)
+	iter.decrementDepth()
+}
+
+// decodeElements decodes the elements of the array, which is not empty
+func (decoder *arrayDecoder) decodeElements(ptr unsafe.Pointer, iter *Iterator) {
+	arrayType := decoder.arrayType
 	elemPtr := arrayType.UnsafeGetIndex(ptr, 0)
 	decoder.elemDecoder.Decode(elemPtr, iter)
+	if iter.Error != nil && iter.Error != io.EOF {
+		iter.addErrorIndex(0, arrayType.Elem().Type1())
+		return
+	}
 	length := 1
+	var c byte
 	for c = iter.nextToken(); c == ','; c = iter.nextToken() {
 		if length >= arrayType.Len() {
 			iter.Skip()
@@ -96,9 +111,13 @@ func (decoder *arrayDecoder) doDecode(ptr unsafe.Pointer, iter *Iterator) {
 		length += 1
 		elemPtr = arrayType.UnsafeGetIndex(ptr, idx)
 		decoder.elemDecoder.Decode(elemPtr, iter)
+		if iter.Error != nil && iter.Error != io.EOF {
+			iter.addErrorIndex(idx, arrayType.Elem().Type1())
+			return
+		}
 	}
 	if c != ']' {
-		iter.ReportError("decode array", "expect ], but found "+string([]byte{c}))
+		iter.reportUnexpected("decode array", "]", c)
 		return
 	}
 }
@@ -1,8 +1,8 @@
 package jsoniter
 
 import (
-	"fmt"
 	"github.com/modern-go/reflect2"
+	"io"
 	"reflect"
 	"sort"
 	"strings"
@@ -10,16 +10,11 @@ import (
 	"unsafe"
 )
 
-var typeDecoders = map[string]ValDecoder{}
-var fieldDecoders = map[string]ValDecoder{}
-var typeEncoders = map[string]ValEncoder{}
-var fieldEncoders = map[string]ValEncoder{}
-var extensions = []Extension{}
-
 // StructDescriptor describe how should we encode/decode the struct
 type StructDescriptor struct {
-	Type   reflect2.Type
-	Fields []*Binding
+	Type      reflect2.Type
+	Fields    []*Binding
+	inlineMap *inlineMap
 }
 
 // GetField get one field from the descriptor by its name.
@@ -35,12 +30,14 @@ func (structDescriptor *StructDescriptor) GetField(fieldName string) *Binding {
 
 // Binding describe how should we encode/decode the struct field
 type Binding struct {
-	levels    []int
-	Field     reflect2.StructField
-	FromNames []string
-	ToNames   []string
-	Encoder   ValEncoder
-	Decoder   ValDecoder
+	levels       []int
+	required     bool   // tag option required, decoding fails if the field is absent
+	defaultValue []byte // JSON of the tag option default, decoded if the field is absent
+	Field        reflect2.StructField
+	FromNames    []string
+	ToNames      []string
+	Encoder      ValEncoder
+	Decoder      ValDecoder
 }
 
 // Extension the one for all SPI. Customize encoding/decoding by specifying alternate encoder/decoder.
@@ -197,53 +194,53 @@ type EncoderFunc func(ptr unsafe.Pointer, stream *Stream)
 
 // RegisterTypeDecoderFunc register TypeDecoder for a type with function
 func RegisterTypeDecoderFunc(typ string, fun DecoderFunc) {
-	typeDecoders[typ] = &funcDecoder{fun}
+	defaultRegistry.RegisterTypeDecoderFunc(typ, fun)
 }
 
 // RegisterTypeDecoder register TypeDecoder for a typ
 func RegisterTypeDecoder(typ string, decoder ValDecoder) {
-	typeDecoders[typ] = decoder
+	defaultRegistry.RegisterTypeDecoder(typ, decoder)
 }
 
 // RegisterFieldDecoderFunc register TypeDecoder for a struct field with function
 func RegisterFieldDecoderFunc(typ string, field string, fun DecoderFunc) {
-	RegisterFieldDecoder(typ, field, &funcDecoder{fun})
+	defaultRegistry.RegisterFieldDecoderFunc(typ, field, fun)
 }
 
 // RegisterFieldDecoder register TypeDecoder for a struct field
 func RegisterFieldDecoder(typ string, field string, decoder ValDecoder) {
-	fieldDecoders[fmt.Sprintf("%s/%s", typ, field)] = decoder
+	defaultRegistry.RegisterFieldDecoder(typ, field, decoder)
 }
 
 // RegisterTypeEncoderFunc register TypeEncoder for a type with encode/isEmpty function
 func RegisterTypeEncoderFunc(typ string, fun EncoderFunc, isEmptyFunc func(unsafe.Pointer) bool) {
-	typeEncoders[typ] = &funcEncoder{fun, isEmptyFunc}
+	defaultRegistry.RegisterTypeEncoderFunc(typ, fun, isEmptyFunc)
 }
 
 // RegisterTypeEncoder register TypeEncoder for a type
 func RegisterTypeEncoder(typ string, encoder ValEncoder) {
-	typeEncoders[typ] = encoder
+	defaultRegistry.RegisterTypeEncoder(typ, encoder)
 }
 
 // RegisterFieldEncoderFunc register TypeEncoder for a struct field with encode/isEmpty function
 func RegisterFieldEncoderFunc(typ string, field string, fun EncoderFunc, isEmptyFunc func(unsafe.Pointer) bool) {
-	RegisterFieldEncoder(typ, field, &funcEncoder{fun, isEmptyFunc})
+	defaultRegistry.RegisterFieldEncoderFunc(typ, field, fun, isEmptyFunc)
 }
 
 // RegisterFieldEncoder register TypeEncoder for a struct field
 func RegisterFieldEncoder(typ string, field string, encoder ValEncoder) {
-	fieldEncoders[fmt.Sprintf("%s/%s", typ, field)] = encoder
+	defaultRegistry.RegisterFieldEncoder(typ, field, encoder)
 }
 
 // RegisterExtension register extension
 func RegisterExtension(extension Extension) {
-	extensions = append(extensions, extension)
+	defaultRegistry.RegisterExtension(extension)
 }
 
 func getTypeDecoderFromExtension(ctx *ctx, typ reflect2.Type) ValDecoder {
 	decoder := _getTypeDecoderFromExtension(ctx, typ)
 	if decoder != nil {
-		for _, extension := range extensions {
+		for _, extension := range ctx.registry.getExtensions() {
 			decoder = extension.DecorateDecoder(typ, decoder)
 		}
 		decoder = ctx.decoderExtension.DecorateDecoder(typ, decoder)
@@ -254,7 +251,7 @@ func getTypeDecoderFromExtension(ctx *ctx, typ reflect2.Type) ValDecoder {
 	return decoder
 }
 func _getTypeDecoderFromExtension(ctx *ctx, typ reflect2.Type) ValDecoder {
-	for _, extension := range extensions {
+	for _, extension := range ctx.registry.getExtensions() {
 		decoder := extension.CreateDecoder(typ)
 		if decoder != nil {
 			return decoder
@@ -271,13 +268,13 @@ func _getTypeDecoderFromExtension(ctx *ctx, typ reflect2.Type) ValDecoder {
 		}
 	}
 	typeName := typ.String()
-	decoder = typeDecoders[typeName]
+	decoder = ctx.registry.getTypeDecoder(typeName)
 	if decoder != nil {
 		return decoder
 	}
 	if typ.Kind() == reflect.Ptr {
 		ptrType := typ.(*reflect2.UnsafePtrType)
-		decoder := typeDecoders[ptrType.Elem().String()]
+		decoder := ctx.registry.getTypeDecoder(ptrType.Elem().String())
 		if decoder != nil {
 			return &OptionalDecoder{ptrType.Elem(), decoder}
 		}
@@ -288,7 +285,7 @@ func _getTypeDecoderFromExtension(ctx *ctx, typ reflect2.Type) ValDecoder {
 func getTypeEncoderFromExtension(ctx *ctx, typ reflect2.Type) ValEncoder {
 	encoder := _getTypeEncoderFromExtension(ctx, typ)
 	if encoder != nil {
-		for _, extension := range extensions {
+		for _, extension := range ctx.registry.getExtensions() {
 			encoder = extension.DecorateEncoder(typ, encoder)
 		}
 		encoder = ctx.encoderExtension.DecorateEncoder(typ, encoder)
@@ -300,7 +297,7 @@ func getTypeEncoderFromExtension(ctx *ctx, typ reflect2.Type) ValEncoder {
 }
 
 func _getTypeEncoderFromExtension(ctx *ctx, typ reflect2.Type) ValEncoder {
-	for _, extension := range extensions {
+	for _, extension := range ctx.registry.getExtensions() {
 		encoder := extension.CreateEncoder(typ)
 		if encoder != nil {
 			return encoder
@@ -317,13 +314,13 @@ func _getTypeEncoderFromExtension(ctx *ctx, typ reflect2.Type) ValEncoder {
 		}
 	}
 	typeName := typ.String()
-	encoder = typeEncoders[typeName]
+	encoder = ctx.registry.getTypeEncoder(typeName)
 	if encoder != nil {
 		return encoder
 	}
 	if typ.Kind() == reflect.Ptr {
 		typePtr := typ.(*reflect2.UnsafePtrType)
-		encoder := typeEncoders[typePtr.Elem().String()]
+		encoder := ctx.registry.getTypeEncoder(typePtr.Elem().String())
 		if encoder != nil {
 			return &OptionalEncoder{encoder}
 		}
@@ -335,6 +332,13 @@ func describeStruct(ctx *ctx, typ reflect2.Type) *StructDescriptor {
 	structType := typ.(*reflect2.UnsafeStructType)
 	embeddedBindings := []*Binding{}
 	bindings := []*Binding{}
+	var inline *inlineMap
+	embedInlineMap := func(field reflect2.StructField, embedded *inlineMap) {
+		// the shallowest inline map is kept, the first one if several are as shallow
+		if embedded != nil && (inline == nil || len(embedded.path)+1 < len(inline.path)) {
+			inline = embedded.embeddedIn(field)
+		}
+	}
 	for i := 0; i < structType.NumField(); i++ {
 		field := structType.Field(i)
 		tag, hastag := field.Tag().Lookup(ctx.getTagKey())
@@ -345,16 +349,30 @@ func describeStruct(ctx *ctx, typ reflect2.Type) *StructDescriptor {
 			continue
 		}
 		tagParts := strings.Split(tag, ",")
-		if field.Anonymous() && (tag == "" || tagParts[0] == "") {
+		isInline := hasFieldTagOption(field, tag, "inline")
+		if (isInline || hasFieldTagOption(field, tag, "extra")) && isInlineMapType(field.Type()) {
+			if inline == nil || len(inline.path) > 1 {
+				inline = newInlineMap(ctx.append(field.Name()), field)
+			}
+			continue
+		}
+		if isInline || field.Anonymous() && (tag == "" || tagParts[0] == "") {
 			if field.Type().Kind() == reflect.Struct {
 				structDescriptor := describeStruct(ctx, field.Type())
 				for _, binding := range structDescriptor.Fields {
 					binding.levels = append([]int{i}, binding.levels...)
-					omitempty := binding.Encoder.(*structFieldEncoder).omitempty
-					binding.Encoder = &structFieldEncoder{field, binding.Encoder, omitempty}
-					binding.Decoder = &structFieldDecoder{field, binding.Decoder}
+					fieldEncoder := binding.Encoder.(*structFieldEncoder)
+					binding.Encoder = &structFieldEncoder{
+						field:        field,
+						fieldEncoder: binding.Encoder,
+						omitempty:    fieldEncoder.omitempty,
+						isZero:       embeddedIsZero(field, fieldEncoder.isZero),
+						defaultValue: fieldEncoder.defaultValue,
+					}
+					binding.Decoder = &structFieldDecoder{field: field, fieldDecoder: binding.Decoder}
 					embeddedBindings = append(embeddedBindings, binding)
 				}
+				embedInlineMap(field, structDescriptor.inlineMap)
 				continue
 			} else if field.Type().Kind() == reflect.Ptr {
 				ptrType := field.Type().(*reflect2.UnsafePtrType)
@@ -362,24 +380,30 @@ func describeStruct(ctx *ctx, typ reflect2.Type) *StructDescriptor {
 					structDescriptor := describeStruct(ctx, ptrType.Elem())
 					for _, binding := range structDescriptor.Fields {
 						binding.levels = append([]int{i}, binding.levels...)
-						omitempty := binding.Encoder.(*structFieldEncoder).omitempty
+						fieldEncoder := binding.Encoder.(*structFieldEncoder)
 						binding.Encoder = &dereferenceEncoder{binding.Encoder}
-						binding.Encoder = &structFieldEncoder{field, binding.Encoder, omitempty}
+						binding.Encoder = &structFieldEncoder{
+							field:        field,
+							fieldEncoder: binding.Encoder,
+							omitempty:    fieldEncoder.omitempty,
+							isZero:       embeddedIsZero(field, fieldEncoder.isZero),
+							defaultValue: fieldEncoder.defaultValue,
+						}
 						binding.Decoder = &dereferenceDecoder{ptrType.Elem(), binding.Decoder}
-						binding.Decoder = &structFieldDecoder{field, binding.Decoder}
+						binding.Decoder = &structFieldDecoder{field: field, fieldDecoder: binding.Decoder}
 						embeddedBindings = append(embeddedBindings, binding)
 					}
+					embedInlineMap(field, structDescriptor.inlineMap)
 					continue
 				}
 			}
 		}
 		fieldNames := calcFieldNames(field.Name(), tagParts[0], tag)
-		fieldCacheKey := fmt.Sprintf("%s/%s", typ.String(), field.Name())
-		decoder := fieldDecoders[fieldCacheKey]
+		decoder := ctx.registry.getFieldDecoder(typ.String(), field.Name())
 		if decoder == nil {
 			decoder = decoderOfType(ctx.append(field.Name()), field.Type())
 		}
-		encoder := fieldEncoders[fieldCacheKey]
+		encoder := ctx.registry.getFieldEncoder(typ.String(), field.Name())
 		if encoder == nil {
 			encoder = encoderOfType(ctx.append(field.Name()), field.Type())
 		}
@@ -393,14 +417,16 @@ func describeStruct(ctx *ctx, typ reflect2.Type) *StructDescriptor {
 		binding.levels = []int{i}
 		bindings = append(bindings, binding)
 	}
-	return createStructDescriptor(ctx, typ, bindings, embeddedBindings)
+	structDescriptor := createStructDescriptor(ctx, typ, bindings, embeddedBindings)
+	structDescriptor.inlineMap = inline
+	return structDescriptor
 }
 func createStructDescriptor(ctx *ctx, typ reflect2.Type, bindings []*Binding, embeddedBindings []*Binding) *StructDescriptor {
 	structDescriptor := &StructDescriptor{
 		Type:   typ,
 		Fields: bindings,
 	}
-	for _, extension := range extensions {
+	for _, extension := range ctx.registry.getExtensions() {
 		extension.UpdateStructDescriptor(structDescriptor)
 	}
 	ctx.encoderExtension.UpdateStructDescriptor(structDescriptor)
@@ -443,11 +469,17 @@ func (bindings sortableBindings) Swap(i, j int) {
 func processTags(structDescriptor *StructDescriptor, cfg *frozenConfig) {
 	for _, binding := range structDescriptor.Fields {
 		shouldOmitEmpty := false
-		tagParts := strings.Split(binding.Field.Tag().Get(cfg.getTagKey()), ",")
-		for _, tagPart := range tagParts[1:] {
-			if tagPart == "omitempty" {
+		var isZero func(ptr unsafe.Pointer) bool
+		tagOptions, defaultValue := parseTagOptions(binding.Field.Tag().Get(cfg.getTagKey()))
+		for _, tagPart := range tagOptions {
+			switch tagPart {
+			case "omitempty":
 				shouldOmitEmpty = true
-			} else if tagPart == "string" {
+			case "omitzero":
+				isZero = fieldIsZero(binding.Field)
+			case "required":
+				binding.required = true
+			case "string":
 				if binding.Field.Type().Kind() == reflect.String {
 					binding.Decoder = &stringModeStringDecoder{binding.Decoder, cfg}
 					binding.Encoder = &stringModeStringEncoder{binding.Encoder, cfg}
@@ -457,9 +489,54 @@ func processTags(structDescriptor *StructDescriptor, cfg *frozenConfig) {
 				}
 			}
 		}
-		binding.Decoder = &structFieldDecoder{binding.Field, binding.Decoder}
-		binding.Encoder = &structFieldEncoder{binding.Field, binding.Encoder, shouldOmitEmpty}
+		binding.defaultValue = defaultValue
+		binding.Decoder = &structFieldDecoder{field: binding.Field, fieldDecoder: binding.Decoder}
+		binding.Encoder = &structFieldEncoder{
+			field:        binding.Field,
+			fieldEncoder: binding.Encoder,
+			omitempty:    shouldOmitEmpty,
+			isZero:       isZero,
+			defaultValue: defaultValue,
+		}
+	}
+}
+
+// parseTagOptions returns the options after the field name of the tag, and the JSON of the default option.
+// The JSON may have commas, the options are also read after the end of its value.
+// If the JSON is invalid, the rest of the tag is the default, for the decoder to report it.
+func parseTagOptions(tag string) ([]string, []byte) {
+	i := strings.Index(tag, ",default=")
+	if i == -1 {
+		return strings.Split(tag, ",")[1:], nil
+	}
+	tagOptions := strings.Split(tag[:i], ",")[1:]
+	defaultValue := []byte(tag[i+len(",default="):])
+	cfg := Config{}.frozeWithCacheReuse(nil)
+	iter := cfg.BorrowIterator(defaultValue)
+	defer cfg.ReturnIterator(iter)
+	iter.Skip()
+	if iter.Error != nil && iter.Error != io.EOF {
+		return tagOptions, defaultValue
+	}
+	end := iter.head
+	if iter.nextToken() != ',' {
+		return tagOptions, defaultValue
+	}
+	return append(tagOptions, strings.Split(string(defaultValue[iter.head:]), ",")...), defaultValue[:end]
+}
+
+// hasFieldTagOption tells if the field has the tag option inline or extra, which unexported fields can not have
+func hasFieldTagOption(field reflect2.StructField, tag string, option string) bool {
+	if unicode.IsLower(rune(field.Name()[0])) || field.Name()[0] == '_' {
+		return false
+	}
+	tagOptions, _ := parseTagOptions(tag)
+	for _, tagPart := range tagOptions {
+		if tagPart == option {
+			return true
+		}
 	}
+	return false
 }
 
 func calcFieldNames(originalFieldName string, tagProvidedFieldName string, wholeTag string) []string {
@@ -0,0 +1,196 @@
+package jsoniter
+
+import (
+	"reflect"
+	"sort"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// inlineMap is the field with the tag option inline or extra, a map with string keys or Any,
+// which gets the members of the object not decoded into a field, and whose entries are encoded as members of the object
+type inlineMap struct {
+	path        []reflect2.StructField  // from the struct to the field, through the embedded and inline structs holding it
+	mapType     *reflect2.UnsafeMapType // nil if the field is Any
+	elemDecoder ValDecoder
+	elemEncoder ValEncoder
+}
+
+// isInlineMapType tells if a field of typ can get the unknown members, which needs string keys or Any
+func isInlineMapType(typ reflect2.Type) bool {
+	if typ == anyType {
+		return true
+	}
+	return typ.Kind() == reflect.Map && typ.(*reflect2.UnsafeMapType).Key().Kind() == reflect.String
+}
+
+func newInlineMap(ctx *ctx, field reflect2.StructField) *inlineMap {
+	if field.Type() == anyType {
+		return &inlineMap{
+			path:        []reflect2.StructField{field},
+			elemDecoder: decoderOfType(ctx, anyType),
+			elemEncoder: encoderOfType(ctx, anyType),
+		}
+	}
+	mapType := field.Type().(*reflect2.UnsafeMapType)
+	return &inlineMap{
+		path:        []reflect2.StructField{field},
+		mapType:     mapType,
+		elemDecoder: decoderOfType(ctx.append("[mapElem]"), mapType.Elem()),
+		elemEncoder: encoderOfType(ctx.append("[mapElem]"), mapType.Elem()),
+	}
+}
+
+// embeddedIn returns the inline map as seen from the struct holding the embedded or inline struct in field
+func (inline *inlineMap) embeddedIn(field reflect2.StructField) *inlineMap {
+	copied := *inline
+	copied.path = append([]reflect2.StructField{field}, inline.path...)
+	return &copied
+}
+
+// fieldPtr returns the pointer to the field in the struct at ptr.
+// The nil pointers to structs on the path are allocated if alloc is set, otherwise nil is returned.
+func (inline *inlineMap) fieldPtr(ptr unsafe.Pointer, alloc bool) unsafe.Pointer {
+	last := len(inline.path) - 1
+	for i, field := range inline.path {
+		ptr = field.UnsafeGet(ptr)
+		if i == last || field.Type().Kind() != reflect.Ptr {
+			continue
+		}
+		if *(*unsafe.Pointer)(ptr) == nil {
+			if !alloc {
+				return nil
+			}
+			*(*unsafe.Pointer)(ptr) = field.Type().(*reflect2.UnsafePtrType).Elem().UnsafeNew()
+		}
+		ptr = *(*unsafe.Pointer)(ptr)
+	}
+	return ptr
+}
+
+// readField reads the name of the member for the struct decoders switching on its hash.
+// The name is only returned if inline is set, as the unknown members are skipped otherwise.
+func (inline *inlineMap) readField(iter *Iterator) (string, int64) {
+	if inline == nil {
+		return "", iter.readFieldHash()
+	}
+	return iter.readFieldNameAndHash()
+}
+
+// decodeUnknown reads the value of the unknown member into the struct at ptr, or skips it if inline is nil
+func (inline *inlineMap) decodeUnknown(ptr unsafe.Pointer, key string, iter *Iterator) {
+	if inline == nil {
+		iter.Skip()
+		return
+	}
+	inline.decodeEntry(ptr, key, iter)
+}
+
+// decodeEntry reads the value of the member key into the field of the struct at ptr
+func (inline *inlineMap) decodeEntry(ptr unsafe.Pointer, key string, iter *Iterator) {
+	fieldPtr := inline.fieldPtr(ptr, true)
+	if inline.mapType == nil {
+		var elem Any
+		inline.elemDecoder.Decode(unsafe.Pointer(&elem), iter)
+		anyMembers((*Any)(fieldPtr))[key] = elem
+		return
+	}
+	if inline.mapType.UnsafeIsNil(fieldPtr) {
+		inline.mapType.UnsafeSet(fieldPtr, inline.mapType.UnsafeMakeMap(0))
+	}
+	// the key is copied to the heap, the map may not keep the memory it was read into
+	keyPtr := inline.mapType.Key().UnsafeNew()
+	*(*string)(keyPtr) = key
+	elem := inline.mapType.Elem().UnsafeNew()
+	inline.elemDecoder.Decode(elem, iter)
+	inline.mapType.UnsafeSetIndex(fieldPtr, keyPtr, elem)
+}
+
+// anyMembers returns the map wrapped by the Any getting the unknown members.
+// Any other value is replaced by the wrapped map, which has the members of the value if it is an object.
+func anyMembers(any *Any) map[string]Any {
+	if *any != nil {
+		if members, isMap := (*any).GetInterface().(map[string]Any); isMap {
+			return members
+		}
+	}
+	members := map[string]Any{}
+	if *any != nil && (*any).ValueType() == ObjectValue {
+		for _, key := range (*any).Keys() {
+			members[key] = (*any).Get(key)
+		}
+	}
+	*any = Wrap(members)
+	return members
+}
+
+// encodeEntries writes the entries of the field of the struct at ptr as members of the object being written,
+// except the ones whose key is in fieldNames, isNotFirst tells if members are written already
+func (inline *inlineMap) encodeEntries(ptr unsafe.Pointer, stream *Stream, isNotFirst bool, fieldNames map[string]bool) {
+	fieldPtr := inline.fieldPtr(ptr, false)
+	if fieldPtr == nil {
+		return
+	}
+	if inline.mapType == nil {
+		inline.encodeAnyEntries(*(*Any)(fieldPtr), stream, isNotFirst, fieldNames)
+		return
+	}
+	if inline.mapType.UnsafeIsNil(fieldPtr) {
+		return
+	}
+	mapIter := inline.mapType.UnsafeIterate(fieldPtr)
+	if !stream.cfg.sortMapKeys {
+		for mapIter.HasNext() {
+			key, elem := mapIter.UnsafeNext()
+			if fieldNames[*(*string)(key)] {
+				continue
+			}
+			inline.encodeEntry(*(*string)(key), elem, stream, isNotFirst)
+			isNotFirst = true
+		}
+		return
+	}
+	keys := []string{}
+	elems := map[string]unsafe.Pointer{}
+	for mapIter.HasNext() {
+		key, elem := mapIter.UnsafeNext()
+		if fieldNames[*(*string)(key)] {
+			continue
+		}
+		keys = append(keys, *(*string)(key))
+		elems[*(*string)(key)] = elem
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		inline.encodeEntry(key, elems[key], stream, isNotFirst)
+		isNotFirst = true
+	}
+}
+
+// encodeAnyEntries writes the members of any except the ones in fieldNames, nothing if it is not an object
+func (inline *inlineMap) encodeAnyEntries(any Any, stream *Stream, isNotFirst bool, fieldNames map[string]bool) {
+	if any == nil || any.ValueType() != ObjectValue {
+		return
+	}
+	keys := any.Keys()
+	if stream.cfg.sortMapKeys {
+		sort.Strings(keys)
+	}
+	for _, key := range keys {
+		if fieldNames[key] {
+			continue
+		}
+		elem := any.Get(key)
+		inline.encodeEntry(key, unsafe.Pointer(&elem), stream, isNotFirst)
+		isNotFirst = true
+	}
+}
+
+func (inline *inlineMap) encodeEntry(key string, elem unsafe.Pointer, stream *Stream, isNotFirst bool) {
+	if isNotFirst {
+		stream.WriteMore()
+	}
+	stream.WriteObjectField(key)
+	inline.elemEncoder.Encode(elem, stream)
+}
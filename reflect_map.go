@@ -163,7 +163,7 @@ func (decoder *mapDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
 		mapType.UnsafeSet(ptr, mapType.UnsafeMakeMap(0))
 	}
 	if c != '{' {
-		iter.ReportError("ReadMapCB", `expect { or n, but found `+string([]byte{c}))
+		iter.reportUnexpectedValue("ReadMapCB", "{ or n", c, mapType.Type1())
 		return
 	}
 	c = iter.nextToken()
@@ -172,21 +172,26 @@ func (decoder *mapDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
 	}
 	iter.unreadByte()
 	key := decoder.keyType.UnsafeNew()
-	decoder.keyDecoder.Decode(key, iter)
+	decoder.decodeKey(key, iter)
 	c = iter.nextToken()
 	if c != ':' {
-		iter.ReportError("ReadMapCB", "expect : after object field, but found "+string([]byte{c}))
+		iter.reportUnexpected("ReadMapCB", ":", c)
 		return
 	}
 	elem := decoder.elemType.UnsafeNew()
 	decoder.elemDecoder.Decode(elem, iter)
 	decoder.mapType.UnsafeSetIndex(ptr, key, elem)
+	count := 1
 	for c = iter.nextToken(); c == ','; c = iter.nextToken() {
+		count++
+		if !iter.checkElements(count) {
+			return
+		}
 		key := decoder.keyType.UnsafeNew()
-		decoder.keyDecoder.Decode(key, iter)
+		decoder.decodeKey(key, iter)
 		c = iter.nextToken()
 		if c != ':' {
-			iter.ReportError("ReadMapCB", "expect : after object field, but found "+string([]byte{c}))
+			iter.reportUnexpected("ReadMapCB", ":", c)
 			return
 		}
 		elem := decoder.elemType.UnsafeNew()
@@ -194,7 +199,36 @@ func (decoder *mapDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
 		decoder.mapType.UnsafeSetIndex(ptr, key, elem)
 	}
 	if c != '}' {
-		iter.ReportError("ReadMapCB", `expect }, but found `+string([]byte{c}))
+		iter.reportUnexpected("ReadMapCB", "}", c)
+	}
+}
+
+// decodeKey decodes the key, which may be single quoted or an identifier with AllowJSON5
+func (decoder *mapDecoder) decodeKey(key unsafe.Pointer, iter *Iterator) {
+	if iter.cfg.allowJSON5 {
+		if c := iter.nextToken(); c != '"' {
+			decoder.decodeJSON5Key(key, iter, c)
+			return
+		}
+		iter.unreadByte()
+	}
+	decoder.keyDecoder.Decode(key, iter)
+}
+
+// decodeJSON5Key passes the key starting with c to the key decoder as JSON string
+func (decoder *mapDecoder) decodeJSON5Key(key unsafe.Pointer, iter *Iterator, c byte) {
+	str := iter.readJSON5Key(c)
+	if iter.Error != nil && iter.Error != io.EOF {
+		return
+	}
+	stream := iter.cfg.BorrowStream(nil)
+	defer iter.cfg.ReturnStream(stream)
+	stream.WriteString(str)
+	keyIter := iter.cfg.BorrowIterator(stream.Buffer())
+	defer iter.cfg.ReturnIterator(keyIter)
+	decoder.keyDecoder.Decode(key, keyIter)
+	if keyIter.Error != nil && keyIter.Error != io.EOF {
+		iter.Error = keyIter.Error
 	}
 }
 
@@ -205,13 +239,13 @@ type numericMapKeyDecoder struct {
 func (decoder *numericMapKeyDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
 	c := iter.nextToken()
 	if c != '"' {
-		iter.ReportError("ReadMapCB", `expect ", but found `+string([]byte{c}))
+		iter.reportUnexpected("ReadMapCB", `"`, c)
 		return
 	}
 	decoder.decoder.Decode(ptr, iter)
 	c = iter.nextToken()
 	if c != '"' {
-		iter.ReportError("ReadMapCB", `expect ", but found `+string([]byte{c}))
+		iter.reportUnexpected("ReadMapCB", `"`, c)
 		return
 	}
 }
@@ -294,6 +328,7 @@ func (encoder *sortKeysMapEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 	mapIter := encoder.mapType.UnsafeIterate(ptr)
 	subStream := stream.cfg.BorrowStream(nil)
 	subStream.Attachment = stream.Attachment
+	subStream.indention = stream.indention
 	subIter := stream.cfg.BorrowIterator(nil)
 	keyValues := encodedKeyValues{}
 	for mapIter.HasNext() {
@@ -322,7 +357,7 @@ func (encoder *sortKeysMapEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 		if i != 0 {
 			stream.WriteMore()
 		}
-		stream.Write(keyValue.keyValue)
+		stream.buf = append(stream.buf, keyValue.keyValue...)
 	}
 	if subStream.Error != nil && stream.Error == nil {
 		stream.Error = subStream.Error
@@ -0,0 +1,223 @@
+package jsoniter
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/modern-go/reflect2"
+)
+
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// SchemaOf describes the JSON produced by encoding typ with this config as JSON Schema draft 2020-12.
+// The schema follows the encoders, so field names, omitempty, omitzero and string options, and extensions are taken into account.
+// Fields omitted when empty or zero are not required, the tag option required only applies to decoding,
+// and the tag option default is described by the default keyword.
+// Values written by custom encoders, marshalers and interfaces are described by the true schema, accepting any value.
+func (cfg *frozenConfig) SchemaOf(typ reflect2.Type) ([]byte, error) {
+	generator := &schemaGenerator{
+		cfg:   cfg,
+		names: map[reflect2.Type]string{},
+		types: map[string]reflect2.Type{},
+		defs:  map[string]interface{}{},
+	}
+	schema, err := generator.schemaOf(cfg.EncoderOf(typ))
+	if err != nil {
+		return nil, err
+	}
+	schema["$schema"] = schemaDialect
+	if len(generator.defs) != 0 {
+		schema["$defs"] = generator.defs
+	}
+	return ConfigCompatibleWithStandardLibrary.Marshal(schema)
+}
+
+type schemaGenerator struct {
+	cfg   *frozenConfig
+	names map[reflect2.Type]string // $defs name of named struct types
+	types map[string]reflect2.Type
+	defs  map[string]interface{}
+}
+
+func (generator *schemaGenerator) schemaOf(encoder ValEncoder) (map[string]interface{}, error) {
+	switch encoder := encoder.(type) {
+	case *placeholderEncoder:
+		return generator.schemaOf(encoder.encoder)
+	case *onePtrEncoder:
+		return generator.schemaOf(encoder.encoder)
+	case *referenceEncoder:
+		return generator.schemaOf(encoder.encoder)
+	case *dereferenceEncoder:
+		return generator.schemaOf(encoder.ValueEncoder)
+	case *OptionalEncoder:
+		schema, err := generator.schemaOf(encoder.ValueEncoder)
+		return nullable(schema), err
+	case *stringCodec, *htmlEscapedStringEncoder:
+		return map[string]interface{}{"type": "string"}, nil
+	case *boolCodec:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case *int8Codec, *int16Codec, *int32Codec, *int64Codec:
+		return map[string]interface{}{"type": "integer"}, nil
+	case *uint8Codec, *uint16Codec, *uint32Codec, *uint64Codec:
+		return map[string]interface{}{"type": "integer", "minimum": 0}, nil
+	case *float32Codec, *float64Codec, *lossyFloat32Encoder, *lossyFloat64Encoder,
+		*jsonNumberCodec, *jsoniterNumberCodec:
+		return map[string]interface{}{"type": "number"}, nil
+	case *base64Codec:
+		return map[string]interface{}{"type": []string{"string", "null"}, "contentEncoding": "base64"}, nil
+	case *textMarshalerEncoder:
+		schema := map[string]interface{}{"type": "string"}
+		if encoder.valType.LikePtr() {
+			schema = nullable(schema)
+		}
+		return schema, nil
+	case *directTextMarshalerEncoder:
+		return nullable(map[string]interface{}{"type": "string"}), nil
+	case *emptyArrayEncoder:
+		return map[string]interface{}{"type": "array", "maxItems": 0}, nil
+	case *sliceEncoder:
+		items, err := generator.schemaOf(encoder.elemEncoder)
+		return map[string]interface{}{"type": []string{"array", "null"}, "items": items}, err
+	case *arrayEncoder:
+		items, err := generator.schemaOf(encoder.elemEncoder)
+		length := encoder.arrayType.Len()
+		return map[string]interface{}{"type": "array", "items": items, "minItems": length, "maxItems": length}, err
+	case *mapEncoder:
+		return generator.schemaOfMap(encoder.mapType, encoder.keyEncoder, encoder.elemEncoder)
+	case *sortKeysMapEncoder:
+		return generator.schemaOfMap(encoder.mapType, encoder.keyEncoder, encoder.elemEncoder)
+	case *emptyStructEncoder:
+		return map[string]interface{}{"type": "object", "maxProperties": 0}, nil
+	case *structEncoder:
+		return generator.schemaOfStruct(encoder)
+	case *lazyErrorEncoder:
+		return nil, encoder.err
+	}
+	// custom encoders, marshalers, raw messages and interfaces can write any value
+	return map[string]interface{}{}, nil
+}
+
+func (generator *schemaGenerator) schemaOfMap(mapType *reflect2.UnsafeMapType, keyEncoder ValEncoder, elemEncoder ValEncoder) (map[string]interface{}, error) {
+	if err, isErr := keyEncoder.(*lazyErrorEncoder); isErr {
+		return nil, err.err
+	}
+	additionalProperties, err := generator.schemaOf(elemEncoder)
+	schema := map[string]interface{}{"type": []string{"object", "null"}, "additionalProperties": additionalProperties}
+	if _, isNumeric := keyEncoder.(*numericMapKeyEncoder); isNumeric {
+		switch mapType.Key().Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			schema["propertyNames"] = map[string]interface{}{"pattern": "^-?[0-9]+$"}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			schema["propertyNames"] = map[string]interface{}{"pattern": "^[0-9]+$"}
+		}
+	}
+	return schema, err
+}
+
+// schemaOfStruct defines named struct in $defs and refers to it, so recursive types terminate
+func (generator *schemaGenerator) schemaOfStruct(encoder *structEncoder) (map[string]interface{}, error) {
+	if encoder.typ.Type1().Name() == "" {
+		return generator.objectOfStruct(encoder)
+	}
+	name, found := generator.names[encoder.typ]
+	if !found {
+		name = encoder.typ.String()
+		for i := 2; generator.types[name] != nil; i++ {
+			name = encoder.typ.String() + strconv.Itoa(i)
+		}
+		generator.names[encoder.typ] = name
+		generator.types[name] = encoder.typ
+		generator.defs[name] = map[string]interface{}{}
+		schema, err := generator.objectOfStruct(encoder)
+		if err != nil {
+			return nil, err
+		}
+		generator.defs[name] = schema
+	}
+	return map[string]interface{}{"$ref": "#/$defs/" + escapePointerToken(name)}, nil
+}
+
+func (generator *schemaGenerator) objectOfStruct(encoder *structEncoder) (map[string]interface{}, error) {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for _, field := range encoder.fields {
+		property, err := generator.schemaOfField(field.encoder.fieldEncoder)
+		if err != nil {
+			return nil, fmt.Errorf("%v.%s: %s", encoder.typ, field.encoder.field.Name(), err.Error())
+		}
+		if field.encoder.defaultValue != nil {
+			if err := generator.cfg.Unmarshal(field.encoder.defaultValue, &RawMessage{}); err != nil {
+				return nil, fmt.Errorf("%v.%s: invalid default: %s", encoder.typ, field.encoder.field.Name(), err.Error())
+			}
+			property["default"] = RawMessage(field.encoder.defaultValue)
+		}
+		properties[field.toName] = property
+		if !field.encoder.omitempty && field.encoder.isZero == nil && !isEmbeddedThroughPtr(field.encoder.fieldEncoder) {
+			required = append(required, field.toName)
+		}
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) != 0 {
+		schema["required"] = required
+	}
+	if encoder.inlineMap != nil {
+		// the entries of the inline map are written as members besides the fields
+		additionalProperties, err := generator.schemaOf(encoder.inlineMap.elemEncoder)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %s", encoder.typ, err.Error())
+		}
+		schema["additionalProperties"] = additionalProperties
+	} else if generator.cfg.disallowUnknownFields {
+		schema["additionalProperties"] = false
+	}
+	return schema, nil
+}
+
+func (generator *schemaGenerator) schemaOfField(encoder ValEncoder) (map[string]interface{}, error) {
+	switch encoder := encoder.(type) {
+	case *structFieldEncoder:
+		// field promoted from embedded struct
+		return generator.schemaOfField(encoder.fieldEncoder)
+	case *dereferenceEncoder:
+		return generator.schemaOfField(encoder.ValueEncoder)
+	case *stringModeNumberEncoder, *stringModeStringEncoder:
+		return map[string]interface{}{"type": "string"}, nil
+	}
+	return generator.schemaOf(encoder)
+}
+
+// isEmbeddedThroughPtr tells if the field is promoted from embedded pointer, omitted when the pointer is nil
+func isEmbeddedThroughPtr(encoder ValEncoder) bool {
+	for {
+		switch fieldEncoder := encoder.(type) {
+		case *structFieldEncoder:
+			encoder = fieldEncoder.fieldEncoder
+		case *dereferenceEncoder:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// nullable allows null in addition to the values of schema
+func nullable(schema map[string]interface{}) map[string]interface{} {
+	switch typ := schema["type"].(type) {
+	case string:
+		schema["type"] = []string{typ, "null"}
+		return schema
+	case []string:
+		for _, name := range typ {
+			if name == "null" {
+				return schema
+			}
+		}
+		schema["type"] = append(typ, "null")
+		return schema
+	}
+	if len(schema) == 0 {
+		return schema
+	}
+	return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+}
@@ -42,7 +42,7 @@ func (encoder *sliceEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 		encoder.elemEncoder.Encode(elemPtr, stream)
 	}
 	stream.WriteArrayEnd()
-	if stream.Error != nil && stream.Error != io.EOF {
+	if stream.hasValueError() {
 		stream.Error = fmt.Errorf("%v: %s", encoder.sliceType, stream.Error.Error())
 	}
 }
@@ -58,7 +58,7 @@ type sliceDecoder struct {
 
 func (decoder *sliceDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
 	decoder.doDecode(ptr, iter)
-	if iter.Error != nil && iter.Error != io.EOF {
+	if iter.Error != nil && iter.Error != io.EOF && !iter.isPathError() {
 		iter.Error = fmt.Errorf("%v: %s", decoder.sliceType, iter.Error.Error())
 	}
 }
@@ -72,7 +72,7 @@ func (decoder *sliceDecoder) doDecode(ptr unsafe.Pointer, iter *Iterator) {
 		return
 	}
 	if c != '[' {
-		iter.ReportError("decode slice", "expect [ or n, but found "+string([]byte{c}))
+		iter.reportUnexpectedValue("decode slice", "[ or n", c, sliceType.Type1())
 		return
 	}
 	c = iter.nextToken()
@@ -81,19 +81,41 @@ func (decoder *sliceDecoder) doDecode(ptr unsafe.Pointer, iter *Iterator) {
 		return
 	}
 	iter.unreadByte()
+	if !iter.incrementDepth() {
+		return
+	}
+	decoder.decodeElements(ptr, iter)
+	iter.decrementDepth()
+}
+
+// decodeElements decodes the elements of the array, which is not empty
+func (decoder *sliceDecoder) decodeElements(ptr unsafe.Pointer, iter *Iterator) {
+	sliceType := decoder.sliceType
 	sliceType.UnsafeGrow(ptr, 1)
 	elemPtr := sliceType.UnsafeGetIndex(ptr, 0)
 	decoder.elemDecoder.Decode(elemPtr, iter)
+	if iter.Error != nil && iter.Error != io.EOF {
+		iter.addErrorIndex(0, sliceType.Elem().Type1())
+		return
+	}
 	length := 1
+	var c byte
 	for c = iter.nextToken(); c == ','; c = iter.nextToken() {
 		idx := length
 		length += 1
+		if !iter.checkElements(length) {
+			return
+		}
 		sliceType.UnsafeGrow(ptr, length)
 		elemPtr = sliceType.UnsafeGetIndex(ptr, idx)
 		decoder.elemDecoder.Decode(elemPtr, iter)
+		if iter.Error != nil && iter.Error != io.EOF {
+			iter.addErrorIndex(idx, sliceType.Elem().Type1())
+			return
+		}
 	}
 	if c != ']' {
-		iter.ReportError("decode slice", "expect ], but found "+string([]byte{c}))
+		iter.reportUnexpected("decode slice", "]", c)
 		return
 	}
 }
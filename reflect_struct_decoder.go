@@ -3,6 +3,7 @@ package jsoniter
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"unsafe"
 
@@ -10,6 +11,29 @@ import (
 )
 
 func decoderOfStruct(ctx *ctx, typ reflect2.Type) ValDecoder {
+	bindings, inline := structFieldBindings(ctx, typ)
+	checked := newCheckedStructDecoder(typ, bindings)
+	fields := fieldDecodersOf(ctx, bindings, checked)
+	decoder := createStructDecoder(ctx, typ, fields, inline)
+	if checked == nil {
+		return decoder
+	}
+	checked.structDecoder = decoder
+	if ctx.checkedDecoders != nil {
+		*ctx.checkedDecoders = append(*ctx.checkedDecoders, checked)
+	}
+	return checked
+}
+
+// structFieldDecoders maps the JSON field names to their decoders,
+// lower cased names are included unless the config is case sensitive
+func structFieldDecoders(ctx *ctx, typ reflect2.Type) map[string]*structFieldDecoder {
+	bindings, _ := structFieldBindings(ctx, typ)
+	return fieldDecodersOf(ctx, bindings, nil)
+}
+
+// structFieldBindings maps the JSON field names to the bindings decoding them, and returns the inline map if any
+func structFieldBindings(ctx *ctx, typ reflect2.Type) (map[string]*Binding, *inlineMap) {
 	bindings := map[string]*Binding{}
 	structDescriptor := describeStruct(ctx, typ)
 	for _, binding := range structDescriptor.Fields {
@@ -28,25 +52,28 @@ func decoderOfStruct(ctx *ctx, typ reflect2.Type) ValDecoder {
 			}
 		}
 	}
+	return bindings, structDescriptor.inlineMap
+}
+
+func fieldDecodersOf(ctx *ctx, bindings map[string]*Binding, checked *checkedStructDecoder) map[string]*structFieldDecoder {
 	fields := map[string]*structFieldDecoder{}
 	for k, binding := range bindings {
-		fields[k] = binding.Decoder.(*structFieldDecoder)
+		fields[k] = checked.fieldDecoderOf(binding)
 	}
 
 	if !ctx.caseSensitive() {
 		for k, binding := range bindings {
 			if _, found := fields[strings.ToLower(k)]; !found {
-				fields[strings.ToLower(k)] = binding.Decoder.(*structFieldDecoder)
+				fields[strings.ToLower(k)] = checked.fieldDecoderOf(binding)
 			}
 		}
 	}
-
-	return createStructDecoder(ctx, typ, fields)
+	return fields
 }
 
-func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structFieldDecoder) ValDecoder {
+func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structFieldDecoder, inline *inlineMap) ValDecoder {
 	if ctx.disallowUnknownFields {
-		return &generalStructDecoder{typ: typ, fields: fields, disallowUnknownFields: true}
+		return &generalStructDecoder{typ: typ, fields: fields, disallowUnknownFields: true, inlineMap: inline}
 	}
 	knownHash := map[int64]struct{}{
 		0: {},
@@ -54,16 +81,19 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 
 	switch len(fields) {
 	case 0:
+		if inline != nil {
+			return &generalStructDecoder{typ: typ, fields: fields, inlineMap: inline}
+		}
 		return &skipObjectDecoder{typ}
 	case 1:
 		for fieldName, fieldDecoder := range fields {
 			fieldHash := calcHash(fieldName, ctx.caseSensitive())
 			_, known := knownHash[fieldHash]
 			if known {
-				return &generalStructDecoder{typ, fields, false}
+				return &generalStructDecoder{typ: typ, fields: fields, inlineMap: inline}
 			}
 			knownHash[fieldHash] = struct{}{}
-			return &oneFieldStructDecoder{typ, fieldHash, fieldDecoder}
+			return &oneFieldStructDecoder{typ, fieldHash, fieldDecoder, inline}
 		}
 	case 2:
 		var fieldHash1 int64
@@ -74,7 +104,7 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldHash := calcHash(fieldName, ctx.caseSensitive())
 			_, known := knownHash[fieldHash]
 			if known {
-				return &generalStructDecoder{typ, fields, false}
+				return &generalStructDecoder{typ: typ, fields: fields, inlineMap: inline}
 			}
 			knownHash[fieldHash] = struct{}{}
 			if fieldHash1 == 0 {
@@ -85,7 +115,7 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 				fieldDecoder2 = fieldDecoder
 			}
 		}
-		return &twoFieldsStructDecoder{typ, fieldHash1, fieldDecoder1, fieldHash2, fieldDecoder2}
+		return &twoFieldsStructDecoder{typ, fieldHash1, fieldDecoder1, fieldHash2, fieldDecoder2, inline}
 	case 3:
 		var fieldName1 int64
 		var fieldName2 int64
@@ -97,7 +127,7 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldHash := calcHash(fieldName, ctx.caseSensitive())
 			_, known := knownHash[fieldHash]
 			if known {
-				return &generalStructDecoder{typ, fields, false}
+				return &generalStructDecoder{typ: typ, fields: fields, inlineMap: inline}
 			}
 			knownHash[fieldHash] = struct{}{}
 			if fieldName1 == 0 {
@@ -114,7 +144,8 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 		return &threeFieldsStructDecoder{typ,
 			fieldName1, fieldDecoder1,
 			fieldName2, fieldDecoder2,
-			fieldName3, fieldDecoder3}
+			fieldName3, fieldDecoder3,
+			inline}
 	case 4:
 		var fieldName1 int64
 		var fieldName2 int64
@@ -128,7 +159,7 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldHash := calcHash(fieldName, ctx.caseSensitive())
 			_, known := knownHash[fieldHash]
 			if known {
-				return &generalStructDecoder{typ, fields, false}
+				return &generalStructDecoder{typ: typ, fields: fields, inlineMap: inline}
 			}
 			knownHash[fieldHash] = struct{}{}
 			if fieldName1 == 0 {
@@ -149,7 +180,8 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldName1, fieldDecoder1,
 			fieldName2, fieldDecoder2,
 			fieldName3, fieldDecoder3,
-			fieldName4, fieldDecoder4}
+			fieldName4, fieldDecoder4,
+			inline}
 	case 5:
 		var fieldName1 int64
 		var fieldName2 int64
@@ -165,7 +197,7 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldHash := calcHash(fieldName, ctx.caseSensitive())
 			_, known := knownHash[fieldHash]
 			if known {
-				return &generalStructDecoder{typ, fields, false}
+				return &generalStructDecoder{typ: typ, fields: fields, inlineMap: inline}
 			}
 			knownHash[fieldHash] = struct{}{}
 			if fieldName1 == 0 {
@@ -190,7 +222,8 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldName2, fieldDecoder2,
 			fieldName3, fieldDecoder3,
 			fieldName4, fieldDecoder4,
-			fieldName5, fieldDecoder5}
+			fieldName5, fieldDecoder5,
+			inline}
 	case 6:
 		var fieldName1 int64
 		var fieldName2 int64
@@ -208,7 +241,7 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldHash := calcHash(fieldName, ctx.caseSensitive())
 			_, known := knownHash[fieldHash]
 			if known {
-				return &generalStructDecoder{typ, fields, false}
+				return &generalStructDecoder{typ: typ, fields: fields, inlineMap: inline}
 			}
 			knownHash[fieldHash] = struct{}{}
 			if fieldName1 == 0 {
@@ -237,7 +270,8 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldName3, fieldDecoder3,
 			fieldName4, fieldDecoder4,
 			fieldName5, fieldDecoder5,
-			fieldName6, fieldDecoder6}
+			fieldName6, fieldDecoder6,
+			inline}
 	case 7:
 		var fieldName1 int64
 		var fieldName2 int64
@@ -257,7 +291,7 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldHash := calcHash(fieldName, ctx.caseSensitive())
 			_, known := knownHash[fieldHash]
 			if known {
-				return &generalStructDecoder{typ, fields, false}
+				return &generalStructDecoder{typ: typ, fields: fields, inlineMap: inline}
 			}
 			knownHash[fieldHash] = struct{}{}
 			if fieldName1 == 0 {
@@ -290,7 +324,8 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldName4, fieldDecoder4,
 			fieldName5, fieldDecoder5,
 			fieldName6, fieldDecoder6,
-			fieldName7, fieldDecoder7}
+			fieldName7, fieldDecoder7,
+			inline}
 	case 8:
 		var fieldName1 int64
 		var fieldName2 int64
@@ -312,7 +347,7 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldHash := calcHash(fieldName, ctx.caseSensitive())
 			_, known := knownHash[fieldHash]
 			if known {
-				return &generalStructDecoder{typ, fields, false}
+				return &generalStructDecoder{typ: typ, fields: fields, inlineMap: inline}
 			}
 			knownHash[fieldHash] = struct{}{}
 			if fieldName1 == 0 {
@@ -349,7 +384,8 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldName5, fieldDecoder5,
 			fieldName6, fieldDecoder6,
 			fieldName7, fieldDecoder7,
-			fieldName8, fieldDecoder8}
+			fieldName8, fieldDecoder8,
+			inline}
 	case 9:
 		var fieldName1 int64
 		var fieldName2 int64
@@ -373,7 +409,7 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldHash := calcHash(fieldName, ctx.caseSensitive())
 			_, known := knownHash[fieldHash]
 			if known {
-				return &generalStructDecoder{typ, fields, false}
+				return &generalStructDecoder{typ: typ, fields: fields, inlineMap: inline}
 			}
 			knownHash[fieldHash] = struct{}{}
 			if fieldName1 == 0 {
@@ -414,7 +450,8 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldName6, fieldDecoder6,
 			fieldName7, fieldDecoder7,
 			fieldName8, fieldDecoder8,
-			fieldName9, fieldDecoder9}
+			fieldName9, fieldDecoder9,
+			inline}
 	case 10:
 		var fieldName1 int64
 		var fieldName2 int64
@@ -440,7 +477,7 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldHash := calcHash(fieldName, ctx.caseSensitive())
 			_, known := knownHash[fieldHash]
 			if known {
-				return &generalStructDecoder{typ, fields, false}
+				return &generalStructDecoder{typ: typ, fields: fields, inlineMap: inline}
 			}
 			knownHash[fieldHash] = struct{}{}
 			if fieldName1 == 0 {
@@ -485,15 +522,17 @@ func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structF
 			fieldName7, fieldDecoder7,
 			fieldName8, fieldDecoder8,
 			fieldName9, fieldDecoder9,
-			fieldName10, fieldDecoder10}
+			fieldName10, fieldDecoder10,
+			inline}
 	}
-	return &generalStructDecoder{typ, fields, false}
+	return &generalStructDecoder{typ: typ, fields: fields, inlineMap: inline}
 }
 
 type generalStructDecoder struct {
 	typ                   reflect2.Type
 	fields                map[string]*structFieldDecoder
 	disallowUnknownFields bool
+	inlineMap             *inlineMap // gets the unknown fields if set
 }
 
 func (decoder *generalStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
@@ -507,31 +546,40 @@ func (decoder *generalStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator)
 	for c = ','; c == ','; c = iter.nextToken() {
 		decoder.decodeOneField(ptr, iter)
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
+	if iter.Error != nil && iter.Error != io.EOF && !iter.addErrorStruct(decoder.typ) && len(decoder.typ.Type1().Name()) != 0 {
 		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
 	}
 	if c != '}' {
-		iter.ReportError("struct Decode", `expect }, but found `+string([]byte{c}))
+		iter.reportUnexpected("struct Decode", "}", c)
 	}
 	iter.decrementDepth()
 }
 
 func (decoder *generalStructDecoder) decodeOneField(ptr unsafe.Pointer, iter *Iterator) {
 	var field string
-	var fieldDecoder *structFieldDecoder
-	if iter.cfg.objectFieldMustBeSimpleString {
-		fieldBytes := iter.ReadStringAsSlice()
+	var fieldBytes []byte
+	if iter.cfg.allowJSON5 {
+		field = iter.readJSON5Key(iter.nextToken())
+	} else if iter.cfg.objectFieldMustBeSimpleString {
+		fieldBytes = iter.ReadStringAsSlice()
 		field = *(*string)(unsafe.Pointer(&fieldBytes))
-		fieldDecoder = decoder.fields[field]
-		if fieldDecoder == nil && !iter.cfg.caseSensitive {
-			fieldDecoder = decoder.fields[strings.ToLower(field)]
-		}
 	} else {
 		field = iter.ReadString()
-		fieldDecoder = decoder.fields[field]
-		if fieldDecoder == nil && !iter.cfg.caseSensitive {
-			fieldDecoder = decoder.fields[strings.ToLower(field)]
+	}
+	fieldDecoder := decoder.fields[field]
+	if fieldDecoder == nil && !iter.cfg.caseSensitive {
+		fieldDecoder = decoder.fields[strings.ToLower(field)]
+	}
+	if fieldDecoder == nil && decoder.inlineMap != nil {
+		c := iter.nextToken()
+		if c != ':' {
+			iter.reportUnexpected("ReadObject", ":", c)
 		}
+		if fieldBytes != nil {
+			field = string(fieldBytes) // the map keeps the key, which must not share the buffer
+		}
+		decoder.inlineMap.decodeEntry(ptr, field, iter)
+		return
 	}
 	if fieldDecoder == nil {
 		if decoder.disallowUnknownFields {
@@ -540,14 +588,14 @@ func (decoder *generalStructDecoder) decodeOneField(ptr unsafe.Pointer, iter *It
 		}
 		c := iter.nextToken()
 		if c != ':' {
-			iter.ReportError("ReadObject", "expect : after object field, but found "+string([]byte{c}))
+			iter.reportUnexpected("ReadObject", ":", c)
 		}
 		iter.Skip()
 		return
 	}
 	c := iter.nextToken()
 	if c != ':' {
-		iter.ReportError("ReadObject", "expect : after object field, but found "+string([]byte{c}))
+		iter.reportUnexpected("ReadObject", ":", c)
 	}
 	fieldDecoder.Decode(ptr, iter)
 }
@@ -569,6 +617,7 @@ type oneFieldStructDecoder struct {
 	typ          reflect2.Type
 	fieldHash    int64
 	fieldDecoder *structFieldDecoder
+	inlineMap    *inlineMap
 }
 
 func (decoder *oneFieldStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
@@ -579,16 +628,17 @@ func (decoder *oneFieldStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator)
 		return
 	}
 	for {
-		if iter.readFieldHash() == decoder.fieldHash {
+		field, hash := decoder.inlineMap.readField(iter)
+		if hash == decoder.fieldHash {
 			decoder.fieldDecoder.Decode(ptr, iter)
 		} else {
-			iter.Skip()
+			decoder.inlineMap.decodeUnknown(ptr, field, iter)
 		}
 		if iter.isObjectEnd() {
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
+	if iter.Error != nil && iter.Error != io.EOF && !iter.addErrorStruct(decoder.typ) && len(decoder.typ.Type1().Name()) != 0 {
 		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
 	}
 	iter.decrementDepth()
@@ -600,6 +650,7 @@ type twoFieldsStructDecoder struct {
 	fieldDecoder1 *structFieldDecoder
 	fieldHash2    int64
 	fieldDecoder2 *structFieldDecoder
+	inlineMap     *inlineMap
 }
 
 func (decoder *twoFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
@@ -610,19 +661,20 @@ func (decoder *twoFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator
 		return
 	}
 	for {
-		switch iter.readFieldHash() {
+		field, hash := decoder.inlineMap.readField(iter)
+		switch hash {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
 		case decoder.fieldHash2:
 			decoder.fieldDecoder2.Decode(ptr, iter)
 		default:
-			iter.Skip()
+			decoder.inlineMap.decodeUnknown(ptr, field, iter)
 		}
 		if iter.isObjectEnd() {
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
+	if iter.Error != nil && iter.Error != io.EOF && !iter.addErrorStruct(decoder.typ) && len(decoder.typ.Type1().Name()) != 0 {
 		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
 	}
 	iter.decrementDepth()
@@ -636,6 +688,7 @@ type threeFieldsStructDecoder struct {
 	fieldDecoder2 *structFieldDecoder
 	fieldHash3    int64
 	fieldDecoder3 *structFieldDecoder
+	inlineMap     *inlineMap
 }
 
 func (decoder *threeFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
@@ -646,7 +699,8 @@ func (decoder *threeFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterat
 		return
 	}
 	for {
-		switch iter.readFieldHash() {
+		field, hash := decoder.inlineMap.readField(iter)
+		switch hash {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
 		case decoder.fieldHash2:
@@ -654,13 +708,13 @@ func (decoder *threeFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterat
 		case decoder.fieldHash3:
 			decoder.fieldDecoder3.Decode(ptr, iter)
 		default:
-			iter.Skip()
+			decoder.inlineMap.decodeUnknown(ptr, field, iter)
 		}
 		if iter.isObjectEnd() {
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
+	if iter.Error != nil && iter.Error != io.EOF && !iter.addErrorStruct(decoder.typ) && len(decoder.typ.Type1().Name()) != 0 {
 		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
 	}
 	iter.decrementDepth()
@@ -676,6 +730,7 @@ type fourFieldsStructDecoder struct {
 	fieldDecoder3 *structFieldDecoder
 	fieldHash4    int64
 	fieldDecoder4 *structFieldDecoder
+	inlineMap     *inlineMap
 }
 
 func (decoder *fourFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
@@ -686,7 +741,8 @@ func (decoder *fourFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterato
 		return
 	}
 	for {
-		switch iter.readFieldHash() {
+		field, hash := decoder.inlineMap.readField(iter)
+		switch hash {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
 		case decoder.fieldHash2:
@@ -696,13 +752,13 @@ func (decoder *fourFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterato
 		case decoder.fieldHash4:
 			decoder.fieldDecoder4.Decode(ptr, iter)
 		default:
-			iter.Skip()
+			decoder.inlineMap.decodeUnknown(ptr, field, iter)
 		}
 		if iter.isObjectEnd() {
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
+	if iter.Error != nil && iter.Error != io.EOF && !iter.addErrorStruct(decoder.typ) && len(decoder.typ.Type1().Name()) != 0 {
 		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
 	}
 	iter.decrementDepth()
@@ -720,6 +776,7 @@ type fiveFieldsStructDecoder struct {
 	fieldDecoder4 *structFieldDecoder
 	fieldHash5    int64
 	fieldDecoder5 *structFieldDecoder
+	inlineMap     *inlineMap
 }
 
 func (decoder *fiveFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
@@ -730,7 +787,8 @@ func (decoder *fiveFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterato
 		return
 	}
 	for {
-		switch iter.readFieldHash() {
+		field, hash := decoder.inlineMap.readField(iter)
+		switch hash {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
 		case decoder.fieldHash2:
@@ -742,13 +800,13 @@ func (decoder *fiveFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterato
 		case decoder.fieldHash5:
 			decoder.fieldDecoder5.Decode(ptr, iter)
 		default:
-			iter.Skip()
+			decoder.inlineMap.decodeUnknown(ptr, field, iter)
 		}
 		if iter.isObjectEnd() {
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
+	if iter.Error != nil && iter.Error != io.EOF && !iter.addErrorStruct(decoder.typ) && len(decoder.typ.Type1().Name()) != 0 {
 		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
 	}
 	iter.decrementDepth()
@@ -768,6 +826,7 @@ type sixFieldsStructDecoder struct {
 	fieldDecoder5 *structFieldDecoder
 	fieldHash6    int64
 	fieldDecoder6 *structFieldDecoder
+	inlineMap     *inlineMap
 }
 
 func (decoder *sixFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
@@ -778,7 +837,8 @@ func (decoder *sixFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator
 		return
 	}
 	for {
-		switch iter.readFieldHash() {
+		field, hash := decoder.inlineMap.readField(iter)
+		switch hash {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
 		case decoder.fieldHash2:
@@ -792,13 +852,13 @@ func (decoder *sixFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator
 		case decoder.fieldHash6:
 			decoder.fieldDecoder6.Decode(ptr, iter)
 		default:
-			iter.Skip()
+			decoder.inlineMap.decodeUnknown(ptr, field, iter)
 		}
 		if iter.isObjectEnd() {
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
+	if iter.Error != nil && iter.Error != io.EOF && !iter.addErrorStruct(decoder.typ) && len(decoder.typ.Type1().Name()) != 0 {
 		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
 	}
 	iter.decrementDepth()
@@ -820,6 +880,7 @@ type sevenFieldsStructDecoder struct {
 	fieldDecoder6 *structFieldDecoder
 	fieldHash7    int64
 	fieldDecoder7 *structFieldDecoder
+	inlineMap     *inlineMap
 }
 
 func (decoder *sevenFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
@@ -830,7 +891,8 @@ func (decoder *sevenFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterat
 		return
 	}
 	for {
-		switch iter.readFieldHash() {
+		field, hash := decoder.inlineMap.readField(iter)
+		switch hash {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
 		case decoder.fieldHash2:
@@ -846,13 +908,13 @@ func (decoder *sevenFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterat
 		case decoder.fieldHash7:
 			decoder.fieldDecoder7.Decode(ptr, iter)
 		default:
-			iter.Skip()
+			decoder.inlineMap.decodeUnknown(ptr, field, iter)
 		}
 		if iter.isObjectEnd() {
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
+	if iter.Error != nil && iter.Error != io.EOF && !iter.addErrorStruct(decoder.typ) && len(decoder.typ.Type1().Name()) != 0 {
 		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
 	}
 	iter.decrementDepth()
@@ -876,6 +938,7 @@ type eightFieldsStructDecoder struct {
 	fieldDecoder7 *structFieldDecoder
 	fieldHash8    int64
 	fieldDecoder8 *structFieldDecoder
+	inlineMap     *inlineMap
 }
 
 func (decoder *eightFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
@@ -886,7 +949,8 @@ func (decoder *eightFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterat
 		return
 	}
 	for {
-		switch iter.readFieldHash() {
+		field, hash := decoder.inlineMap.readField(iter)
+		switch hash {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
 		case decoder.fieldHash2:
@@ -904,13 +968,13 @@ func (decoder *eightFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterat
 		case decoder.fieldHash8:
 			decoder.fieldDecoder8.Decode(ptr, iter)
 		default:
-			iter.Skip()
+			decoder.inlineMap.decodeUnknown(ptr, field, iter)
 		}
 		if iter.isObjectEnd() {
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
+	if iter.Error != nil && iter.Error != io.EOF && !iter.addErrorStruct(decoder.typ) && len(decoder.typ.Type1().Name()) != 0 {
 		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
 	}
 	iter.decrementDepth()
@@ -936,6 +1000,7 @@ type nineFieldsStructDecoder struct {
 	fieldDecoder8 *structFieldDecoder
 	fieldHash9    int64
 	fieldDecoder9 *structFieldDecoder
+	inlineMap     *inlineMap
 }
 
 func (decoder *nineFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
@@ -946,7 +1011,8 @@ func (decoder *nineFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterato
 		return
 	}
 	for {
-		switch iter.readFieldHash() {
+		field, hash := decoder.inlineMap.readField(iter)
+		switch hash {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
 		case decoder.fieldHash2:
@@ -966,13 +1032,13 @@ func (decoder *nineFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterato
 		case decoder.fieldHash9:
 			decoder.fieldDecoder9.Decode(ptr, iter)
 		default:
-			iter.Skip()
+			decoder.inlineMap.decodeUnknown(ptr, field, iter)
 		}
 		if iter.isObjectEnd() {
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
+	if iter.Error != nil && iter.Error != io.EOF && !iter.addErrorStruct(decoder.typ) && len(decoder.typ.Type1().Name()) != 0 {
 		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
 	}
 	iter.decrementDepth()
@@ -1000,6 +1066,7 @@ type tenFieldsStructDecoder struct {
 	fieldDecoder9  *structFieldDecoder
 	fieldHash10    int64
 	fieldDecoder10 *structFieldDecoder
+	inlineMap      *inlineMap
 }
 
 func (decoder *tenFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
@@ -1010,7 +1077,8 @@ func (decoder *tenFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator
 		return
 	}
 	for {
-		switch iter.readFieldHash() {
+		field, hash := decoder.inlineMap.readField(iter)
+		switch hash {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
 		case decoder.fieldHash2:
@@ -1032,13 +1100,13 @@ func (decoder *tenFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator
 		case decoder.fieldHash10:
 			decoder.fieldDecoder10.Decode(ptr, iter)
 		default:
-			iter.Skip()
+			decoder.inlineMap.decodeUnknown(ptr, field, iter)
 		}
 		if iter.isObjectEnd() {
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
+	if iter.Error != nil && iter.Error != io.EOF && !iter.addErrorStruct(decoder.typ) && len(decoder.typ.Type1().Name()) != 0 {
 		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
 	}
 	iter.decrementDepth()
@@ -1047,16 +1115,126 @@ func (decoder *tenFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator
 type structFieldDecoder struct {
 	field        reflect2.StructField
 	fieldDecoder ValDecoder
+	checked      *checkedStructDecoder // marks the field read for it, if the field is required or has default
+	checkedIndex int
 }
 
 func (decoder *structFieldDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	if decoder.checked != nil && iter.fieldsRead != nil && iter.fieldsRead.decoder == decoder.checked {
+		iter.fieldsRead.read[decoder.checkedIndex] = true
+	}
 	fieldPtr := decoder.field.UnsafeGet(ptr)
 	decoder.fieldDecoder.Decode(fieldPtr, iter)
-	if iter.Error != nil && iter.Error != io.EOF {
+	if iter.Error != nil && iter.Error != io.EOF && !iter.addErrorField(decoder.field.Name(), decoder.field.Type().Type1()) {
 		iter.Error = fmt.Errorf("%s: %s", decoder.field.Name(), iter.Error.Error())
 	}
 }
 
+// checkedStructDecoder fails if a required field is absent and decodes the default of the other absent fields,
+// after the struct decoder has read the object, the decoders of these fields marking them read in iter.fieldsRead
+type checkedStructDecoder struct {
+	typ           reflect2.Type
+	structDecoder ValDecoder
+	bindings      []*Binding // the fields required or with default
+	fieldDecoders map[*Binding]*structFieldDecoder
+}
+
+// fieldsRead tells which fields of the checkedStructDecoder are read from the object being decoded
+type fieldsRead struct {
+	decoder *checkedStructDecoder
+	read    []bool
+}
+
+// newCheckedStructDecoder returns nil if no field is required or has default
+func newCheckedStructDecoder(typ reflect2.Type, bindings map[string]*Binding) *checkedStructDecoder {
+	checked := &checkedStructDecoder{typ: typ, fieldDecoders: map[*Binding]*structFieldDecoder{}}
+	for _, binding := range bindings {
+		if (binding.required || binding.defaultValue != nil) && checked.fieldDecoders[binding] == nil {
+			checked.bindings = append(checked.bindings, binding)
+			checked.fieldDecoders[binding] = binding.Decoder.(*structFieldDecoder)
+		}
+	}
+	if len(checked.bindings) == 0 {
+		return nil
+	}
+	// in field order, for the first absent field to be reported
+	sort.Sort(sortableBindings(checked.bindings))
+	for i, binding := range checked.bindings {
+		fieldDecoder := *checked.fieldDecoders[binding]
+		fieldDecoder.checked = checked
+		fieldDecoder.checkedIndex = i
+		checked.fieldDecoders[binding] = &fieldDecoder
+	}
+	return checked
+}
+
+// fieldDecoderOf returns the decoder of the field, which marks it read if the field is checked
+func (decoder *checkedStructDecoder) fieldDecoderOf(binding *Binding) *structFieldDecoder {
+	if decoder != nil {
+		if fieldDecoder := decoder.fieldDecoders[binding]; fieldDecoder != nil {
+			return fieldDecoder
+		}
+	}
+	return binding.Decoder.(*structFieldDecoder)
+}
+
+func (decoder *checkedStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	if iter.WhatIsNext() != ObjectValue {
+		// null leaves the struct as is, anything else is an error of the struct decoder
+		decoder.structDecoder.Decode(ptr, iter)
+		return
+	}
+	outer := iter.fieldsRead
+	read := &fieldsRead{decoder, make([]bool, len(decoder.bindings))}
+	iter.fieldsRead = read
+	decoder.structDecoder.Decode(ptr, iter)
+	iter.fieldsRead = outer
+	if iter.Error != nil && iter.Error != io.EOF {
+		return
+	}
+	for i, binding := range decoder.bindings {
+		if read.read[i] {
+			continue
+		}
+		if binding.required {
+			iter.ReportError("ReadObject", fmt.Sprintf("%v: required field %s is missing", decoder.typ, binding.FromNames[0]))
+			return
+		}
+		if err := decoder.decodeDefault(iter.cfg, ptr, binding); err != nil {
+			iter.Error = err
+			return
+		}
+	}
+}
+
+// checkDefaults decodes the defaults into a new struct, for an invalid one to be reported when the decoder is created
+func (decoder *checkedStructDecoder) checkDefaults(cfg *frozenConfig) error {
+	ptr := decoder.typ.UnsafeNew()
+	for _, binding := range decoder.bindings {
+		if binding.defaultValue == nil {
+			continue
+		}
+		if err := decoder.decodeDefault(cfg, ptr, binding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (decoder *checkedStructDecoder) decodeDefault(cfg *frozenConfig, ptr unsafe.Pointer, binding *Binding) error {
+	fieldDecoder := decoder.fieldDecoders[binding]
+	defaultIter := cfg.BorrowIterator(binding.defaultValue)
+	defer cfg.ReturnIterator(defaultIter)
+	fieldDecoder.fieldDecoder.Decode(fieldDecoder.field.UnsafeGet(ptr), defaultIter)
+	if defaultIter.nextToken() != 0 && (defaultIter.Error == nil || defaultIter.Error == io.EOF) {
+		defaultIter.ReportError("ReadObject", "there are bytes left after the default")
+	}
+	if defaultIter.Error != nil && defaultIter.Error != io.EOF {
+		return fmt.Errorf("%v: invalid default of field %s: %s", decoder.typ, binding.FromNames[0], defaultIter.Error.Error())
+	}
+	return nil
+}
+
 type stringModeStringDecoder struct {
 	elemDecoder ValDecoder
 	cfg         *frozenConfig
@@ -1082,7 +1260,7 @@ func (decoder *stringModeNumberDecoder) Decode(ptr unsafe.Pointer, iter *Iterato
 
 	c := iter.nextToken()
 	if c != '"' {
-		iter.ReportError("stringModeNumberDecoder", `expect ", but found `+string([]byte{c}))
+		iter.reportUnexpected("stringModeNumberDecoder", `"`, c)
 		return
 	}
 	decoder.elemDecoder.Decode(ptr, iter)
@@ -1091,7 +1269,7 @@ func (decoder *stringModeNumberDecoder) Decode(ptr unsafe.Pointer, iter *Iterato
 	}
 	c = iter.readByte()
 	if c != '"' {
-		iter.ReportError("stringModeNumberDecoder", `expect ", but found `+string([]byte{c}))
+		iter.reportUnexpected("stringModeNumberDecoder", `"`, c)
 		return
 	}
 }
@@ -3,7 +3,6 @@ package jsoniter
 import (
 	"fmt"
 	"github.com/modern-go/reflect2"
-	"io"
 	"reflect"
 	"unsafe"
 )
@@ -31,19 +30,21 @@ func encoderOfStruct(ctx *ctx, typ reflect2.Type) ValEncoder {
 			orderedBindings = append(orderedBindings, new)
 		}
 	}
-	if len(orderedBindings) == 0 {
+	if len(orderedBindings) == 0 && structDescriptor.inlineMap == nil {
 		return &emptyStructEncoder{}
 	}
 	finalOrderedFields := []structFieldTo{}
+	fieldNames := map[string]bool{}
 	for _, bindingTo := range orderedBindings {
 		if !bindingTo.ignored {
 			finalOrderedFields = append(finalOrderedFields, structFieldTo{
 				encoder: bindingTo.binding.Encoder.(*structFieldEncoder),
 				toName:  bindingTo.toName,
 			})
+			fieldNames[bindingTo.toName] = true
 		}
 	}
-	return &structEncoder{typ, finalOrderedFields}
+	return &structEncoder{typ: typ, fields: finalOrderedFields, inlineMap: structDescriptor.inlineMap, fieldNames: fieldNames}
 }
 
 func createCheckIsEmpty(ctx *ctx, typ reflect2.Type) checkIsEmpty {
@@ -70,6 +71,111 @@ func createCheckIsEmpty(ctx *ctx, typ reflect2.Type) checkIsEmpty {
 	}
 }
 
+type isZeroer interface {
+	IsZero() bool
+}
+
+var isZeroerType = reflect2.TypeOfPtr((*isZeroer)(nil)).Elem()
+
+// fieldIsZero returns the check of the tag option omitzero for the field of the struct
+func fieldIsZero(field reflect2.StructField) func(ptr unsafe.Pointer) bool {
+	checkIsZero := createCheckIsZero(field.Type())
+	return func(ptr unsafe.Pointer) bool {
+		return checkIsZero(field.UnsafeGet(ptr))
+	}
+}
+
+// embeddedIsZero returns the check of omitzero for the field of the embedded struct, isZero is its check in the embedded struct
+func embeddedIsZero(field reflect2.StructField, isZero func(ptr unsafe.Pointer) bool) func(ptr unsafe.Pointer) bool {
+	if isZero == nil {
+		return nil
+	}
+	if field.Type().Kind() == reflect.Ptr {
+		return func(ptr unsafe.Pointer) bool {
+			embedded := *(*unsafe.Pointer)(field.UnsafeGet(ptr))
+			return embedded == nil || isZero(embedded)
+		}
+	}
+	return func(ptr unsafe.Pointer) bool {
+		return isZero(field.UnsafeGet(ptr))
+	}
+}
+
+// createCheckIsZero uses the IsZero method of the type, or compares with the zero value if it has none
+func createCheckIsZero(typ reflect2.Type) func(ptr unsafe.Pointer) bool {
+	if typ.Implements(isZeroerType) {
+		if typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Interface {
+			// nil is zero, without calling IsZero on it
+			return func(ptr unsafe.Pointer) bool {
+				return *(*unsafe.Pointer)(ptr) == nil || typ.UnsafeIndirect(ptr).(isZeroer).IsZero()
+			}
+		}
+		return func(ptr unsafe.Pointer) bool {
+			return typ.UnsafeIndirect(ptr).(isZeroer).IsZero()
+		}
+	}
+	ptrType := reflect2.PtrTo(typ)
+	if ptrType.Implements(isZeroerType) {
+		return func(ptr unsafe.Pointer) bool {
+			return ptrType.UnsafeIndirect(unsafe.Pointer(&ptr)).(isZeroer).IsZero()
+		}
+	}
+	return createCheckIsZeroValue(typ.Type1())
+}
+
+// createCheckIsZeroValue compares with the zero value of typ, same as reflect.Value.IsZero
+func createCheckIsZeroValue(typ reflect.Type) func(ptr unsafe.Pointer) bool {
+	switch typ.Kind() {
+	case reflect.String:
+		return func(ptr unsafe.Pointer) bool {
+			return *(*string)(ptr) == ""
+		}
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Slice, reflect.Interface:
+		// the first word is the pointer, or the type of the interface
+		return func(ptr unsafe.Pointer) bool {
+			return *(*unsafe.Pointer)(ptr) == nil
+		}
+	case reflect.Array:
+		elemIsZero := createCheckIsZeroValue(typ.Elem())
+		elemSize := typ.Elem().Size()
+		length := typ.Len()
+		return func(ptr unsafe.Pointer) bool {
+			for i := 0; i < length; i++ {
+				if !elemIsZero(unsafe.Pointer(uintptr(ptr) + uintptr(i)*elemSize)) {
+					return false
+				}
+			}
+			return true
+		}
+	case reflect.Struct:
+		// the padding between the fields is not compared
+		offsets := make([]uintptr, typ.NumField())
+		checks := make([]func(ptr unsafe.Pointer) bool, typ.NumField())
+		for i := range checks {
+			offsets[i] = typ.Field(i).Offset
+			checks[i] = createCheckIsZeroValue(typ.Field(i).Type)
+		}
+		return func(ptr unsafe.Pointer) bool {
+			for i, check := range checks {
+				if !check(unsafe.Pointer(uintptr(ptr) + offsets[i])) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	// the bits of bool, integers, floats and complexes are all zero, -0.0 is not zero
+	size := typ.Size()
+	return func(ptr unsafe.Pointer) bool {
+		for i := uintptr(0); i < size; i++ {
+			if *(*byte)(unsafe.Pointer(uintptr(ptr) + i)) != 0 {
+				return false
+			}
+		}
+		return true
+	}
+}
+
 func resolveConflictBinding(cfg *frozenConfig, old, new *Binding) (ignoreOld, ignoreNew bool) {
 	newTagged := new.Field.Tag().Get(cfg.getTagKey()) != ""
 	oldTagged := old.Field.Tag().Get(cfg.getTagKey()) != ""
@@ -103,12 +209,14 @@ type structFieldEncoder struct {
 	field        reflect2.StructField
 	fieldEncoder ValEncoder
 	omitempty    bool
+	isZero       func(ptr unsafe.Pointer) bool // tag option omitzero, takes the pointer to the struct, nil if not set
+	defaultValue []byte                        // JSON of the tag option default, described by SchemaOf
 }
 
 func (encoder *structFieldEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 	fieldPtr := encoder.field.UnsafeGet(ptr)
 	encoder.fieldEncoder.Encode(fieldPtr, stream)
-	if stream.Error != nil && stream.Error != io.EOF {
+	if stream.hasValueError() {
 		stream.Error = fmt.Errorf("%s: %s", encoder.field.Name(), stream.Error.Error())
 	}
 }
@@ -132,8 +240,10 @@ type IsEmbeddedPtrNil interface {
 }
 
 type structEncoder struct {
-	typ    reflect2.Type
-	fields []structFieldTo
+	typ        reflect2.Type
+	fields     []structFieldTo
+	inlineMap  *inlineMap
+	fieldNames map[string]bool // names of the fields, the entries of the inline map with these keys are not written
 }
 
 type structFieldTo struct {
@@ -148,6 +258,9 @@ func (encoder *structEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 		if field.encoder.omitempty && field.encoder.IsEmpty(ptr) {
 			continue
 		}
+		if field.encoder.isZero != nil && field.encoder.isZero(ptr) {
+			continue
+		}
 		if field.encoder.IsEmbeddedPtrNil(ptr) {
 			continue
 		}
@@ -158,8 +271,11 @@ func (encoder *structEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 		field.encoder.Encode(ptr, stream)
 		isNotFirst = true
 	}
+	if encoder.inlineMap != nil {
+		encoder.inlineMap.encodeEntries(ptr, stream, isNotFirst, encoder.fieldNames)
+	}
 	stream.WriteObjectEnd()
-	if stream.Error != nil && stream.Error != io.EOF {
+	if stream.hasValueError() {
 		stream.Error = fmt.Errorf("%v.%s", encoder.typ, stream.Error.Error())
 	}
 }
@@ -0,0 +1,145 @@
+package jsoniter
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Registry holds type and field codecs and extensions for the APIs frozen from a Config with it as Registry,
+// instead of the ones registered by the package level RegisterTypeDecoder and friends, which the APIs ignore.
+//
+// The APIs look for the codec of a type in this order:
+// the extensions of the registry in registration order, the codecs of the Config options,
+// the extensions registered by API.RegisterExtension, the codec registered for the type name,
+// and for a pointer, the codec registered for the name of its elem type.
+// A codec registered for a struct field comes before all of them.
+//
+// Changing the registry makes the APIs frozen with it drop their cached codecs on the next lookup,
+// so the change is seen by all of them, whether they were used already or not.
+// The registry keeps no reference to the APIs.
+type Registry struct {
+	generation    uint32 // counts the changes, read and written atomically
+	mutex         sync.RWMutex
+	typeDecoders  map[string]ValDecoder
+	fieldDecoders map[string]ValDecoder
+	typeEncoders  map[string]ValEncoder
+	fieldEncoders map[string]ValEncoder
+	extensions    []Extension
+}
+
+// defaultRegistry holds the package level registrations, for the APIs frozen without Registry,
+// it drops no cache when changed
+var defaultRegistry = NewRegistry()
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		typeDecoders:  map[string]ValDecoder{},
+		fieldDecoders: map[string]ValDecoder{},
+		typeEncoders:  map[string]ValEncoder{},
+		fieldEncoders: map[string]ValEncoder{},
+	}
+}
+
+// RegisterTypeDecoderFunc register TypeDecoder for a type with function
+func (registry *Registry) RegisterTypeDecoderFunc(typ string, fun DecoderFunc) {
+	registry.RegisterTypeDecoder(typ, &funcDecoder{fun})
+}
+
+// RegisterTypeDecoder register TypeDecoder for a typ
+func (registry *Registry) RegisterTypeDecoder(typ string, decoder ValDecoder) {
+	registry.update(func() {
+		registry.typeDecoders[typ] = decoder
+	})
+}
+
+// RegisterFieldDecoderFunc register TypeDecoder for a struct field with function
+func (registry *Registry) RegisterFieldDecoderFunc(typ string, field string, fun DecoderFunc) {
+	registry.RegisterFieldDecoder(typ, field, &funcDecoder{fun})
+}
+
+// RegisterFieldDecoder register TypeDecoder for a struct field
+func (registry *Registry) RegisterFieldDecoder(typ string, field string, decoder ValDecoder) {
+	registry.update(func() {
+		registry.fieldDecoders[fieldCacheKey(typ, field)] = decoder
+	})
+}
+
+// RegisterTypeEncoderFunc register TypeEncoder for a type with encode/isEmpty function
+func (registry *Registry) RegisterTypeEncoderFunc(typ string, fun EncoderFunc, isEmptyFunc func(unsafe.Pointer) bool) {
+	registry.RegisterTypeEncoder(typ, &funcEncoder{fun, isEmptyFunc})
+}
+
+// RegisterTypeEncoder register TypeEncoder for a type
+func (registry *Registry) RegisterTypeEncoder(typ string, encoder ValEncoder) {
+	registry.update(func() {
+		registry.typeEncoders[typ] = encoder
+	})
+}
+
+// RegisterFieldEncoderFunc register TypeEncoder for a struct field with encode/isEmpty function
+func (registry *Registry) RegisterFieldEncoderFunc(typ string, field string, fun EncoderFunc, isEmptyFunc func(unsafe.Pointer) bool) {
+	registry.RegisterFieldEncoder(typ, field, &funcEncoder{fun, isEmptyFunc})
+}
+
+// RegisterFieldEncoder register TypeEncoder for a struct field
+func (registry *Registry) RegisterFieldEncoder(typ string, field string, encoder ValEncoder) {
+	registry.update(func() {
+		registry.fieldEncoders[fieldCacheKey(typ, field)] = encoder
+	})
+}
+
+// RegisterExtension register extension, after the ones already registered
+func (registry *Registry) RegisterExtension(extension Extension) {
+	registry.update(func() {
+		registry.extensions = append(registry.extensions, extension)
+	})
+}
+
+// update applies the change, then moves the generation for the APIs to drop the codecs they cached
+func (registry *Registry) update(change func()) {
+	registry.mutex.Lock()
+	change()
+	registry.mutex.Unlock()
+	atomic.AddUint32(&registry.generation, 1)
+}
+
+func (registry *Registry) getGeneration() uint32 {
+	return atomic.LoadUint32(&registry.generation)
+}
+
+func (registry *Registry) getExtensions() []Extension {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	return registry.extensions
+}
+
+func (registry *Registry) getTypeDecoder(typ string) ValDecoder {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	return registry.typeDecoders[typ]
+}
+
+func (registry *Registry) getTypeEncoder(typ string) ValEncoder {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	return registry.typeEncoders[typ]
+}
+
+func (registry *Registry) getFieldDecoder(typ string, field string) ValDecoder {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	return registry.fieldDecoders[fieldCacheKey(typ, field)]
+}
+
+func (registry *Registry) getFieldEncoder(typ string, field string) ValEncoder {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	return registry.fieldEncoders[fieldCacheKey(typ, field)]
+}
+
+func fieldCacheKey(typ string, field string) string {
+	return fmt.Sprintf("%s/%s", typ, field)
+}
@@ -0,0 +1,478 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/json-iterator/go"
+)
+
+// Compiler compiles schemas, resolving $ref to the resources added to it.
+type Compiler struct {
+	resources map[string]interface{}
+	// AssertFormat makes format keyword an assertion instead of an annotation, it is enabled by NewCompiler.
+	AssertFormat bool
+}
+
+// NewCompiler creates a compiler asserting formats.
+func NewCompiler() *Compiler {
+	return &Compiler{resources: map[string]interface{}{}, AssertFormat: true}
+}
+
+// AddResource makes the schema document available to $ref by its uri.
+func (compiler *Compiler) AddResource(uri string, data []byte) error {
+	document, err := parseDocument(data)
+	if err != nil {
+		return err
+	}
+	if compiler.resources == nil {
+		compiler.resources = map[string]interface{}{}
+	}
+	compiler.resources[strings.TrimSuffix(uri, "#")] = document
+	return nil
+}
+
+// Compile compiles the schema document.
+func (compiler *Compiler) Compile(data []byte) (*Schema, error) {
+	document, err := parseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	ctx := &compileContext{
+		compiler: compiler,
+		schemas:  map[string]*rawSchema{},
+		nodes:    map[string]*node{},
+		indexed:  map[string]bool{},
+	}
+	ctx.index(document, []location{{"", ""}})
+	for uri, resource := range compiler.resources {
+		if !ctx.indexed[uri] {
+			ctx.indexed[uri] = true
+			ctx.index(resource, []location{{uri, ""}})
+		}
+	}
+	root, err := ctx.compile("#")
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{root: root}, nil
+}
+
+func parseDocument(data []byte) (interface{}, error) {
+	iter := jsoniter.ConfigDefault.BorrowIterator(data)
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	document := readValue(iter)
+	if iter.Error != nil && iter.Error != io.EOF {
+		return nil, iter.Error
+	}
+	return document, nil
+}
+
+// node is a compiled schema
+type node struct {
+	location string
+	always   *bool // boolean schema
+
+	ref *node
+
+	types      []string
+	enum       []interface{}
+	constValue interface{}
+	hasConst   bool
+
+	minimum          *big.Rat
+	maximum          *big.Rat
+	exclusiveMinimum *big.Rat
+	exclusiveMaximum *big.Rat
+	multipleOf       *big.Rat
+
+	minLength int
+	maxLength int
+	pattern   *regexp.Regexp
+	format    string
+
+	prefixItems      []*node
+	items            *node
+	contains         *node
+	minContains      int
+	maxContains      int
+	minItems         int
+	maxItems         int
+	uniqueItems      bool
+	unevaluatedItems *node
+
+	properties            map[string]*node
+	patternProperties     map[*regexp.Regexp]*node
+	additionalProperties  *node
+	required              []string
+	minProperties         int
+	maxProperties         int
+	propertyNames         *node
+	dependentRequired     map[string][]string
+	dependentSchemas      map[string]*node
+	unevaluatedProperties *node
+
+	allOf    []*node
+	anyOf    []*node
+	oneOf    []*node
+	not      *node
+	ifNode   *node
+	thenNode *node
+	elseNode *node
+}
+
+type rawSchema struct {
+	value interface{}
+	base  string
+}
+
+// location of a schema, as pointer relative to the resource identified by base
+type location struct {
+	base    string
+	pointer string
+}
+
+type compileContext struct {
+	compiler *Compiler
+	schemas  map[string]*rawSchema
+	nodes    map[string]*node
+	indexed  map[string]bool
+}
+
+var singleSubschemaKeywords = []string{"additionalProperties", "unevaluatedProperties", "unevaluatedItems",
+	"items", "contains", "propertyNames", "not", "if", "then", "else"}
+var arraySubschemaKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+var mapSubschemaKeywords = []string{"properties", "patternProperties", "$defs", "definitions", "dependentSchemas"}
+
+// index registers value and its subschemas by all their locations and anchors
+func (ctx *compileContext) index(value interface{}, locations []location) {
+	object, isObject := value.(map[string]interface{})
+	if id, ok := object["$id"].(string); isObject && ok {
+		base := resolveURI(locations[len(locations)-1].base, id)
+		base = strings.SplitN(base, "#", 2)[0]
+		if base != locations[len(locations)-1].base {
+			locations = append(locations, location{base, ""})
+		}
+	}
+	innermost := locations[len(locations)-1].base
+	for _, loc := range locations {
+		ctx.schemas[loc.base+"#"+loc.pointer] = &rawSchema{value, innermost}
+	}
+	if !isObject {
+		return
+	}
+	for _, keyword := range []string{"$anchor", "$dynamicAnchor"} {
+		if anchor, ok := object[keyword].(string); ok {
+			ctx.schemas[innermost+"#"+anchor] = &rawSchema{value, innermost}
+		}
+	}
+	child := func(value interface{}, tokens ...string) {
+		childLocations := make([]location, len(locations))
+		for i, loc := range locations {
+			childLocations[i] = loc
+			for _, token := range tokens {
+				childLocations[i].pointer += "/" + escapeToken(token)
+			}
+		}
+		ctx.index(value, childLocations)
+	}
+	for _, keyword := range singleSubschemaKeywords {
+		if sub, ok := object[keyword]; ok {
+			child(sub, keyword)
+		}
+	}
+	for _, keyword := range arraySubschemaKeywords {
+		if subs, ok := object[keyword].([]interface{}); ok {
+			for i, sub := range subs {
+				child(sub, keyword, strconv.Itoa(i))
+			}
+		}
+	}
+	for _, keyword := range mapSubschemaKeywords {
+		if subs, ok := object[keyword].(map[string]interface{}); ok {
+			for name, sub := range subs {
+				child(sub, keyword, name)
+			}
+		}
+	}
+}
+
+func resolveURI(base string, ref string) string {
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
+
+// lookup finds the raw schema by absolute location, walking the JSON Pointer if it is not a subschema location
+func (ctx *compileContext) lookup(uri string) (*rawSchema, string) {
+	parts := strings.SplitN(uri, "#", 2)
+	if len(parts) == 1 {
+		parts = append(parts, "")
+	}
+	fragment, err := url.PathUnescape(parts[1])
+	if err != nil {
+		return nil, ""
+	}
+	uri = parts[0] + "#" + fragment
+	if raw := ctx.schemas[uri]; raw != nil {
+		return raw, uri
+	}
+	if resource, found := ctx.compiler.resources[parts[0]]; found && !ctx.indexed[parts[0]] {
+		ctx.indexed[parts[0]] = true
+		ctx.index(resource, []location{{parts[0], ""}})
+		return ctx.lookup(uri)
+	}
+	if !strings.HasPrefix(fragment, "/") {
+		return nil, ""
+	}
+	raw := ctx.schemas[parts[0]+"#"]
+	if raw == nil {
+		return nil, ""
+	}
+	tokens, err := jsoniter.ParsePointer(fragment)
+	if err != nil {
+		return nil, ""
+	}
+	value := raw.value
+	for _, token := range tokens {
+		switch container := value.(type) {
+		case map[string]interface{}:
+			value = container[token]
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(container) {
+				return nil, ""
+			}
+			value = container[index]
+		default:
+			return nil, ""
+		}
+	}
+	raw = &rawSchema{value, raw.base}
+	ctx.schemas[uri] = raw
+	return raw, uri
+}
+
+func (ctx *compileContext) compile(uri string) (*node, error) {
+	if compiled := ctx.nodes[uri]; compiled != nil {
+		return compiled, nil
+	}
+	raw, uri := ctx.lookup(uri)
+	if raw == nil {
+		return nil, fmt.Errorf("schema %s not found", uri)
+	}
+	if compiled := ctx.nodes[uri]; compiled != nil {
+		return compiled, nil
+	}
+	compiled := &node{location: uri, minLength: -1, maxLength: -1, minContains: 1, maxContains: -1,
+		minItems: -1, maxItems: -1, minProperties: -1, maxProperties: -1}
+	ctx.nodes[uri] = compiled
+	if err := ctx.compileNode(compiled, raw); err != nil {
+		return nil, err
+	}
+	return compiled, nil
+}
+
+func (ctx *compileContext) compileNode(n *node, raw *rawSchema) error {
+	if always, ok := raw.value.(bool); ok {
+		n.always = &always
+		return nil
+	}
+	object, ok := raw.value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: schema must be object or boolean", n.location)
+	}
+	var err error
+	fail := func(keyword string, format string, args ...interface{}) {
+		if err == nil {
+			err = fmt.Errorf("%s/%s: %s", n.location, keyword, fmt.Sprintf(format, args...))
+		}
+	}
+	sub := func(tokens ...string) *node {
+		uri := n.location
+		for _, token := range tokens {
+			uri += "/" + escapeToken(token)
+		}
+		compiled, compileErr := ctx.compile(uri)
+		if compileErr != nil && err == nil {
+			err = compileErr
+		}
+		return compiled
+	}
+	number := func(keyword string) *big.Rat {
+		value, found := object[keyword]
+		if !found {
+			return nil
+		}
+		num, ok := value.(json.Number)
+		if !ok {
+			fail(keyword, "must be number")
+			return nil
+		}
+		rat, ok := new(big.Rat).SetString(string(num))
+		if !ok {
+			fail(keyword, "invalid number %s", num)
+		}
+		return rat
+	}
+	integer := func(keyword string, defaultValue int) int {
+		rat := number(keyword)
+		if rat == nil {
+			return defaultValue
+		}
+		if !rat.IsInt() || rat.Sign() < 0 || !rat.Num().IsInt64() {
+			fail(keyword, "must be non negative integer")
+			return defaultValue
+		}
+		return int(rat.Num().Int64())
+	}
+	regex := func(keyword string, pattern string) *regexp.Regexp {
+		compiled, compileErr := regexp.Compile(pattern)
+		if compileErr != nil {
+			fail(keyword, "invalid pattern %q", pattern)
+		}
+		return compiled
+	}
+	for _, keyword := range []string{"$ref", "$dynamicRef"} {
+		if ref, ok := object[keyword].(string); ok {
+			n.ref, err = ctx.compile(resolveURI(raw.base, ref))
+			if err != nil {
+				return fmt.Errorf("%s/%s: %v", n.location, keyword, err)
+			}
+		}
+	}
+	switch types := object["type"].(type) {
+	case string:
+		n.types = []string{types}
+	case []interface{}:
+		for _, typ := range types {
+			name, _ := typ.(string)
+			n.types = append(n.types, name)
+		}
+	}
+	for _, typ := range n.types {
+		switch typ {
+		case "null", "boolean", "object", "array", "number", "string", "integer":
+		default:
+			fail("type", "unknown type %q", typ)
+		}
+	}
+	if enum, found := object["enum"]; found {
+		values, ok := enum.([]interface{})
+		if !ok {
+			fail("enum", "must be array")
+		}
+		n.enum = values
+		if n.enum == nil {
+			n.enum = []interface{}{}
+		}
+	}
+	n.constValue, n.hasConst = object["const"]
+	n.minimum = number("minimum")
+	n.maximum = number("maximum")
+	n.exclusiveMinimum = number("exclusiveMinimum")
+	n.exclusiveMaximum = number("exclusiveMaximum")
+	n.multipleOf = number("multipleOf")
+	if n.multipleOf != nil && n.multipleOf.Sign() <= 0 {
+		fail("multipleOf", "must be greater than 0")
+	}
+	n.minLength = integer("minLength", -1)
+	n.maxLength = integer("maxLength", -1)
+	if pattern, ok := object["pattern"].(string); ok {
+		n.pattern = regex("pattern", pattern)
+	}
+	if format, ok := object["format"].(string); ok && ctx.compiler.AssertFormat {
+		n.format = format
+	}
+	if _, found := object["prefixItems"]; found {
+		subs, _ := object["prefixItems"].([]interface{})
+		for i := range subs {
+			n.prefixItems = append(n.prefixItems, sub("prefixItems", strconv.Itoa(i)))
+		}
+	}
+	for keyword, target := range map[string]**node{
+		"items":                 &n.items,
+		"contains":              &n.contains,
+		"unevaluatedItems":      &n.unevaluatedItems,
+		"additionalProperties":  &n.additionalProperties,
+		"propertyNames":         &n.propertyNames,
+		"unevaluatedProperties": &n.unevaluatedProperties,
+		"not":                   &n.not,
+		"if":                    &n.ifNode,
+		"then":                  &n.thenNode,
+		"else":                  &n.elseNode,
+	} {
+		if _, found := object[keyword]; found {
+			*target = sub(keyword)
+		}
+	}
+	n.minContains = integer("minContains", 1)
+	n.maxContains = integer("maxContains", -1)
+	n.minItems = integer("minItems", -1)
+	n.maxItems = integer("maxItems", -1)
+	n.uniqueItems, _ = object["uniqueItems"].(bool)
+	if properties, ok := object["properties"].(map[string]interface{}); ok {
+		n.properties = map[string]*node{}
+		for name := range properties {
+			n.properties[name] = sub("properties", name)
+		}
+	}
+	if patternProperties, ok := object["patternProperties"].(map[string]interface{}); ok {
+		n.patternProperties = map[*regexp.Regexp]*node{}
+		for pattern := range patternProperties {
+			n.patternProperties[regex("patternProperties", pattern)] = sub("patternProperties", pattern)
+		}
+	}
+	if required, ok := object["required"].([]interface{}); ok {
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				n.required = append(n.required, name)
+			} else {
+				fail("required", "must be array of string")
+			}
+		}
+	}
+	n.minProperties = integer("minProperties", -1)
+	n.maxProperties = integer("maxProperties", -1)
+	if dependentRequired, ok := object["dependentRequired"].(map[string]interface{}); ok {
+		n.dependentRequired = map[string][]string{}
+		for name, dependencies := range dependentRequired {
+			dependencies, _ := dependencies.([]interface{})
+			for _, dependency := range dependencies {
+				dependency, _ := dependency.(string)
+				n.dependentRequired[name] = append(n.dependentRequired[name], dependency)
+			}
+		}
+	}
+	if dependentSchemas, ok := object["dependentSchemas"].(map[string]interface{}); ok {
+		n.dependentSchemas = map[string]*node{}
+		for name := range dependentSchemas {
+			n.dependentSchemas[name] = sub("dependentSchemas", name)
+		}
+	}
+	for keyword, target := range map[string]*[]*node{"allOf": &n.allOf, "anyOf": &n.anyOf, "oneOf": &n.oneOf} {
+		if _, found := object[keyword]; !found {
+			continue
+		}
+		subs, ok := object[keyword].([]interface{})
+		if !ok || len(subs) == 0 {
+			fail(keyword, "must be non empty array")
+		}
+		for i := range subs {
+			*target = append(*target, sub(keyword, strconv.Itoa(i)))
+		}
+	}
+	return err
+}
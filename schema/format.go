@@ -0,0 +1,108 @@
+package schema
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/json-iterator/go"
+)
+
+// formats checks the values of format keyword, values of other types than the format applies to are valid
+var formats = map[string]func(value interface{}) bool{
+	"date-time":     stringFormat(isDateTime),
+	"date":          stringFormat(isDate),
+	"time":          stringFormat(isTime),
+	"duration":      stringFormat(durationPattern.MatchString),
+	"email":         stringFormat(isEmail),
+	"hostname":      stringFormat(isHostname),
+	"ipv4":          stringFormat(isIPv4),
+	"ipv6":          stringFormat(isIPv6),
+	"uri":           stringFormat(isURI),
+	"uri-reference": stringFormat(isURIReference),
+	"uuid":          stringFormat(uuidPattern.MatchString),
+	"regex":         stringFormat(isRegex),
+	"json-pointer":  stringFormat(isJSONPointer),
+}
+
+var durationPattern = regexp.MustCompile(`^P(?:\d+W|(?:\d+Y)?(?:\d+M)?(?:\d+D)?(?:T(?:\d+H)?(?:\d+M)?(?:\d+S)?)?)$`)
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+var hostnameLabelPattern = regexp.MustCompile(`^[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+func stringFormat(check func(string) bool) func(value interface{}) bool {
+	return func(value interface{}) bool {
+		str, isString := value.(string)
+		return !isString || check(str)
+	}
+}
+
+func isDateTime(value string) bool {
+	_, err := time.Parse(time.RFC3339Nano, strings.ToUpper(value))
+	return err == nil
+}
+
+func isDate(value string) bool {
+	_, err := time.Parse("2006-01-02", value)
+	return err == nil
+}
+
+func isTime(value string) bool {
+	_, err := time.Parse("15:04:05.999999999Z07:00", strings.ToUpper(value))
+	return err == nil
+}
+
+func isEmail(value string) bool {
+	at := strings.LastIndexByte(value, '@')
+	if at <= 0 || at > 64 || strings.ContainsAny(value[:at], " \t\r\n") {
+		return false
+	}
+	domain := value[at+1:]
+	if strings.HasPrefix(domain, "[") && strings.HasSuffix(domain, "]") {
+		return net.ParseIP(strings.TrimPrefix(domain[1:len(domain)-1], "IPv6:")) != nil
+	}
+	return isHostname(domain)
+}
+
+func isHostname(value string) bool {
+	value = strings.TrimSuffix(value, ".")
+	if value == "" || len(value) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(value, ".") {
+		if !hostnameLabelPattern.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIPv4(value string) bool {
+	ip := net.ParseIP(value)
+	return ip != nil && ip.To4() != nil && !strings.Contains(value, ":")
+}
+
+func isIPv6(value string) bool {
+	return net.ParseIP(value) != nil && strings.Contains(value, ":")
+}
+
+func isURI(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && parsed.IsAbs()
+}
+
+func isURIReference(value string) bool {
+	_, err := url.Parse(value)
+	return err == nil
+}
+
+func isRegex(value string) bool {
+	_, err := regexp.Compile(value)
+	return err == nil
+}
+
+func isJSONPointer(value string) bool {
+	_, err := jsoniter.ParsePointer(value)
+	return err == nil
+}
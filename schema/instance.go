@@ -0,0 +1,163 @@
+package schema
+
+import (
+	"encoding/json"
+
+	"github.com/json-iterator/go"
+)
+
+// instance is the value being validated, read once in document order.
+// valueType peeks at the value, the other methods consume it and only one of them is called.
+type instance interface {
+	valueType() jsoniter.ValueType
+	// scalar returns the string, json.Number, bool or nil value
+	scalar() interface{}
+	elements(callback func(index int, element instance))
+	members(callback func(name string, member instance))
+	skip()
+}
+
+// decodeInstance reads the whole value, numbers as json.Number
+func decodeInstance(src instance) interface{} {
+	switch src.valueType() {
+	case jsoniter.ArrayValue:
+		array := []interface{}{}
+		src.elements(func(index int, element instance) {
+			array = append(array, decodeInstance(element))
+		})
+		return array
+	case jsoniter.ObjectValue:
+		object := map[string]interface{}{}
+		src.members(func(name string, member instance) {
+			object[name] = decodeInstance(member)
+		})
+		return object
+	}
+	return src.scalar()
+}
+
+// iterInstance reads the next value of the iterator
+type iterInstance struct {
+	iter *jsoniter.Iterator
+}
+
+func (src iterInstance) valueType() jsoniter.ValueType {
+	return src.iter.WhatIsNext()
+}
+
+func (src iterInstance) scalar() interface{} {
+	switch src.iter.WhatIsNext() {
+	case jsoniter.StringValue:
+		return src.iter.ReadString()
+	case jsoniter.NumberValue:
+		return src.iter.ReadNumber()
+	case jsoniter.BoolValue:
+		return src.iter.ReadBool()
+	case jsoniter.NilValue:
+		src.iter.ReadNil()
+		return nil
+	}
+	// reports the error of the invalid value
+	return readValue(src.iter)
+}
+
+func (src iterInstance) elements(callback func(index int, element instance)) {
+	index := 0
+	src.iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+		callback(index, src)
+		index++
+		return true
+	})
+}
+
+func (src iterInstance) members(callback func(name string, member instance)) {
+	src.iter.ReadObjectCB(func(iter *jsoniter.Iterator, field string) bool {
+		callback(field, src)
+		return true
+	})
+}
+
+func (src iterInstance) skip() {
+	src.iter.Skip()
+}
+
+// valueInstance is a value decoded from JSON, with numbers as json.Number
+type valueInstance struct {
+	value interface{}
+}
+
+func (src valueInstance) valueType() jsoniter.ValueType {
+	switch src.value.(type) {
+	case nil:
+		return jsoniter.NilValue
+	case bool:
+		return jsoniter.BoolValue
+	case json.Number:
+		return jsoniter.NumberValue
+	case string:
+		return jsoniter.StringValue
+	case []interface{}:
+		return jsoniter.ArrayValue
+	case map[string]interface{}:
+		return jsoniter.ObjectValue
+	}
+	return jsoniter.InvalidValue
+}
+
+func (src valueInstance) scalar() interface{} {
+	return src.value
+}
+
+func (src valueInstance) elements(callback func(index int, element instance)) {
+	for i, element := range src.value.([]interface{}) {
+		callback(i, valueInstance{element})
+	}
+}
+
+func (src valueInstance) members(callback func(name string, member instance)) {
+	for name, member := range src.value.(map[string]interface{}) {
+		callback(name, valueInstance{member})
+	}
+}
+
+func (src valueInstance) skip() {
+}
+
+// anyInstance walks the Any without writing it out, lazy values are only parsed where they are read
+type anyInstance struct {
+	any jsoniter.Any
+}
+
+func (src anyInstance) valueType() jsoniter.ValueType {
+	return src.any.ValueType()
+}
+
+func (src anyInstance) scalar() interface{} {
+	switch src.any.ValueType() {
+	case jsoniter.StringValue:
+		return src.any.ToString()
+	case jsoniter.NumberValue:
+		return json.Number(src.any.ToString())
+	case jsoniter.BoolValue:
+		return src.any.ToBool()
+	case jsoniter.NilValue:
+		return nil
+	}
+	return src.any.GetInterface()
+}
+
+func (src anyInstance) elements(callback func(index int, element instance)) {
+	size := src.any.Size()
+	for i := 0; i < size; i++ {
+		callback(i, anyInstance{src.any.Get(i)})
+	}
+}
+
+func (src anyInstance) members(callback func(name string, member instance)) {
+	for _, name := range src.any.Keys() {
+		callback(name, anyInstance{src.any.Get(name)})
+	}
+}
+
+func (src anyInstance) skip() {
+}
@@ -0,0 +1,193 @@
+// Package schema validates JSON documents against JSON Schema draft 2020-12.
+//
+// The schema is compiled once, and can then validate []byte, a jsoniter.Iterator stream
+// or a jsoniter.Any, parsing the instance in one pass with jsoniter.
+package schema
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/json-iterator/go"
+)
+
+// Schema is a compiled JSON Schema, safe for concurrent use.
+type Schema struct {
+	root  *node
+	hooks Hooks
+}
+
+// Hooks customize the validation.
+type Hooks struct {
+	// UnknownProperty is called for every object member not declared by properties or patternProperties
+	// of a schema declaring properties, and without additionalProperties.
+	// Returning false rejects the member, like additionalProperties: false would.
+	UnknownProperty func(instanceLocation string, name string) bool
+	// Formats adds or replaces the checkers of format keyword by format name.
+	Formats map[string]func(value interface{}) bool
+}
+
+// RejectUnknownProperties is an UnknownProperty hook rejecting every undeclared member,
+// the same way Config.DisallowUnknownFields rejects unknown fields when decoding struct.
+func RejectUnknownProperties(instanceLocation string, name string) bool {
+	return false
+}
+
+// ValidationError describes one failed keyword.
+type ValidationError struct {
+	InstanceLocation string // JSON Pointer to the invalid value in the instance
+	KeywordLocation  string // URI with JSON Pointer fragment to the failed keyword in the schema
+	Message          string
+}
+
+func (err *ValidationError) Error() string {
+	location := err.InstanceLocation
+	if location == "" {
+		location = "/"
+	}
+	return location + ": " + err.Message
+}
+
+// ValidationErrors is returned by Validate when the instance is invalid.
+type ValidationErrors []*ValidationError
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Compile compiles a schema document without external resources.
+func Compile(data []byte) (*Schema, error) {
+	return NewCompiler().Compile(data)
+}
+
+// MustCompile is like Compile but panics if the schema is invalid.
+func MustCompile(data []byte) *Schema {
+	schema, err := Compile(data)
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+// WithHooks returns a copy of the schema validating with hooks.
+func (schema *Schema) WithHooks(hooks Hooks) *Schema {
+	return &Schema{root: schema.root, hooks: hooks}
+}
+
+// DisallowUnknownFields tells if the root schema rejects undeclared members,
+// through additionalProperties: false or unevaluatedProperties: false.
+func (schema *Schema) DisallowUnknownFields() bool {
+	for _, n := range []*node{schema.root.additionalProperties, schema.root.unevaluatedProperties} {
+		if n != nil && n.always != nil && !*n.always {
+			return true
+		}
+	}
+	return false
+}
+
+// Config freezes cfg, with DisallowUnknownFields derived from the schema.
+func (schema *Schema) Config(cfg jsoniter.Config) jsoniter.API {
+	cfg.DisallowUnknownFields = schema.DisallowUnknownFields()
+	return cfg.Froze()
+}
+
+// Validate parses data and validates it while reading, returns ValidationErrors if data is invalid.
+func (schema *Schema) Validate(data []byte) error {
+	iter := jsoniter.ConfigDefault.BorrowIterator(data)
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	err := schema.ValidateIterator(iter)
+	if iter.Error != nil && iter.Error != io.EOF {
+		return iter.Error
+	}
+	if iter.WhatIsNext() != jsoniter.InvalidValue || iter.Error != io.EOF {
+		iter.ReportError("Validate", "there are bytes left after value")
+		return iter.Error
+	}
+	return err
+}
+
+// ValidateIterator reads the next value from iter and validates it while reading.
+func (schema *Schema) ValidateIterator(iter *jsoniter.Iterator) error {
+	err := schema.validate(iterInstance{iter})
+	if iter.Error != nil && iter.Error != io.EOF {
+		return iter.Error
+	}
+	return err
+}
+
+// ValidateAny validates the value of any, lazy values are parsed only where the schema reads them.
+func (schema *Schema) ValidateAny(any jsoniter.Any) error {
+	if err := any.LastError(); err != nil {
+		return err
+	}
+	return schema.validate(anyInstance{any})
+}
+
+// ValidateValue validates a value decoded from JSON, with numbers as json.Number.
+func (schema *Schema) ValidateValue(instance interface{}) error {
+	return schema.validate(valueInstance{instance})
+}
+
+func (schema *Schema) validate(src instance) error {
+	validator := &validator{hooks: schema.hooks}
+	errs, _ := validator.visit([]*node{schema.root}, src, "", false).result(schema.root)
+	if len(errs) != 0 {
+		return ValidationErrors(errs)
+	}
+	return nil
+}
+
+// readValue reads the next value, numbers are read as json.Number to keep their precision
+func readValue(iter *jsoniter.Iterator) interface{} {
+	switch iter.WhatIsNext() {
+	case jsoniter.StringValue:
+		return iter.ReadString()
+	case jsoniter.NumberValue:
+		return iter.ReadNumber()
+	case jsoniter.BoolValue:
+		return iter.ReadBool()
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		return nil
+	case jsoniter.ArrayValue:
+		array := []interface{}{}
+		iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			array = append(array, readValue(iter))
+			return true
+		})
+		return array
+	case jsoniter.ObjectValue:
+		object := map[string]interface{}{}
+		iter.ReadObjectCB(func(iter *jsoniter.Iterator, field string) bool {
+			object[field] = readValue(iter)
+			return true
+		})
+		return object
+	}
+	if iter.Error == nil {
+		iter.ReportError("readValue", "invalid value")
+	} else if iter.Error == io.EOF {
+		iter.Error = errors.New("readValue: input is empty")
+	}
+	return nil
+}
+
+func escapeToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+// typeNames are the JSON Schema types of the values
+var typeNames = map[jsoniter.ValueType]string{
+	jsoniter.InvalidValue: "unknown",
+	jsoniter.StringValue:  "string",
+	jsoniter.NumberValue:  "number",
+	jsoniter.NilValue:     "null",
+	jsoniter.BoolValue:    "boolean",
+	jsoniter.ArrayValue:   "array",
+	jsoniter.ObjectValue:  "object",
+}
@@ -0,0 +1,174 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func locationsOf(err error) []string {
+	errs, _ := err.(ValidationErrors)
+	locations := make([]string, len(errs))
+	for i, validationErr := range errs {
+		locations[i] = validationErr.InstanceLocation
+	}
+	return locations
+}
+
+func Test_validate_keywords(t *testing.T) {
+	should := require.New(t)
+	schema := MustCompile([]byte(`{
+		"type": "object",
+		"required": ["id", "tags"],
+		"properties": {
+			"id": {"type": "integer", "minimum": 1},
+			"price": {"type": "number", "multipleOf": 0.01, "exclusiveMaximum": 100},
+			"name": {"type": "string", "minLength": 2, "pattern": "^[a-z]+$"},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true, "maxItems": 3}
+		}
+	}`))
+	should.NoError(schema.Validate([]byte(`{"id": 10.0, "price": 19.99, "name": "ab", "tags": ["x", "y"]}`)))
+	err := schema.Validate([]byte(`{"id": 0, "price": 0.001, "name": "A", "tags": ["x", 1, "x"]}`))
+	should.Error(err)
+	should.Equal([]string{"/id", "/name", "/name", "/price", "/tags", "/tags/1"}, locationsOf(err))
+	should.Equal("/: missing property \"tags\"", schema.Validate([]byte(`{"id": 1}`)).Error())
+	should.Error(schema.Validate([]byte(`{"id": 1, "tags": []} []`)))
+	should.Error(schema.Validate([]byte(``)))
+	should.Error(schema.Validate([]byte(`{"id": 1,}`)))
+}
+
+func Test_validate_ref(t *testing.T) {
+	should := require.New(t)
+	schema := MustCompile([]byte(`{
+		"$id": "https://example.com/tree",
+		"$defs": {
+			"node": {
+				"type": "object",
+				"properties": {
+					"value": {"$ref": "#/$defs/value"},
+					"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}
+				}
+			},
+			"value": {"$anchor": "value", "type": "integer"}
+		},
+		"$ref": "#/$defs/node"
+	}`))
+	should.NoError(schema.Validate([]byte(`{"value": 1, "children": [{"value": 2, "children": []}]}`)))
+	err := schema.Validate([]byte(`{"value": 1, "children": [{"value": "2"}]}`))
+	should.Equal([]string{"/children/0/value"}, locationsOf(err))
+	should.Equal("https://example.com/tree#/$defs/value/type", err.(ValidationErrors)[0].KeywordLocation)
+
+	compiler := NewCompiler()
+	should.NoError(compiler.AddResource("https://example.com/money", []byte(`{"type": "string", "pattern": "^\\d+\\.\\d{2}$"}`)))
+	schema, err = compiler.Compile([]byte(`{"$id": "https://example.com/order", "properties": {"total": {"$ref": "money"}}}`))
+	should.NoError(err)
+	should.NoError(schema.Validate([]byte(`{"total": "1.00"}`)))
+	should.Error(schema.Validate([]byte(`{"total": "1"}`)))
+
+	_, err = Compile([]byte(`{"$ref": "#/$defs/missing"}`))
+	should.Error(err)
+}
+
+func Test_validate_combinators(t *testing.T) {
+	should := require.New(t)
+	schema := MustCompile([]byte(`{
+		"allOf": [{"type": ["integer", "string"]}],
+		"anyOf": [{"type": "integer", "minimum": 10}, {"type": "string"}],
+		"oneOf": [{"type": "string", "maxLength": 3}, {"type": "integer", "multipleOf": 5}],
+		"not": {"const": "bad"}
+	}`))
+	should.NoError(schema.Validate([]byte(`"abc"`)))
+	should.NoError(schema.Validate([]byte(`50`)))
+	should.Error(schema.Validate([]byte(`5`)))
+	should.Error(schema.Validate([]byte(`"abcd"`)))
+	should.Error(schema.Validate([]byte(`"bad"`)))
+	should.Error(schema.Validate([]byte(`true`)))
+	should.Error(schema.Validate([]byte(`12`)))
+}
+
+func Test_validate_unevaluated_properties(t *testing.T) {
+	should := require.New(t)
+	schema := MustCompile([]byte(`{
+		"properties": {"kind": {"enum": ["card", "cash"]}},
+		"if": {"properties": {"kind": {"const": "card"}}},
+		"then": {"properties": {"number": {"type": "string"}}, "required": ["number"]},
+		"allOf": [{"patternProperties": {"^x-": true}}],
+		"unevaluatedProperties": false
+	}`))
+	should.NoError(schema.Validate([]byte(`{"kind": "card", "number": "4111", "x-note": 1}`)))
+	should.Equal([]string{"/number"}, locationsOf(schema.Validate([]byte(`{"kind": "cash", "number": "4111"}`))))
+	should.True(schema.DisallowUnknownFields())
+
+	items := MustCompile([]byte(`{"prefixItems": [{"type": "integer"}], "unevaluatedItems": {"type": "string"}}`))
+	should.NoError(items.Validate([]byte(`[1, "a", "b"]`)))
+	should.Equal([]string{"/2"}, locationsOf(items.Validate([]byte(`[1, "a", 2]`))))
+	closed := MustCompile([]byte(`{"prefixItems": [{"type": "integer"}], "unevaluatedItems": false}`))
+	should.NoError(closed.Validate([]byte(`[1]`)))
+	should.Equal([]string{"/0", "/0"}, locationsOf(closed.Validate([]byte(`["a"]`))))
+}
+
+func Test_validate_formats(t *testing.T) {
+	should := require.New(t)
+	schema := MustCompile([]byte(`{"properties": {
+		"at": {"format": "date-time"},
+		"mail": {"format": "email"},
+		"ip": {"format": "ipv4"},
+		"id": {"format": "uuid"},
+		"ptr": {"format": "json-pointer"},
+		"custom": {"format": "even"}
+	}}`))
+	should.NoError(schema.Validate([]byte(`{"at": "2020-01-02T03:04:05Z", "mail": "a@example.com",
+		"ip": "127.0.0.1", "id": "123e4567-e89b-12d3-a456-426614174000", "ptr": "/a~1b", "custom": 1}`)))
+	err := schema.Validate([]byte(`{"at": "2020-13-02", "mail": "example.com", "ip": "::1", "id": "x", "ptr": "a"}`))
+	should.Equal([]string{"/at", "/id", "/ip", "/mail", "/ptr"}, locationsOf(err))
+
+	even := schema.WithHooks(Hooks{Formats: map[string]func(value interface{}) bool{
+		"even": func(value interface{}) bool {
+			number, _ := value.(json.Number).Int64()
+			return number%2 == 0
+		},
+	}})
+	should.NoError(even.Validate([]byte(`{"custom": 2}`)))
+	should.Error(even.Validate([]byte(`{"custom": 3}`)))
+}
+
+func Test_validate_hooks(t *testing.T) {
+	should := require.New(t)
+	schema := MustCompile([]byte(`{"properties": {"a": {"properties": {"b": true}}}}`))
+	should.False(schema.DisallowUnknownFields())
+	should.NoError(schema.Validate([]byte(`{"a": {"c": 1}}`)))
+	strict := schema.WithHooks(Hooks{UnknownProperty: RejectUnknownProperties})
+	err := strict.Validate([]byte(`{"a": {"c": 1}, "d": 1}`))
+	should.Equal([]string{"/a/c", "/d"}, locationsOf(err))
+
+	closed := MustCompile([]byte(`{"properties": {"Name": {"type": "string"}}, "additionalProperties": false}`))
+	should.Error(closed.Validate([]byte(`{"Name": "x", "Age": 1}`)))
+	var target struct {
+		Name string
+	}
+	should.Error(closed.Config(jsoniter.Config{}).Unmarshal([]byte(`{"Name": "x", "Age": 1}`), &target))
+}
+
+func Test_validate_iterator_and_any(t *testing.T) {
+	should := require.New(t)
+	schema := MustCompile([]byte(`{"type": "object", "required": ["a"]}`))
+	iter := jsoniter.ParseString(jsoniter.ConfigDefault, `{"a": 1} {"b": 2}`)
+	should.NoError(schema.ValidateIterator(iter))
+	should.Equal([]string{""}, locationsOf(schema.ValidateIterator(iter)))
+	should.NoError(schema.ValidateAny(jsoniter.Get([]byte(`{"a": [1]}`))))
+	should.NoError(schema.ValidateAny(jsoniter.Wrap(map[string]int{"a": 1})))
+	should.Error(schema.ValidateAny(jsoniter.Get([]byte(`[1]`))))
+
+	nested := MustCompile([]byte(`{"properties": {"items": {"items": {"enum": [1, [2]]}, "uniqueItems": true}}}`))
+	reader := jsoniter.Parse(jsoniter.ConfigDefault, strings.NewReader(`{"skipped": {"a": [1, 2]}, "items": [1, [2]]}`), 4)
+	should.NoError(nested.ValidateIterator(reader))
+	reader = jsoniter.Parse(jsoniter.ConfigDefault, strings.NewReader(`{"items": [[2], 3, [2]]}`), 4)
+	should.Equal([]string{"/items", "/items/1"}, locationsOf(nested.ValidateIterator(reader)))
+	should.Equal([]string{"/items", "/items/1"}, locationsOf(nested.ValidateAny(jsoniter.Get([]byte(`{"items": [[2], 3, [2]]}`)))))
+	should.Equal([]string{"/items/0"}, locationsOf(nested.ValidateValue(map[string]interface{}{
+		"items": []interface{}{json.Number("2")},
+	})))
+}
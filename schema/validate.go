@@ -0,0 +1,581 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/json-iterator/go"
+)
+
+type validator struct {
+	hooks Hooks
+}
+
+// evaluation records the members and elements evaluated by successful subschemas, for unevaluated keywords
+type evaluation struct {
+	properties map[string]bool
+	items      map[int]bool
+	allItems   bool
+}
+
+func (eval *evaluation) merge(other *evaluation) {
+	if other == nil {
+		return
+	}
+	for name := range other.properties {
+		eval.properties[name] = true
+	}
+	for index := range other.items {
+		eval.items[index] = true
+	}
+	eval.allItems = eval.allItems || other.allItems
+}
+
+// visit is one instance read from the input, with the results of the schemas applying to it
+type visit struct {
+	validator *validator
+	location  string
+	kind      jsoniter.ValueType
+	value     interface{} // the scalar, or the whole value if a keyword needs it
+	elements  []*visit
+	names     []string // member names sorted, members are in the same order
+	members   []*visit
+	results   map[*node]*result
+}
+
+type result struct {
+	errs []*ValidationError
+	eval *evaluation
+}
+
+// visit reads the instance once, for the schemas of nodes and the ones they apply to the same instance.
+// The results of nodes are computed as soon as the instance is read, and the children are released.
+// The value is kept if keepValue is set, for uniqueItems of the parent.
+func (v *validator) visit(nodes []*node, src instance, location string, keepValue bool) *visit {
+	visited := &visit{validator: v, location: location, results: map[*node]*result{}}
+	var closure []*node
+	seen := map[*node]bool{}
+	for _, n := range nodes {
+		closure = appendInPlace(closure, seen, n)
+	}
+	if len(closure) == 0 && !keepValue {
+		src.skip()
+		return visited
+	}
+	needValue := keepValue
+	for _, n := range closure {
+		needValue = needValue || n.enum != nil || n.hasConst || n.format != ""
+	}
+	if needValue {
+		visited.value = decodeInstance(src)
+		src = valueInstance{visited.value}
+	}
+	visited.kind = src.valueType()
+	switch visited.kind {
+	case jsoniter.ArrayValue:
+		src.elements(func(index int, element instance) {
+			var sub []*node
+			keepElement := false
+			for _, n := range closure {
+				if index < len(n.prefixItems) {
+					sub = append(sub, n.prefixItems[index])
+				} else if n.items != nil {
+					sub = append(sub, n.items)
+				}
+				if n.contains != nil {
+					sub = append(sub, n.contains)
+				}
+				if n.unevaluatedItems != nil {
+					sub = append(sub, n.unevaluatedItems)
+				}
+				keepElement = keepElement || n.uniqueItems
+			}
+			child := v.visit(sub, element, location+"/"+strconv.Itoa(index), keepElement)
+			visited.elements = append(visited.elements, child.finish(sub, keepElement))
+		})
+	case jsoniter.ObjectValue:
+		src.members(func(name string, member instance) {
+			var sub []*node
+			for _, n := range closure {
+				declared := false
+				if property, found := n.properties[name]; found {
+					declared = true
+					sub = append(sub, property)
+				}
+				for pattern, property := range n.patternProperties {
+					if pattern.MatchString(name) {
+						declared = true
+						sub = append(sub, property)
+					}
+				}
+				if !declared && n.additionalProperties != nil {
+					sub = append(sub, n.additionalProperties)
+				}
+				if n.unevaluatedProperties != nil {
+					sub = append(sub, n.unevaluatedProperties)
+				}
+			}
+			child := v.visit(sub, member, location+"/"+escapeToken(name), false)
+			visited.names = append(visited.names, name)
+			visited.members = append(visited.members, child.finish(sub, false))
+		})
+		sort.Stable(byName{visited})
+	default:
+		if !needValue {
+			visited.value = src.scalar()
+		}
+	}
+	return visited
+}
+
+// appendInPlace appends n and the subschemas applying to the same instance as n, once each
+func appendInPlace(closure []*node, seen map[*node]bool, n *node) []*node {
+	if n == nil || seen[n] {
+		return closure
+	}
+	seen[n] = true
+	closure = append(closure, n)
+	closure = appendInPlace(closure, seen, n.ref)
+	for _, subs := range [][]*node{n.allOf, n.anyOf, n.oneOf, {n.not, n.ifNode, n.thenNode, n.elseNode}} {
+		for _, sub := range subs {
+			closure = appendInPlace(closure, seen, sub)
+		}
+	}
+	for _, sub := range n.dependentSchemas {
+		closure = appendInPlace(closure, seen, sub)
+	}
+	return closure
+}
+
+// finish computes the results of nodes, and releases what was read except the value if keepValue is set
+func (visited *visit) finish(nodes []*node, keepValue bool) *visit {
+	for _, n := range nodes {
+		visited.result(n)
+	}
+	visited.elements = nil
+	visited.names = nil
+	visited.members = nil
+	if !keepValue {
+		visited.value = nil
+	}
+	return visited
+}
+
+// member returns the visit of the member name, nil if not found
+func (visited *visit) member(name string) *visit {
+	i := sort.SearchStrings(visited.names, name)
+	if i < len(visited.names) && visited.names[i] == name {
+		return visited.members[i]
+	}
+	return nil
+}
+
+type byName struct {
+	visited *visit
+}
+
+func (members byName) Len() int {
+	return len(members.visited.names)
+}
+
+func (members byName) Less(i, j int) bool {
+	return members.visited.names[i] < members.visited.names[j]
+}
+
+func (members byName) Swap(i, j int) {
+	names, visits := members.visited.names, members.visited.members
+	names[i], names[j] = names[j], names[i]
+	visits[i], visits[j] = visits[j], visits[i]
+}
+
+func (visited *visit) result(n *node) ([]*ValidationError, *evaluation) {
+	if cached, found := visited.results[n]; found {
+		return cached.errs, cached.eval
+	}
+	// a schema applying itself to the same instance is not evaluated again
+	visited.results[n] = &result{}
+	errs, eval := visited.validate(n)
+	visited.results[n] = &result{errs, eval}
+	return errs, eval
+}
+
+func (visited *visit) validate(n *node) ([]*ValidationError, *evaluation) {
+	v := visited.validator
+	location := visited.location
+	eval := &evaluation{properties: map[string]bool{}, items: map[int]bool{}}
+	if n.always != nil {
+		if *n.always {
+			return nil, eval
+		}
+		return []*ValidationError{{location, n.location, "false schema does not allow any value"}}, eval
+	}
+	var errs []*ValidationError
+	fail := func(keyword string, format string, args ...interface{}) {
+		errs = append(errs, &ValidationError{location, n.location + "/" + keyword, fmt.Sprintf(format, args...)})
+	}
+	// applicators on the same instance contribute to the evaluation when they succeed
+	apply := func(sub *node) bool {
+		subErrs, subEval := visited.result(sub)
+		if len(subErrs) == 0 {
+			eval.merge(subEval)
+		}
+		return len(subErrs) == 0
+	}
+	if n.ref != nil {
+		subErrs, subEval := visited.result(n.ref)
+		errs = append(errs, subErrs...)
+		if len(subErrs) == 0 {
+			eval.merge(subEval)
+		}
+	}
+	v.validateType(n, visited, fail)
+	if n.enum != nil {
+		found := false
+		for _, value := range n.enum {
+			if deepEqual(value, visited.value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("enum", "value is not one of enum")
+		}
+	}
+	if n.hasConst && !deepEqual(n.constValue, visited.value) {
+		fail("const", "value is not const")
+	}
+	switch visited.kind {
+	case jsoniter.NumberValue:
+		v.validateNumber(n, visited.value.(json.Number), fail)
+	case jsoniter.StringValue:
+		v.validateString(n, visited.value.(string), fail)
+	case jsoniter.ArrayValue:
+		errs = append(errs, visited.validateArray(n, eval, fail)...)
+	case jsoniter.ObjectValue:
+		errs = append(errs, visited.validateObject(n, eval, fail)...)
+	}
+	if n.format != "" {
+		checker := v.hooks.Formats[n.format]
+		if checker == nil {
+			checker = formats[n.format]
+		}
+		if checker != nil && !checker(visited.value) {
+			fail("format", "value is not valid %s", n.format)
+		}
+	}
+	for _, sub := range n.allOf {
+		subErrs, subEval := visited.result(sub)
+		errs = append(errs, subErrs...)
+		if len(subErrs) == 0 {
+			eval.merge(subEval)
+		}
+	}
+	if n.anyOf != nil {
+		matched := false
+		for _, sub := range n.anyOf {
+			// all subschemas are evaluated, to collect their annotations
+			if apply(sub) {
+				matched = true
+			}
+		}
+		if !matched {
+			fail("anyOf", "value does not match any schema")
+		}
+	}
+	if n.oneOf != nil {
+		var matched []int
+		var matchedEval *evaluation
+		for i, sub := range n.oneOf {
+			subErrs, subEval := visited.result(sub)
+			if len(subErrs) == 0 {
+				matched = append(matched, i)
+				matchedEval = subEval
+			}
+		}
+		switch len(matched) {
+		case 0:
+			fail("oneOf", "value does not match any schema")
+		case 1:
+			eval.merge(matchedEval)
+		default:
+			fail("oneOf", "value matches schemas %d and %d, only one is allowed", matched[0], matched[1])
+		}
+	}
+	if n.not != nil {
+		if subErrs, _ := visited.result(n.not); len(subErrs) == 0 {
+			fail("not", "value must not match schema")
+		}
+	}
+	if n.ifNode != nil {
+		if apply(n.ifNode) {
+			if n.thenNode != nil {
+				subErrs, subEval := visited.result(n.thenNode)
+				errs = append(errs, subErrs...)
+				if len(subErrs) == 0 {
+					eval.merge(subEval)
+				}
+			}
+		} else if n.elseNode != nil {
+			subErrs, subEval := visited.result(n.elseNode)
+			errs = append(errs, subErrs...)
+			if len(subErrs) == 0 {
+				eval.merge(subEval)
+			}
+		}
+	}
+	switch visited.kind {
+	case jsoniter.ArrayValue:
+		if n.unevaluatedItems != nil && !eval.allItems {
+			for i, element := range visited.elements {
+				if eval.items[i] {
+					continue
+				}
+				subErrs, _ := element.result(n.unevaluatedItems)
+				errs = append(errs, subErrs...)
+			}
+			eval.allItems = true
+		}
+	case jsoniter.ObjectValue:
+		if n.unevaluatedProperties != nil {
+			for i, name := range visited.names {
+				if eval.properties[name] {
+					continue
+				}
+				subErrs, _ := visited.members[i].result(n.unevaluatedProperties)
+				errs = append(errs, subErrs...)
+				eval.properties[name] = true
+			}
+		}
+	}
+	return errs, eval
+}
+
+func (v *validator) validateType(n *node, visited *visit, fail func(string, string, ...interface{})) {
+	if n.types == nil {
+		return
+	}
+	actual := typeNames[visited.kind]
+	for _, typ := range n.types {
+		if typ == actual {
+			return
+		}
+		if typ == "integer" && actual == "number" {
+			if rat, ok := new(big.Rat).SetString(string(visited.value.(json.Number))); ok && rat.IsInt() {
+				return
+			}
+		}
+	}
+	if len(n.types) == 1 {
+		fail("type", "expected %s, but got %s", n.types[0], actual)
+	} else {
+		fail("type", "expected one of %v, but got %s", n.types, actual)
+	}
+}
+
+func (v *validator) validateNumber(n *node, value json.Number, fail func(string, string, ...interface{})) {
+	rat, ok := new(big.Rat).SetString(string(value))
+	if !ok {
+		fail("type", "invalid number %s", value)
+		return
+	}
+	if n.minimum != nil && rat.Cmp(n.minimum) < 0 {
+		fail("minimum", "%s is less than %s", value, n.minimum.RatString())
+	}
+	if n.maximum != nil && rat.Cmp(n.maximum) > 0 {
+		fail("maximum", "%s is greater than %s", value, n.maximum.RatString())
+	}
+	if n.exclusiveMinimum != nil && rat.Cmp(n.exclusiveMinimum) <= 0 {
+		fail("exclusiveMinimum", "%s is not greater than %s", value, n.exclusiveMinimum.RatString())
+	}
+	if n.exclusiveMaximum != nil && rat.Cmp(n.exclusiveMaximum) >= 0 {
+		fail("exclusiveMaximum", "%s is not less than %s", value, n.exclusiveMaximum.RatString())
+	}
+	if n.multipleOf != nil && !new(big.Rat).Quo(rat, n.multipleOf).IsInt() {
+		fail("multipleOf", "%s is not multiple of %s", value, n.multipleOf.RatString())
+	}
+}
+
+func (v *validator) validateString(n *node, value string, fail func(string, string, ...interface{})) {
+	length := utf8.RuneCountInString(value)
+	if n.minLength != -1 && length < n.minLength {
+		fail("minLength", "length %d is less than %d", length, n.minLength)
+	}
+	if n.maxLength != -1 && length > n.maxLength {
+		fail("maxLength", "length %d is greater than %d", length, n.maxLength)
+	}
+	if n.pattern != nil && !n.pattern.MatchString(value) {
+		fail("pattern", "%q does not match pattern %q", value, n.pattern.String())
+	}
+}
+
+func (visited *visit) validateArray(n *node, eval *evaluation, fail func(string, string, ...interface{})) []*ValidationError {
+	var errs []*ValidationError
+	elements := visited.elements
+	if n.minItems != -1 && len(elements) < n.minItems {
+		fail("minItems", "%d items is less than %d", len(elements), n.minItems)
+	}
+	if n.maxItems != -1 && len(elements) > n.maxItems {
+		fail("maxItems", "%d items is greater than %d", len(elements), n.maxItems)
+	}
+	if n.uniqueItems {
+	unique:
+		for i := range elements {
+			for j := i + 1; j < len(elements); j++ {
+				if deepEqual(elements[i].value, elements[j].value) {
+					fail("uniqueItems", "items %d and %d are equal", i, j)
+					break unique
+				}
+			}
+		}
+	}
+	for i, sub := range n.prefixItems {
+		if i >= len(elements) {
+			break
+		}
+		subErrs, _ := elements[i].result(sub)
+		errs = append(errs, subErrs...)
+		if len(subErrs) == 0 {
+			eval.items[i] = true
+		}
+	}
+	if n.items != nil {
+		for i := len(n.prefixItems); i < len(elements); i++ {
+			subErrs, _ := elements[i].result(n.items)
+			errs = append(errs, subErrs...)
+		}
+		eval.allItems = true
+	}
+	if n.contains != nil {
+		count := 0
+		for i, element := range elements {
+			if subErrs, _ := element.result(n.contains); len(subErrs) == 0 {
+				count++
+				eval.items[i] = true
+			}
+		}
+		if count < n.minContains {
+			fail("contains", "%d items match contains, expected at least %d", count, n.minContains)
+		}
+		if n.maxContains != -1 && count > n.maxContains {
+			fail("maxContains", "%d items match contains, expected at most %d", count, n.maxContains)
+		}
+	}
+	return errs
+}
+
+func (visited *visit) validateObject(n *node, eval *evaluation, fail func(string, string, ...interface{})) []*ValidationError {
+	var errs []*ValidationError
+	v := visited.validator
+	location := visited.location
+	if n.minProperties != -1 && len(visited.names) < n.minProperties {
+		fail("minProperties", "%d properties is less than %d", len(visited.names), n.minProperties)
+	}
+	if n.maxProperties != -1 && len(visited.names) > n.maxProperties {
+		fail("maxProperties", "%d properties is greater than %d", len(visited.names), n.maxProperties)
+	}
+	for _, name := range n.required {
+		if visited.member(name) == nil {
+			fail("required", "missing property %q", name)
+		}
+	}
+	for name, dependencies := range n.dependentRequired {
+		if visited.member(name) == nil {
+			continue
+		}
+		for _, dependency := range dependencies {
+			if visited.member(dependency) == nil {
+				fail("dependentRequired/"+escapeToken(name), "missing property %q required by %q", dependency, name)
+			}
+		}
+	}
+	for name, sub := range n.dependentSchemas {
+		if visited.member(name) == nil {
+			continue
+		}
+		subErrs, subEval := visited.result(sub)
+		errs = append(errs, subErrs...)
+		if len(subErrs) == 0 {
+			eval.merge(subEval)
+		}
+	}
+	for i, name := range visited.names {
+		member := visited.members[i]
+		memberLocation := location + "/" + escapeToken(name)
+		if n.propertyNames != nil {
+			nameVisit := v.visit([]*node{n.propertyNames}, valueInstance{name}, memberLocation, false)
+			if subErrs, _ := nameVisit.result(n.propertyNames); len(subErrs) != 0 {
+				fail("propertyNames", "invalid property name %q", name)
+			}
+		}
+		declared := false
+		if sub, found := n.properties[name]; found {
+			declared = true
+			subErrs, _ := member.result(sub)
+			errs = append(errs, subErrs...)
+		}
+		for pattern, sub := range n.patternProperties {
+			if pattern.MatchString(name) {
+				declared = true
+				subErrs, _ := member.result(sub)
+				errs = append(errs, subErrs...)
+			}
+		}
+		if declared {
+			eval.properties[name] = true
+			continue
+		}
+		if n.additionalProperties != nil {
+			subErrs, _ := member.result(n.additionalProperties)
+			errs = append(errs, subErrs...)
+			eval.properties[name] = true
+			continue
+		}
+		if n.properties != nil && v.hooks.UnknownProperty != nil && !v.hooks.UnknownProperty(location, name) {
+			errs = append(errs, &ValidationError{memberLocation, n.location + "/properties",
+				fmt.Sprintf("unknown property %q", name)})
+		}
+	}
+	return errs
+}
+
+// deepEqual compares JSON values, numbers are equal by their mathematical value
+func deepEqual(a interface{}, b interface{}) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		ratA, okA := new(big.Rat).SetString(string(a))
+		ratB, okB := new(big.Rat).SetString(string(b))
+		return okA && okB && ratA.Cmp(ratB) == 0
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !deepEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, value := range a {
+			other, found := b[key]
+			if !found || !deepEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
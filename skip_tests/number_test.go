@@ -0,0 +1,15 @@
+package skip_tests
+
+import "encoding/json"
+
+func init() {
+	testCases = append(testCases, testCase{
+		ptr: (*json.Number)(nil),
+		inputs: []string{
+			"1e400",  // valid, out of float64 range
+			"-1e400", // valid, out of float64 range
+			"1e-400", // valid
+			"1e",     // invalid
+		},
+	})
+}
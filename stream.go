@@ -1,6 +1,8 @@
 package jsoniter
 
 import (
+	"bytes"
+	"encoding/json"
 	"io"
 )
 
@@ -12,6 +14,8 @@ type Stream struct {
 	buf        []byte
 	Error      error
 	indention  int
+	flushAt    int         // flush to out once buf holds flushAt bytes, 0 if only flushed by Flush
+	outErr     error       // error of out when flushed by flushAt
 	Attachment interface{} // open for customized encoder
 }
 
@@ -95,6 +99,38 @@ func (stream *Stream) writeFiveBytes(c1 byte, c2 byte, c3 byte, c4 byte, c5 byte
 	stream.buf = append(stream.buf, c1, c2, c3, c4, c5)
 }
 
+// SetFlushThreshold makes the stream flush to the underlying io.Writer whenever the buffer holds threshold bytes
+// or more between two writes, so the memory used by a large output stays bounded.
+// A threshold of 0 disables it, the buffer is then only written by Flush.
+// It has no effect with Config.Canonical, the value is rewritten once complete.
+// Once the writer failed, the output written after is discarded instead of buffered.
+func (stream *Stream) SetFlushThreshold(threshold int) {
+	stream.flushAt = threshold
+}
+
+func (stream *Stream) flushIfFull() {
+	// canonical form is only written once the value is complete
+	if stream.flushAt > 0 && len(stream.buf) >= stream.flushAt && stream.out != nil && !stream.cfg.canonical {
+		stream.autoFlush()
+	}
+}
+
+func (stream *Stream) autoFlush() {
+	if stream.Error == nil {
+		if _, err := stream.out.Write(stream.buf); err != nil {
+			stream.Error = err
+			stream.outErr = err
+		}
+	}
+	stream.buf = stream.buf[:0]
+}
+
+// hasValueError tells if the stream failed to encode a value, rather than to write to the underlying io.Writer.
+// Only the errors of values are prefixed with the path of the value by the encoders.
+func (stream *Stream) hasValueError() bool {
+	return stream.Error != nil && stream.Error != io.EOF && stream.Error != stream.outErr
+}
+
 // Flush writes any buffered data to the underlying io.Writer.
 func (stream *Stream) Flush() error {
 	if stream.out == nil {
@@ -116,21 +152,25 @@ func (stream *Stream) Flush() error {
 
 // WriteRaw write string out without quotes, just like []byte
 func (stream *Stream) WriteRaw(s string) {
+	stream.flushIfFull()
 	stream.buf = append(stream.buf, s...)
 }
 
 // WriteNil write null to stream
 func (stream *Stream) WriteNil() {
+	stream.flushIfFull()
 	stream.writeFourBytes('n', 'u', 'l', 'l')
 }
 
 // WriteTrue write true to stream
 func (stream *Stream) WriteTrue() {
+	stream.flushIfFull()
 	stream.writeFourBytes('t', 'r', 'u', 'e')
 }
 
 // WriteFalse write false to stream
 func (stream *Stream) WriteFalse() {
+	stream.flushIfFull()
 	stream.writeFiveBytes('f', 'a', 'l', 's', 'e')
 }
 
@@ -162,7 +202,9 @@ func (stream *Stream) WriteObjectField(field string) {
 
 // WriteObjectEnd write } with possible indention
 func (stream *Stream) WriteObjectEnd() {
-	stream.writeIndention(stream.cfg.indentionStep)
+	if !stream.trimEmptyIndention('{') {
+		stream.writeIndention(stream.cfg.indentionStep)
+	}
 	stream.indention -= stream.cfg.indentionStep
 	stream.writeByte('}')
 }
@@ -177,6 +219,7 @@ func (stream *Stream) WriteEmptyObject() {
 func (stream *Stream) WriteMore() {
 	stream.writeByte(',')
 	stream.writeIndention(0)
+	stream.flushIfFull()
 }
 
 // WriteArrayStart write [ with possible indention
@@ -193,7 +236,9 @@ func (stream *Stream) WriteEmptyArray() {
 
 // WriteArrayEnd write ] with possible indention
 func (stream *Stream) WriteArrayEnd() {
-	stream.writeIndention(stream.cfg.indentionStep)
+	if !stream.trimEmptyIndention('[') {
+		stream.writeIndention(stream.cfg.indentionStep)
+	}
 	stream.indention -= stream.cfg.indentionStep
 	stream.writeByte(']')
 }
@@ -203,8 +248,54 @@ func (stream *Stream) writeIndention(delta int) {
 		return
 	}
 	stream.writeByte('\n')
+	stream.buf = append(stream.buf, stream.cfg.indentPrefix...)
 	toWrite := stream.indention - delta
 	for i := 0; i < toWrite; i++ {
-		stream.buf = append(stream.buf, ' ')
+		stream.buf = append(stream.buf, stream.cfg.indent...)
+	}
+}
+
+// trimEmptyIndention removes the indention written right after open,
+// so that empty object and array are written as {} and [] like json.MarshalIndent
+func (stream *Stream) trimEmptyIndention(open byte) bool {
+	if stream.indention == 0 {
+		return false
+	}
+	written := 1 + len(stream.cfg.indentPrefix) + len(stream.cfg.indent)*stream.indention
+	start := len(stream.buf) - written
+	if start < 1 || stream.buf[start-1] != open || stream.buf[start] != '\n' {
+		return false
+	}
+	line := stream.buf[start+1:]
+	if string(line[:len(stream.cfg.indentPrefix)]) != stream.cfg.indentPrefix {
+		return false
+	}
+	line = line[len(stream.cfg.indentPrefix):]
+	for i := 0; i < stream.indention; i++ {
+		if string(line[:len(stream.cfg.indent)]) != stream.cfg.indent {
+			return false
+		}
+		line = line[len(stream.cfg.indent):]
+	}
+	stream.buf = stream.buf[:start]
+	return true
+}
+
+// writeRawValue writes JSON produced outside of stream, such as json.Marshaler output and RawMessage.
+// When indention is enabled, the value is re-indented at the current level like json.MarshalIndent.
+func (stream *Stream) writeRawValue(raw []byte) {
+	if stream.cfg.indentionStep == 0 {
+		stream.buf = append(stream.buf, raw...)
+		return
+	}
+	prefix := stream.cfg.indentPrefix
+	for i := 0; i < stream.indention; i++ {
+		prefix += stream.cfg.indent
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, raw, prefix, stream.cfg.indent); err != nil {
+		stream.buf = append(stream.buf, raw...)
+		return
 	}
+	stream.buf = append(stream.buf, indented.Bytes()...)
 }
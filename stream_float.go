@@ -14,6 +14,7 @@ func init() {
 
 // WriteFloat32 write float32 to stream
 func (stream *Stream) WriteFloat32(val float32) {
+	stream.flushIfFull()
 	if math.IsInf(float64(val), 0) || math.IsNaN(float64(val)) {
 		stream.Error = fmt.Errorf("unsupported value: %f", val)
 		return
@@ -39,6 +40,7 @@ func (stream *Stream) WriteFloat32(val float32) {
 
 // WriteFloat32Lossy write float32 to stream with ONLY 6 digits precision although much much faster
 func (stream *Stream) WriteFloat32Lossy(val float32) {
+	stream.flushIfFull()
 	if math.IsInf(float64(val), 0) || math.IsNaN(float64(val)) {
 		stream.Error = fmt.Errorf("unsupported value: %f", val)
 		return
@@ -71,6 +73,7 @@ func (stream *Stream) WriteFloat32Lossy(val float32) {
 
 // WriteFloat64 write float64 to stream
 func (stream *Stream) WriteFloat64(val float64) {
+	stream.flushIfFull()
 	if math.IsInf(val, 0) || math.IsNaN(val) {
 		stream.Error = fmt.Errorf("unsupported value: %f", val)
 		return
@@ -96,6 +99,7 @@ func (stream *Stream) WriteFloat64(val float64) {
 
 // WriteFloat64Lossy write float64 to stream with ONLY 6 digits precision although much much faster
 func (stream *Stream) WriteFloat64Lossy(val float64) {
+	stream.flushIfFull()
 	if math.IsInf(val, 0) || math.IsNaN(val) {
 		stream.Error = fmt.Errorf("unsupported value: %f", val)
 		return
@@ -31,11 +31,13 @@ func writeBuf(buf []byte, v uint32) []byte {
 
 // WriteUint8 write uint8 to stream
 func (stream *Stream) WriteUint8(val uint8) {
+	stream.flushIfFull()
 	stream.buf = writeFirstBuf(stream.buf, digits[val])
 }
 
 // WriteInt8 write int8 to stream
 func (stream *Stream) WriteInt8(nval int8) {
+	stream.flushIfFull()
 	var val uint8
 	if nval < 0 {
 		val = uint8(-nval)
@@ -48,6 +50,7 @@ func (stream *Stream) WriteInt8(nval int8) {
 
 // WriteUint16 write uint16 to stream
 func (stream *Stream) WriteUint16(val uint16) {
+	stream.flushIfFull()
 	q1 := val / 1000
 	if q1 == 0 {
 		stream.buf = writeFirstBuf(stream.buf, digits[val])
@@ -61,6 +64,7 @@ func (stream *Stream) WriteUint16(val uint16) {
 
 // WriteInt16 write int16 to stream
 func (stream *Stream) WriteInt16(nval int16) {
+	stream.flushIfFull()
 	var val uint16
 	if nval < 0 {
 		val = uint16(-nval)
@@ -73,6 +77,7 @@ func (stream *Stream) WriteInt16(nval int16) {
 
 // WriteUint32 write uint32 to stream
 func (stream *Stream) WriteUint32(val uint32) {
+	stream.flushIfFull()
 	q1 := val / 1000
 	if q1 == 0 {
 		stream.buf = writeFirstBuf(stream.buf, digits[val])
@@ -100,6 +105,7 @@ func (stream *Stream) WriteUint32(val uint32) {
 
 // WriteInt32 write int32 to stream
 func (stream *Stream) WriteInt32(nval int32) {
+	stream.flushIfFull()
 	var val uint32
 	if nval < 0 {
 		val = uint32(-nval)
@@ -112,6 +118,7 @@ func (stream *Stream) WriteInt32(nval int32) {
 
 // WriteUint64 write uint64 to stream
 func (stream *Stream) WriteUint64(val uint64) {
+	stream.flushIfFull()
 	q1 := val / 1000
 	if q1 == 0 {
 		stream.buf = writeFirstBuf(stream.buf, digits[val])
@@ -169,6 +176,7 @@ func (stream *Stream) WriteUint64(val uint64) {
 
 // WriteInt64 write int64 to stream
 func (stream *Stream) WriteInt64(nval int64) {
+	stream.flushIfFull()
 	var val uint64
 	if nval < 0 {
 		val = uint64(-nval)
@@ -219,6 +219,7 @@ var hex = "0123456789abcdef"
 
 // WriteStringWithHTMLEscaped write string to stream with html special characters escaped
 func (stream *Stream) WriteStringWithHTMLEscaped(s string) {
+	stream.flushIfFull()
 	valLen := len(s)
 	stream.buf = append(stream.buf, '"')
 	// write string, the fast path, without utf8 and escape support
@@ -309,6 +310,7 @@ func writeStringSlowPathWithHTMLEscaped(stream *Stream, i int, s string, valLen
 
 // WriteString write string to stream without html escape
 func (stream *Stream) WriteString(s string) {
+	stream.flushIfFull()
 	valLen := len(s)
 	stream.buf = append(stream.buf, '"')
 	// write string, the fast path, without utf8 and escape support
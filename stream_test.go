@@ -1,6 +1,9 @@
 package jsoniter
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -84,3 +87,66 @@ func Test_flush_buffer_should_stop_grow_buffer(t *testing.T) {
 	// is ever used, and it is never extended. Capacity remains 512.
 	should.Equal(512, writer.bufferSize)
 }
+
+func Test_flush_threshold_should_stop_grow_buffer(t *testing.T) {
+	should := require.New(t)
+	type item struct {
+		ID   int
+		Tags []string
+		Meta map[string]float64
+	}
+	items := make([]item, 20000)
+	for i := range items {
+		items[i] = item{ID: i, Tags: []string{"a", "b"}, Meta: map[string]float64{"x": 1.5}}
+	}
+	expected, err := Marshal(items)
+	should.NoError(err)
+
+	var output bytes.Buffer
+	stream := NewStream(ConfigDefault, &output, 512)
+	stream.SetFlushThreshold(512)
+	stream.WriteVal(items)
+	should.NoError(stream.Flush())
+	should.Equal(string(expected), output.String())
+	should.True(cap(stream.buf) < 1024, "buffer grew to %d", cap(stream.buf))
+
+	// the whole output is buffered without threshold
+	output.Reset()
+	stream = NewStream(ConfigDefault, &output, 512)
+	stream.WriteVal(items)
+	should.Equal(0, output.Len())
+	should.NoError(stream.Flush())
+	should.Equal(string(expected), output.String())
+}
+
+func Test_flush_threshold_keeps_empty_indention(t *testing.T) {
+	should := require.New(t)
+	var output bytes.Buffer
+	stream := NewStream(Config{IndentionStep: 2, SortMapKeys: true}.Froze(), &output, 16)
+	stream.SetFlushThreshold(1)
+	stream.WriteVal(map[string]interface{}{"a": []int{}, "b": map[string]int{}, "c": []float64{1.5, 2}})
+	should.NoError(stream.Flush())
+	expected, err := json.MarshalIndent(map[string]interface{}{"a": []int{}, "b": map[string]int{}, "c": []float64{1.5, 2}}, "", "  ")
+	should.NoError(err)
+	should.Equal(string(expected), output.String())
+}
+
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (n int, err error) {
+	w.writes++
+	return 0, errors.New("write failed")
+}
+
+func Test_flush_threshold_discards_output_after_write_error(t *testing.T) {
+	should := require.New(t)
+	writer := &failingWriter{}
+	stream := NewStream(ConfigDefault, writer, 64)
+	stream.SetFlushThreshold(64)
+	stream.WriteVal(make([]int, 100000))
+	should.EqualError(stream.Error, "write failed")
+	should.Equal(1, writer.writes)
+	should.True(cap(stream.buf) < 256, "buffer grew to %d", cap(stream.buf))
+}